@@ -21,6 +21,8 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/containeroo/tinyflags"
 
@@ -29,12 +31,19 @@ import (
 	"github.com/thurgauerkb/cascader/internal/kinds"
 	"github.com/thurgauerkb/cascader/internal/logging"
 	internalmetrics "github.com/thurgauerkb/cascader/internal/metrics"
+	"github.com/thurgauerkb/cascader/internal/promquery"
+	"github.com/thurgauerkb/cascader/internal/readycheck"
 	"github.com/thurgauerkb/cascader/internal/utils"
+	"github.com/thurgauerkb/cascader/internal/workloads"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -73,6 +82,8 @@ func Run(ctx context.Context, version string, args []string, stdOut, stdErr io.W
 		"DaemonSet":           flags.DaemonSetAnnotation,
 		"Deployment":          flags.DeploymentAnnotation,
 		"StatefulSet":         flags.StatefulSetAnnotation,
+		"Job":                 flags.JobAnnotation,
+		"CronJob":             flags.CronJobAnnotation,
 		"LastObservedRestart": flags.LastObservedRestartAnnotation,
 		"RequeueAfter":        flags.RequeueAfterAnnotation,
 	}
@@ -113,8 +124,17 @@ func Run(ctx context.Context, version string, args []string, stdOut, stdErr io.W
 		}
 	}
 
+	// ConfigMap defining named target groups, if configured
+	var targetGroups types.NamespacedName
+	if flags.TargetGroupsConfigMap != "" {
+		targetGroups.Namespace, targetGroups.Name, _ = strings.Cut(flags.TargetGroupsConfigMap, "/")
+	}
+
 	// Create Cache Options
 	cacheOpts := utils.ToCacheOptions(flags.WatchNamespaces)
+	if targetGroups.Name != "" {
+		cacheOpts = utils.WithConfigMap(cacheOpts, targetGroups)
+	}
 
 	// Create and initialize the manager
 	cfg, err := ctrl.GetConfig()
@@ -139,6 +159,14 @@ func Run(ctx context.Context, version string, args []string, stdOut, stdErr io.W
 		return err
 	}
 
+	// Watch the target groups ConfigMap, so that it is cached before the first cascade resolves its targets
+	if targetGroups.Name != "" {
+		if _, err := mgr.GetCache().GetInformer(ctx, &corev1.ConfigMap{}); err != nil {
+			setupLog.Error(err, "unable to watch target groups", "configMap", targetGroups)
+			return err
+		}
+	}
+
 	// Log watching namespaces
 	if len(flags.WatchNamespaces) == 0 {
 		setupLog.Info("namespace scope", "mode", "cluster-wide")
@@ -151,20 +179,75 @@ func Run(ctx context.Context, version string, args []string, stdOut, stdErr io.W
 		flags.DaemonSetAnnotation:   kinds.DaemonSetKind,
 		flags.DeploymentAnnotation:  kinds.DeploymentKind,
 		flags.StatefulSetAnnotation: kinds.StatefulSetKind,
+		flags.JobAnnotation:         kinds.JobKind,
+		flags.CronJobAnnotation:     kinds.CronJobKind,
+	}
+
+	// Queues used to resume in-flight cascades after a restart or leader failover
+	resumeQueues := map[kinds.Kind]chan event.GenericEvent{
+		kinds.DeploymentKind:  make(chan event.GenericEvent),
+		kinds.StatefulSetKind: make(chan event.GenericEvent),
+		kinds.DaemonSetKind:   make(chan event.GenericEvent),
+		kinds.JobKind:         make(chan event.GenericEvent),
+	}
+
+	// Client evaluating metrics gates and post-restart checks, if a Prometheus server is configured
+	var promQuerier promquery.Querier
+	if flags.PrometheusURL != "" {
+		if promQuerier, err = promquery.NewQuerier(flags.PrometheusURL); err != nil {
+			setupLog.Error(err, "unable to create Prometheus client")
+			return err
+		}
+	}
+
+	// Restrictions of the external ready checks configured by workload annotations
+	readyChecks := readycheck.Policy{
+		MaxTimeout:   flags.ReadyCheckMaxTimeout,
+		AllowedHosts: flags.ReadyCheckAllowedHosts,
+	}
+
+	// Settings shared by the reconcilers of all source kinds
+	base := controller.BaseReconciler{
+		Logger:                        &reconcilerLog,
+		KubeClient:                    mgr.GetClient(),
+		Metrics:                       metricsReg,
+		AnnotationKindMap:             annotationKindMap,
+		LastObservedRestartAnnotation: flags.LastObservedRestartAnnotation,
+		RequeueAfterAnnotation:        flags.RequeueAfterAnnotation,
+		RequeueAfterDefault:           flags.RequeueAfterDefault,
+		EvictionTimeout:               flags.EvictionTimeout,
+		MaxRetries:                    flags.MaxRetries,
+		RetryBackoff:                  flags.RetryBackoff,
+		RetryBackoffMax:               flags.RetryBackoffMax,
+		MissingTargets:                controller.MissingTargetsMode(flags.MissingTargets),
+		MissingTargetsTimeout:         flags.MissingTargetsTimeout,
+		StabilityTimeout:              flags.StabilityTimeout,
+		JoinTimeout:                   flags.JoinTimeout,
+		StabilityResync:               flags.StabilityResync,
+		WatchPods:                     flags.WatchSourcePods,
+		Prometheus:                    promQuerier,
+		ReadyChecks:                   readyChecks,
+		PostRestartCheckTimeout:       flags.PostRestartCheckTimeout,
+		SkipScaledDownTargets:         flags.SkipScaledDownTargets,
+		ScaleFollowHPA:                controller.ScaleFollowHPAMode(flags.ScaleFollowHPA),
+		QuiesceTimeout:                flags.QuiesceTimeout,
+		MaxFanOut:                     flags.MaxFanOut,
+		MaxCascadeSize:                flags.MaxCascadeSize,
+		MaxCascadeDepth:               flags.MaxCascadeDepth,
+		ApprovalTimeout:               flags.ApprovalTimeout,
+		TargetGroups:                  targetGroups,
+	}
+	// baseFor returns a copy of the shared settings with the event recorder and resume queue of the kind.
+	baseFor := func(kind kinds.Kind) controller.BaseReconciler {
+		b := base
+		b.Recorder = mgr.GetEventRecorder(strings.ToLower(kind.String()) + "-controller")
+		b.Resume = resumeQueues[kind]
+		return b
 	}
 
 	// Setup Deployment controller
 	if err := (&controller.DeploymentReconciler{
-		BaseReconciler: controller.BaseReconciler{
-			Logger:                        &reconcilerLog,
-			KubeClient:                    mgr.GetClient(),
-			Recorder:                      mgr.GetEventRecorder("deployment-controller"),
-			Metrics:                       metricsReg,
-			AnnotationKindMap:             annotationKindMap,
-			LastObservedRestartAnnotation: flags.LastObservedRestartAnnotation,
-			RequeueAfterAnnotation:        flags.RequeueAfterAnnotation,
-			RequeueAfterDefault:           flags.RequeueAfterDefault,
-		},
+		BaseReconciler: baseFor(kinds.DeploymentKind),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create Deployment controller")
 		return err
@@ -172,16 +255,8 @@ func Run(ctx context.Context, version string, args []string, stdOut, stdErr io.W
 
 	// Setup StatefulSet controller
 	if err := (&controller.StatefulSetReconciler{
-		BaseReconciler: controller.BaseReconciler{
-			Logger:                        &reconcilerLog,
-			KubeClient:                    mgr.GetClient(),
-			Recorder:                      mgr.GetEventRecorder("statefulset-controller"),
-			Metrics:                       metricsReg,
-			AnnotationKindMap:             annotationKindMap,
-			LastObservedRestartAnnotation: flags.LastObservedRestartAnnotation,
-			RequeueAfterAnnotation:        flags.RequeueAfterAnnotation,
-			RequeueAfterDefault:           flags.RequeueAfterDefault,
-		},
+		BaseReconciler: baseFor(kinds.StatefulSetKind),
+		OnDelete:       workloads.OnDeleteMode(flags.StatefulSetOnDelete),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create StatefulSet controller")
 		return err
@@ -189,21 +264,85 @@ func Run(ctx context.Context, version string, args []string, stdOut, stdErr io.W
 
 	// Setup DaemonSet controller
 	if err := (&controller.DaemonSetReconciler{
-		BaseReconciler: controller.BaseReconciler{
-			Logger:                        &reconcilerLog,
-			KubeClient:                    mgr.GetClient(),
-			Recorder:                      mgr.GetEventRecorder("daemonset-controller"),
-			Metrics:                       metricsReg,
-			AnnotationKindMap:             annotationKindMap,
-			LastObservedRestartAnnotation: flags.LastObservedRestartAnnotation,
-			RequeueAfterAnnotation:        flags.RequeueAfterAnnotation,
-			RequeueAfterDefault:           flags.RequeueAfterDefault,
-		},
+		BaseReconciler: baseFor(kinds.DaemonSetKind),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create DaemonSet controller")
 		return err
 	}
 
+	// Setup Job controller, Jobs are supported as sources only
+	if err := (&controller.JobReconciler{
+		BaseReconciler: baseFor(kinds.JobKind),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create Job controller")
+		return err
+	}
+
+	// Resume cascades interrupted by a restart or leader failover
+	if err := mgr.Add(&controller.CascadeRecovery{
+		KubeClient:                    mgr.GetClient(),
+		Logger:                        &reconcilerLog,
+		Metrics:                       metricsReg,
+		LastObservedRestartAnnotation: flags.LastObservedRestartAnnotation,
+		Queues:                        resumeQueues,
+	}); err != nil {
+		setupLog.Error(err, "unable to add cascade recovery")
+		return err
+	}
+
+	// Setup image digest controllers
+	if flags.WatchImageDigests {
+		for _, kind := range []kinds.Kind{kinds.DeploymentKind, kinds.StatefulSetKind, kinds.DaemonSetKind} {
+			if err := (&controller.ImageDigestReconciler{
+				KubeClient:        mgr.GetClient(),
+				Logger:            &reconcilerLog,
+				Kind:              kind,
+				AnnotationKindMap: annotationKindMap,
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create image digest controller", "kind", kind)
+				return err
+			}
+		}
+	}
+
+	// Setup pod restart controllers
+	if flags.WatchPodRestarts {
+		for _, kind := range []kinds.Kind{kinds.DeploymentKind, kinds.StatefulSetKind, kinds.DaemonSetKind} {
+			if err := (&controller.PodRestartReconciler{
+				KubeClient:        mgr.GetClient(),
+				Logger:            &reconcilerLog,
+				Kind:              kind,
+				AnnotationKindMap: annotationKindMap,
+				Threshold:         flags.PodRestartThreshold,
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create pod restart controller", "kind", kind)
+				return err
+			}
+		}
+	}
+
+	// Setup schedule controllers
+	scheduleTimeZone, err := time.LoadLocation(flags.ScheduleTimeZone)
+	if err != nil {
+		setupLog.Error(err, "invalid schedule time zone")
+		return err
+	}
+	for _, kind := range []kinds.Kind{kinds.DeploymentKind, kinds.StatefulSetKind, kinds.DaemonSetKind} {
+		if err := (&controller.ScheduleReconciler{
+			KubeClient:       mgr.GetClient(),
+			Logger:           &reconcilerLog,
+			Recorder:         mgr.GetEventRecorder(strings.ToLower(kind.String()) + "-schedule-controller"),
+			Metrics:          metricsReg,
+			Clock:            clock.RealClock{},
+			Kind:             kind,
+			TimeZone:         scheduleTimeZone,
+			StartingDeadline: flags.ScheduleStartingDeadline,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create schedule controller", "kind", kind)
+			return err
+		}
+	}
+
 	// Register health and readiness checks
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "failed to set up health check")
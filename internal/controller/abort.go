@@ -0,0 +1,80 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// abortReason reports why the cascade of an unstable workload must be aborted, or an empty string
+// if the workload may still become stable.
+func (b *BaseReconciler) abortReason(ctx context.Context, workload workloads.Workload, unstableReason string) (string, error) {
+	failure, err := workloads.RolloutFailure(ctx, b.KubeClient, workload)
+	if err != nil {
+		return "", fmt.Errorf("failed to check rollout of %s: %w", workload.ID(), err)
+	}
+	if failure != "" {
+		return fmt.Sprintf("rollout failed: %s", failure), nil
+	}
+
+	if b.StabilityTimeout > 0 && b.observedFor(workload.Resource()) >= b.StabilityTimeout {
+		return fmt.Sprintf("workload did not become stable within %s: %s", b.StabilityTimeout, unstableReason), nil
+	}
+
+	return "", nil
+}
+
+// abortCascade gives up on the cascade of the workload without restarting any target and clears its pending state.
+func (b *BaseReconciler) abortCascade(ctx context.Context, workload workloads.Workload, reason string) (ctrl.Result, error) {
+	res := workload.Resource()
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	log.Error(errors.New(reason), "Cascade aborted; skipping reload")
+	b.Metrics.IncCascadesAborted(workload.GetNamespace(), workload.GetName(), workload.Kind().String())
+	b.Recorder.Eventf(
+		res,
+		nil,
+		corev1.EventTypeWarning,
+		"CascadeAborted",
+		"WaitForStable",
+		"Cascade aborted: %s",
+		reason,
+	)
+
+	b.forgetRecreation(res)
+	if err := b.clearRetryState(ctx, workload); err != nil {
+		log.Error(err, "Failed to delete retry annotation")
+	}
+	if err := b.clearGateState(ctx, workload); err != nil {
+		log.Error(err, "Failed to delete stability gate annotation")
+	}
+	if err := b.releaseVerificationHold(ctx, res); err != nil {
+		log.Error(err, "Failed to delete verification hold annotation")
+	}
+	if err := b.clearLastObservedRestartAnnotation(ctx, workload); err != nil {
+		log.Error(err, "Failed to delete restartedAt annotation")
+	}
+
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReconcileWorkload_Abort(t *testing.T) {
+	t.Parallel()
+
+	// newUnstableSource returns a source with a rollout in progress, observed the given duration ago.
+	newUnstableSource := func(observedAgo time.Duration) *appsv1.Deployment {
+		source := newStableDeployment("source", map[string]string{
+			"cascader.tkb.ch/deployment":            "target",
+			"cascader.tkb.ch/last-observed-restart": time.Now().Add(-observedAgo).Format(time.RFC3339),
+		})
+		source.Status.UpdatedReplicas = 0
+		return source
+	}
+	// assertAborted verifies that the cascade was aborted without restarting the target.
+	assertAborted := func(t *testing.T, reconciler *BaseReconciler, recorder *events.FakeRecorder, reason string) {
+		t.Helper()
+
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "Warning CascadeAborted Cascade aborted: "+reason)
+
+		source := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "source"}, source))
+		assert.NotContains(t, source.Annotations, "cascader.tkb.ch/last-observed-restart")
+
+		target := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "target"}, target))
+		assert.NotContains(t, target.Spec.Template.Annotations, flag.LastObservedRestartAnnotation)
+	}
+
+	t.Run("Progress deadline exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		source := newUnstableSource(time.Minute)
+		source.Status.Conditions = []appsv1.DeploymentCondition{{
+			Type:    appsv1.DeploymentProgressing,
+			Status:  corev1.ConditionFalse,
+			Reason:  "ProgressDeadlineExceeded",
+			Message: "timed out",
+		}}
+
+		reconciler := createBaseReconciler(source, newStableDeployment("target", nil))
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assertAborted(t, reconciler, recorder, "rollout failed: progress deadline exceeded: timed out")
+	})
+
+	t.Run("Stability timeout exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		source := newUnstableSource(2 * time.Hour)
+
+		reconciler := createBaseReconciler(source, newStableDeployment("target", nil))
+		reconciler.StabilityTimeout = time.Hour
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assertAborted(t, reconciler, recorder, "workload did not become stable within 1h0m0s: not all replicas are updated")
+	})
+
+	t.Run("Within stability timeout", func(t *testing.T) {
+		t.Parallel()
+
+		source := newUnstableSource(time.Minute)
+
+		reconciler := createBaseReconciler(source, newStableDeployment("target", nil))
+		reconciler.StabilityTimeout = time.Hour
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: defaultRequeuAfter}, result)
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("Stability gates do not count towards the timeout", func(t *testing.T) {
+		t.Parallel()
+
+		source := newUnstableSource(2 * time.Hour)
+		source.Status.UpdatedReplicas = 1
+		source.Annotations[flag.SoakAnnotation] = "3h"
+
+		reconciler := createBaseReconciler(source, newStableDeployment("target", nil))
+		reconciler.StabilityTimeout = time.Hour
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.NotZero(t, result.RequeueAfter)
+		assert.Empty(t, recorder.Events)
+		assert.Contains(t, source.Annotations, "cascader.tkb.ch/last-observed-restart")
+	})
+
+	t.Run("Stability timeout disabled", func(t *testing.T) {
+		t.Parallel()
+
+		source := newUnstableSource(24 * time.Hour)
+
+		reconciler := createBaseReconciler(source, newStableDeployment("target", nil))
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: defaultRequeuAfter}, result)
+	})
+}
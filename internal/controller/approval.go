@@ -0,0 +1,222 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/utils"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// approvalRequest is a request to approve the restart of a target by a source. It is persisted as JSON
+// in an annotation on the target and approved by setting the approve annotation to its ID.
+type approvalRequest struct {
+	ID          string    `json:"id"`                 // ID which must be set in the approve annotation.
+	Source      string    `json:"source"`             // ID of the source which requested the restart.
+	RequestedAt time.Time `json:"requestedAt"`        // Time the approval was requested.
+	ExpiresAt   time.Time `json:"expiresAt,omitzero"` // Time the request expires, zero if it never expires.
+}
+
+// expired reports whether the request expired at the given time.
+func (r *approvalRequest) expired(now time.Time) bool {
+	return !r.ExpiresAt.IsZero() && !now.Before(r.ExpiresAt)
+}
+
+// loadApprovalRequest reads the pending approval request from the target annotations.
+// Returns nil if no approval is pending.
+func loadApprovalRequest(obj client.Object) (*approvalRequest, error) {
+	val, ok := obj.GetAnnotations()[flag.PendingApprovalAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	request := &approvalRequest{}
+	if err := json.Unmarshal([]byte(val), request); err != nil {
+		return nil, fmt.Errorf("invalid annotation %q: %w", flag.PendingApprovalAnnotation, err)
+	}
+	return request, nil
+}
+
+// requiresApproval reports whether restarting the target requires manual approval, either because
+// the target itself requires it or because the source requires it for the edge to the target.
+// Invalid annotations require approval, so that a typo does not bypass the gate.
+func requiresApproval(source, target client.Object, targetID string) (bool, error) {
+	if val, ok := target.GetAnnotations()[flag.RequiresApprovalAnnotation]; ok {
+		required, err := strconv.ParseBool(val)
+		if err != nil {
+			return true, fmt.Errorf("invalid annotation %q on %s: %w", flag.RequiresApprovalAnnotation, targetID, err)
+		}
+		if required {
+			return true, nil
+		}
+	}
+
+	val, ok := source.GetAnnotations()[flag.RequiresApprovalForAnnotation]
+	if !ok {
+		return false, nil
+	}
+
+	// Edges are specified as a comma-separated list of "Kind/name" or "Kind/namespace/name".
+	for _, ref := range strings.Split(val, ",") {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+
+		kind, name, found := strings.Cut(ref, "/")
+		if !found {
+			return true, fmt.Errorf("invalid reference %q in annotation %q", ref, flag.RequiresApprovalForAnnotation)
+		}
+		ns, name, err := utils.ParseTargetRef(name, source.GetNamespace())
+		if err != nil {
+			return true, fmt.Errorf("invalid reference %q in annotation %q: %w", ref, flag.RequiresApprovalForAnnotation, err)
+		}
+		if utils.GenerateID(kinds.Kind(kind), ns, name) == targetID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// approve checks whether the restart of a target by the source was approved. A new approval request is
+// recorded on the target if none is pending. Requests of other sources are waited for, unless they expired.
+// Expired, invalid and obsolete requests are removed along with their approval and pending approval metric.
+func (b *BaseReconciler) approve(ctx context.Context, workload workloads.Workload, obj client.Object, t targets.Target) (preflightDecision, string) {
+	log := b.Logger.WithValues("workloadID", workload.ID(), "targetID", t.ID())
+
+	required, err := requiresApproval(workload.Resource(), obj, t.ID())
+	if err != nil {
+		log.Error(err, "Invalid approval annotation, requiring approval")
+	}
+
+	request, err := loadApprovalRequest(obj)
+	if err != nil {
+		log.Error(err, "Discarding invalid approval request")
+		if err := b.clearApproval(ctx, obj, t); err != nil {
+			return preflightAwaitingApproval, fmt.Sprintf("failed to discard invalid approval request: %v", err)
+		}
+	}
+
+	if !required {
+		// A request of the source is no longer needed once the approval requirement was removed.
+		if request != nil && request.Source == workload.ID() {
+			if err := b.clearApproval(ctx, obj, t); err != nil {
+				log.Error(err, "Failed to delete obsolete approval request")
+			}
+		}
+		return preflightRestart, ""
+	}
+
+	now := time.Now()
+	if request != nil && request.Source != workload.ID() {
+		if !request.expired(now) {
+			b.Metrics.SetPendingApproval(t.Namespace(), t.Name(), t.Kind().String(), 1)
+			return preflightAwaitingApproval, fmt.Sprintf("waiting for approval requested by %s", request.Source)
+		}
+		// The request was abandoned by its source, e.g. because the source restarted again.
+		if err := b.clearApproval(ctx, obj, t); err != nil {
+			return preflightAwaitingApproval, fmt.Sprintf("failed to discard expired approval request: %v", err)
+		}
+		request = nil
+	}
+
+	if request != nil {
+		if obj.GetAnnotations()[flag.ApproveAnnotation] == request.ID {
+			return preflightRestart, ""
+		}
+		if request.expired(now) {
+			if err := b.clearApproval(ctx, obj, t); err != nil {
+				log.Error(err, "Failed to delete expired approval request")
+			}
+			return preflightApprovalExpired, fmt.Sprintf("approval was not given within %s", request.ExpiresAt.Sub(request.RequestedAt))
+		}
+		b.Metrics.SetPendingApproval(t.Namespace(), t.Name(), t.Kind().String(), 1)
+		return preflightAwaitingApproval, approvalHint(request)
+	}
+
+	request = &approvalRequest{ID: uuid.NewString(), Source: workload.ID(), RequestedAt: now.UTC().Truncate(time.Second)}
+	if b.ApprovalTimeout > 0 {
+		request.ExpiresAt = request.RequestedAt.Add(b.ApprovalTimeout)
+	}
+	if err := b.patchJSONAnnotation(ctx, obj, flag.PendingApprovalAnnotation, request); err != nil {
+		// The request is recorded again once the source is requeued.
+		return preflightAwaitingApproval, fmt.Sprintf("failed to record approval request: %v", err)
+	}
+	b.Metrics.SetPendingApproval(t.Namespace(), t.Name(), t.Kind().String(), 1)
+	b.Recorder.Eventf(
+		workload.Resource(),
+		nil,
+		corev1.EventTypeNormal,
+		"ApprovalRequired",
+		"PreflightTarget",
+		"Cascader requires approval to restart %s: %s",
+		t.ID(),
+		approvalHint(request),
+	)
+	return preflightAwaitingApproval, approvalHint(request)
+}
+
+// approvalHint describes how to approve the given request.
+func approvalHint(request *approvalRequest) string {
+	hint := fmt.Sprintf("approve by setting annotation %s=%s", flag.ApproveAnnotation, request.ID)
+	if !request.ExpiresAt.IsZero() {
+		hint += fmt.Sprintf(" before %s", request.ExpiresAt.Format(time.RFC3339))
+	}
+	return hint
+}
+
+// completeApproval removes the approval request of the source from the target once the target was restarted.
+func (b *BaseReconciler) completeApproval(ctx context.Context, workload workloads.Workload, t targets.Target) error {
+	obj := t.Resource()
+	if err := b.KubeClient.Get(ctx, client.ObjectKey{Namespace: t.Namespace(), Name: t.Name()}, obj); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", t.ID(), err)
+	}
+
+	request, err := loadApprovalRequest(obj)
+	if err != nil || request == nil || request.Source != workload.ID() {
+		return nil
+	}
+	return b.clearApproval(ctx, obj, t)
+}
+
+// clearApproval removes the approval request and the approval from the target.
+func (b *BaseReconciler) clearApproval(ctx context.Context, obj client.Object, t targets.Target) error {
+	original := obj.DeepCopyObject().(client.Object)
+	annotations := obj.GetAnnotations()
+	delete(annotations, flag.PendingApprovalAnnotation)
+	delete(annotations, flag.ApproveAnnotation)
+	obj.SetAnnotations(annotations)
+
+	if err := b.KubeClient.Patch(ctx, obj, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to delete approval annotations: %w", err)
+	}
+	b.Metrics.SetPendingApproval(t.Namespace(), t.Name(), t.Kind().String(), 0)
+	return nil
+}
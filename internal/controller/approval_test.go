@@ -0,0 +1,329 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	internalmetrics "github.com/thurgauerkb/cascader/internal/metrics"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// approvalRequestOf returns the pending approval request recorded on the named Deployment.
+func approvalRequestOf(t *testing.T, c client.Client, name string) *approvalRequest {
+	t.Helper()
+
+	dep := &appsv1.Deployment{}
+	require.NoError(t, c.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: name}, dep))
+	request, err := loadApprovalRequest(dep)
+	require.NoError(t, err)
+	return request
+}
+
+// setApproval approves the restart of the named Deployment with the given request ID.
+func setApproval(t *testing.T, c client.Client, name, id string) {
+	t.Helper()
+
+	dep := &appsv1.Deployment{}
+	require.NoError(t, c.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: name}, dep))
+	dep.Annotations[flag.ApproveAnnotation] = id
+	require.NoError(t, c.Update(t.Context(), dep))
+}
+
+func TestRequiresApproval(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		source   map[string]string
+		target   map[string]string
+		required bool
+		err      string
+	}{
+		{name: "No approval required", required: false},
+		{name: "Target requires approval", target: map[string]string{flag.RequiresApprovalAnnotation: "true"}, required: true},
+		{name: "Target does not require approval", target: map[string]string{flag.RequiresApprovalAnnotation: "false"}, required: false},
+		{
+			name:     "Invalid target annotation",
+			target:   map[string]string{flag.RequiresApprovalAnnotation: "yes please"},
+			required: true,
+			err:      `invalid annotation "cascader.tkb.ch/requires-approval" on Deployment/default/b: strconv.ParseBool: parsing "yes please": invalid syntax`,
+		},
+		{name: "Edge to target", source: map[string]string{flag.RequiresApprovalForAnnotation: "Deployment/c, Deployment/b"}, required: true},
+		{name: "Edge with namespace", source: map[string]string{flag.RequiresApprovalForAnnotation: "Deployment/default/b"}, required: true},
+		{name: "Edge to other target", source: map[string]string{flag.RequiresApprovalForAnnotation: "Deployment/c,StatefulSet/b"}, required: false},
+		{
+			name:     "Invalid edge",
+			source:   map[string]string{flag.RequiresApprovalForAnnotation: "b"},
+			required: true,
+			err:      `invalid reference "b" in annotation "cascader.tkb.ch/requires-approval-for"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := newStableDeployment("a", tt.source)
+			target := newStableDeployment("b", tt.target)
+
+			required, err := requiresApproval(source, target, "Deployment/default/b")
+			assert.Equal(t, tt.required, required)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestReconcileWorkload_Approval(t *testing.T) {
+	t.Parallel()
+
+	b := newStableDeployment("b", nil)
+	c := newStableDeployment("c", nil)
+
+	t.Run("Approved restart", func(t *testing.T) {
+		t.Parallel()
+
+		reg := prometheus.NewRegistry()
+		recorder := events.NewFakeRecorder(10)
+		reconciler := createBaseReconciler(
+			newStableDeployment("a", map[string]string{"cascader.tkb.ch/deployment": "b,c"}),
+			newStableDeployment("b", map[string]string{flag.RequiresApprovalAnnotation: "true"}),
+			newStableDeployment("c", nil),
+		)
+		reconciler.Metrics = internalmetrics.NewRegistry(reg)
+		reconciler.Recorder = recorder
+		reconciler.ApprovalTimeout = time.Hour
+
+		result := reconcileDeployment(t, reconciler, "a")
+		assert.Equal(t, defaultRequeuAfter, result.RequeueAfter)
+		assert.False(t, restarted(t, reconciler.KubeClient, b))
+		assert.True(t, restarted(t, reconciler.KubeClient, c))
+
+		request := approvalRequestOf(t, reconciler.KubeClient, "b")
+		require.NotNil(t, request)
+		assert.Equal(t, "Deployment/default/a", request.Source)
+		assert.Equal(t, time.Hour, request.ExpiresAt.Sub(request.RequestedAt))
+		event := <-recorder.Events
+		assert.True(t, strings.HasPrefix(
+			event,
+			"Normal ApprovalRequired Cascader requires approval to restart Deployment/default/b: approve by setting annotation cascader.tkb.ch/approve="+request.ID+" before ",
+		), event)
+
+		expected := `
+# HELP cascader_pending_approvals Indicates whether a restart of a target is waiting for manual approval (1 = pending, 0 = none).
+# TYPE cascader_pending_approvals gauge
+cascader_pending_approvals{name="b",namespace="default",resource_kind="Deployment"} 1
+`
+		require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "cascader_pending_approvals"))
+
+		// Without approval the target keeps waiting, even with a wrong approval.
+		setApproval(t, reconciler.KubeClient, "b", "not-the-request")
+		result = reconcileDeployment(t, reconciler, "a")
+		assert.Equal(t, defaultRequeuAfter, result.RequeueAfter)
+		assert.False(t, restarted(t, reconciler.KubeClient, b))
+		assert.Equal(t, request, approvalRequestOf(t, reconciler.KubeClient, "b"))
+
+		setApproval(t, reconciler.KubeClient, "b", request.ID)
+		result = reconcileDeployment(t, reconciler, "a")
+		assert.Zero(t, result.RequeueAfter)
+		assert.True(t, restarted(t, reconciler.KubeClient, b))
+
+		target := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(b), target))
+		assert.NotContains(t, target.Annotations, flag.PendingApprovalAnnotation)
+		assert.NotContains(t, target.Annotations, flag.ApproveAnnotation)
+
+		source := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "a"}, source))
+		assert.NotContains(t, source.Annotations, flag.DeferredTargetsAnnotation)
+
+		expected = strings.Replace(expected, "} 1", "} 0", 1)
+		require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "cascader_pending_approvals"))
+	})
+
+	t.Run("Approval required for edge", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler(
+			newStableDeployment("a", map[string]string{
+				"cascader.tkb.ch/deployment":       "b,c",
+				flag.RequiresApprovalForAnnotation: "Deployment/b",
+			}),
+			newStableDeployment("b", nil),
+			newStableDeployment("c", nil),
+		)
+
+		reconcileDeployment(t, reconciler, "a")
+		assert.False(t, restarted(t, reconciler.KubeClient, b))
+		assert.True(t, restarted(t, reconciler.KubeClient, c))
+		assert.NotNil(t, approvalRequestOf(t, reconciler.KubeClient, "b"))
+		assert.Nil(t, approvalRequestOf(t, reconciler.KubeClient, "c"))
+	})
+
+	t.Run("Expired approval", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := events.NewFakeRecorder(10)
+		reconciler := createBaseReconciler(
+			newStableDeployment("a", map[string]string{"cascader.tkb.ch/deployment": "b"}),
+			newStableDeployment("b", map[string]string{flag.RequiresApprovalAnnotation: "true"}),
+		)
+		reconciler.Recorder = recorder
+		reconciler.ApprovalTimeout = time.Nanosecond
+
+		reconcileDeployment(t, reconciler, "a")
+		<-recorder.Events
+
+		result := reconcileDeployment(t, reconciler, "a")
+		assert.Zero(t, result.RequeueAfter)
+		assert.False(t, restarted(t, reconciler.KubeClient, b))
+		assert.Nil(t, approvalRequestOf(t, reconciler.KubeClient, "b"))
+		assert.Equal(t, "Warning ApprovalExpired Cascader skipped restart of Deployment/default/b: approval was not given within 1ns", <-recorder.Events)
+
+		source := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "a"}, source))
+		assert.NotContains(t, source.Annotations, flag.DeferredTargetsAnnotation)
+	})
+
+	t.Run("Approval requested by another source", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name      string
+			expiresAt string
+			reason    string
+		}{
+			{name: "Pending request is waited for", expiresAt: "2099-01-01T00:00:00Z", reason: "waiting for approval requested by Deployment/default/x"},
+			{name: "Expired request is replaced", expiresAt: "2026-01-01T01:00:00Z", reason: "approve by setting annotation"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				source := newStableDeployment("a", map[string]string{"cascader.tkb.ch/deployment": "b"})
+				reconciler := createBaseReconciler(
+					source,
+					newStableDeployment("b", map[string]string{
+						flag.RequiresApprovalAnnotation: "true",
+						flag.PendingApprovalAnnotation:  `{"id":"request-1","source":"Deployment/default/x","requestedAt":"2026-01-01T00:00:00Z","expiresAt":"` + tt.expiresAt + `"}`,
+					}),
+				)
+
+				decision, reason := reconciler.preflight(
+					t.Context(),
+					&workloads.DeploymentWorkload{Deployment: source},
+					reconciler.newDependencyGraph(),
+					targets.NewDeployment("default", "b", reconciler.KubeClient),
+					false,
+				)
+				assert.Equal(t, preflightAwaitingApproval, decision)
+				assert.Contains(t, reason, tt.reason)
+			})
+		}
+	})
+
+	t.Run("Dropped requests reset the gauge", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name     string
+			target   map[string]string
+			decision preflightDecision
+			gauge    string
+		}{
+			{
+				name: "Approval no longer required",
+				target: map[string]string{
+					flag.PendingApprovalAnnotation: `{"id":"request-1","source":"Deployment/default/a","requestedAt":"2026-01-01T00:00:00Z"}`,
+				},
+				decision: preflightRestart,
+				gauge:    "0",
+			},
+			{
+				name: "Expired request of another source",
+				target: map[string]string{
+					flag.RequiresApprovalAnnotation: "true",
+					flag.PendingApprovalAnnotation:  `{"id":"request-1","source":"Deployment/default/x","requestedAt":"2026-01-01T00:00:00Z","expiresAt":"2026-01-01T01:00:00Z"}`,
+					flag.ApproveAnnotation:          "request-1",
+				},
+				decision: preflightAwaitingApproval,
+				gauge:    "1",
+			},
+			{
+				name: "Invalid request",
+				target: map[string]string{
+					flag.PendingApprovalAnnotation: `{`,
+				},
+				decision: preflightRestart,
+				gauge:    "0",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				source := newStableDeployment("a", map[string]string{"cascader.tkb.ch/deployment": "b"})
+				reconciler := createBaseReconciler(source, newStableDeployment("b", tt.target))
+				reg := prometheus.NewRegistry()
+				reconciler.Metrics = internalmetrics.NewRegistry(reg)
+				reconciler.Metrics.SetPendingApproval("default", "b", "Deployment", 1)
+
+				decision, _ := reconciler.preflight(
+					t.Context(),
+					&workloads.DeploymentWorkload{Deployment: source},
+					reconciler.newDependencyGraph(),
+					targets.NewDeployment("default", "b", reconciler.KubeClient),
+					false,
+				)
+				assert.Equal(t, tt.decision, decision)
+
+				// The dropped request and its approval are removed from the target.
+				target := &appsv1.Deployment{}
+				require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "b"}, target))
+				assert.NotContains(t, target.Annotations, flag.ApproveAnnotation)
+				if request := approvalRequestOf(t, reconciler.KubeClient, "b"); request != nil {
+					assert.Equal(t, "Deployment/default/a", request.Source)
+				}
+
+				expected := `
+# HELP cascader_pending_approvals Indicates whether a restart of a target is waiting for manual approval (1 = pending, 0 = none).
+# TYPE cascader_pending_approvals gauge
+cascader_pending_approvals{name="b",namespace="default",resource_kind="Deployment"} ` + tt.gauge + `
+`
+				require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "cascader_pending_approvals"))
+			})
+		}
+	})
+}
@@ -20,22 +20,34 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/thurgauerkb/cascader/internal/flag"
 	"github.com/thurgauerkb/cascader/internal/kinds"
 	"github.com/thurgauerkb/cascader/internal/metrics"
+	"github.com/thurgauerkb/cascader/internal/promquery"
+	"github.com/thurgauerkb/cascader/internal/readycheck"
+	"github.com/thurgauerkb/cascader/internal/recreation"
 	"github.com/thurgauerkb/cascader/internal/targets"
 	"github.com/thurgauerkb/cascader/internal/utils"
 	"github.com/thurgauerkb/cascader/internal/workloads"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 )
 
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods/eviction,verbs=create
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",namespace=cascader-system,resources=configmaps,verbs=get;list;watch
+
 // BaseReconciler contains shared fields for reconcilers.
 type BaseReconciler struct {
 	KubeClient                    client.Client           // KubeClient is the Kubernetes API client.
@@ -46,10 +58,47 @@ type BaseReconciler struct {
 	LastObservedRestartAnnotation string                  // LastObservedRestartAnnotation is the annotation key for last observed restarts.
 	RequeueAfterAnnotation        string                  // RequeueAfterAnnotation is the annotation key for requeue intervals.
 	RequeueAfterDefault           time.Duration           // RequeueAfterDefault is the default duration for requeuing.
+	EvictionTimeout               time.Duration           // EvictionTimeout is the maximum duration for restarting a target by eviction.
+	MaxRetries                    int                     // MaxRetries is the maximum number of retries for targets that failed to restart.
+	RetryBackoff                  time.Duration           // RetryBackoff is the initial backoff between retries.
+	RetryBackoffMax               time.Duration           // RetryBackoffMax caps the exponential backoff between retries.
+	MissingTargets                MissingTargetsMode      // MissingTargets is the default handling of targets which do not exist.
+	MissingTargetsTimeout         time.Duration           // MissingTargetsTimeout is the default duration to wait for missing targets.
+	StabilityTimeout              time.Duration           // StabilityTimeout is the maximum duration for a source to become stable, 0 disables it.
+	JoinTimeout                   time.Duration           // JoinTimeout is the maximum duration a shared dependent waits for the other upstreams of its cascade.
+	StabilityResync               time.Duration           // StabilityResync is the safety-net requeue while waiting for status updates, 0 polls instead.
+	WatchPods                     bool                    // WatchPods wakes sources with a pending cascade on readiness changes of their Pods.
+	Prometheus                    promquery.Querier       // Prometheus evaluates metrics gates and post-restart checks, nil fails them.
+	ReadyChecks                   readycheck.Policy       // ReadyChecks restricts the endpoints and timeouts of external ready checks.
+	PostRestartCheckTimeout       time.Duration           // PostRestartCheckTimeout is the maximum duration for restarted targets to pass their post-restart checks.
+	SkipScaledDownTargets         bool                    // SkipScaledDownTargets skips restarts of targets scaled to zero replicas.
+	ScaleFollowHPA                ScaleFollowHPAMode      // ScaleFollowHPA is the default handling of scale-follow targets managed by a HorizontalPodAutoscaler.
+	QuiesceTimeout                time.Duration           // QuiesceTimeout is the default maximum duration targets stay quiesced while their source is updated.
+	MaxFanOut                     int                     // MaxFanOut is the maximum number of direct targets per source, 0 disables it.
+	MaxCascadeSize                int                     // MaxCascadeSize is the maximum number of workloads restarted by a cascade, 0 disables it.
+	MaxCascadeDepth               int                     // MaxCascadeDepth is the maximum length of a dependency chain of a cascade, 0 disables it.
+	ApprovalTimeout               time.Duration           // ApprovalTimeout is the duration after which pending approvals expire, 0 disables it.
+	TargetGroups                  types.NamespacedName    // TargetGroups is the ConfigMap defining named target groups, empty disables them.
+	Recreations                   *recreation.Tracker     // Recreations remembers deleted workloads which cascade once recreated.
+	Resume                        chan event.GenericEvent // Resume enqueues workloads with an in-flight cascade, see CascadeRecovery.
 }
 
 // ReconcileWorkload handles the core reconciliation logic for any workload type.
+// Evictions in progress are advanced before and requeued after the reconciliation.
+// A recreated workload is forgotten once its cascade finished, see finishRecreation.
 func (b *BaseReconciler) ReconcileWorkload(ctx context.Context, workload workloads.Workload) (ctrl.Result, error) {
+	b.progressEvictions(ctx, workload, time.Now())
+
+	result, err := b.reconcileWorkload(ctx, workload)
+	if err != nil {
+		return result, err
+	}
+	b.finishRecreation(workload.Resource())
+	return b.requeueEvictions(workload.Resource(), result), nil
+}
+
+// reconcileWorkload restarts the targets of the workload if it changed.
+func (b *BaseReconciler) reconcileWorkload(ctx context.Context, workload workloads.Workload) (ctrl.Result, error) {
 	ns, name := workload.GetNamespace(), workload.GetName()
 
 	res := workload.Resource()
@@ -61,6 +110,67 @@ func (b *BaseReconciler) ReconcileWorkload(ctx context.Context, workload workloa
 	// If the last-observed-restart annotation is not present, this is the first time the workload is being processed.
 	// The annotation will be removed after a successful reconciliation.
 	observed := hasAnnotation(res, b.LastObservedRestartAnnotation)
+
+	// The dependency graph is listed at most once per reconciliation, see dependencyGraph.
+	graph := b.newDependencyGraph()
+
+	// Pending retries, verifications and deferred restarts of a recreated workload refer to the
+	// targets of its deleted predecessor.
+	b.restoreRecreatedAnnotations(res)
+
+	// Retry targets which failed during a previous cascade, unless the workload changed in the meantime.
+	state, err := loadRetryState(res)
+	if err != nil {
+		log.Error(err, "Discarding invalid retry state")
+	}
+	if state != nil && !observed && state.Generation == res.GetGeneration() {
+		return b.retryTargets(ctx, workload, graph, state)
+	}
+	if state != nil || err != nil {
+		if state != nil {
+			log.Info("Discarding pending retry superseded by a new restart", "targets", state.Targets)
+			b.createPendingReruns(ctx, workload, state)
+		}
+		if err := b.clearRetryState(ctx, workload); err != nil {
+			log.Error(err, "Failed to delete retry annotation")
+		}
+	}
+
+	// Verify targets restarted during a previous cascade, unless the workload changed in the meantime.
+	verification, err := loadVerificationState(res)
+	if err != nil {
+		log.Error(err, "Discarding invalid verification state")
+	}
+	if verification != nil && !observed && verification.Generation == res.GetGeneration() {
+		result, err := b.verifyTargets(ctx, workload, verification)
+		return requeuePending(res, result, b.RequeueAfterDefault), err
+	}
+	if verification != nil || err != nil {
+		if verification != nil {
+			log.Info("Discarding pending post-restart checks superseded by a new restart", "targets", verification.Targets)
+		}
+		if err := b.clearVerificationState(ctx, workload); err != nil {
+			log.Error(err, "Failed to delete verification annotation")
+		}
+	}
+
+	// Restart targets deferred during a previous cascade once their rollout finished or all upstreams arrived.
+	deferred, err := loadDeferredState(res)
+	if err != nil {
+		log.Error(err, "Discarding invalid deferred state")
+	}
+	if deferred != nil && !observed && deferred.Generation == res.GetGeneration() {
+		return b.restartDeferred(ctx, workload, graph, deferred)
+	}
+	if deferred != nil || err != nil {
+		if deferred != nil {
+			log.Info("Discarding deferred restarts superseded by a new restart", "targets", deferred.Targets)
+		}
+		if err := b.clearDeferredState(ctx, workload); err != nil {
+			log.Error(err, "Failed to delete deferred annotation")
+		}
+	}
+
 	if !observed {
 		now := time.Now().Format(time.RFC3339)
 		log.Info("Restart detected, handling targets", "restartedAt", now)
@@ -69,21 +179,86 @@ func (b *BaseReconciler) ReconcileWorkload(ctx context.Context, workload workloa
 		}
 	}
 
+	// A recreated workload may not carry the annotations of its deleted predecessor yet.
+	// They are restored first, so that the workload takes part in the cascade below.
+	recreated := b.restoreRecreatedAnnotations(res)
+	if recreated && !observed {
+		log.Info("Workload was recreated, using annotations of the deleted workload")
+	}
+
+	// Continue the cascade of the upstream which restarted the workload, or start a new one.
+	if !observed && hasTargetAnnotation(res, b.AnnotationKindMap) {
+		if stamp, err := b.adoptCascade(ctx, workload); err != nil {
+			log.Error(err, "Failed to record cascade")
+		} else {
+			log.Info("Cascade identified", "cascade", stamp.ID, "root", stamp.Root)
+		}
+		// Patching the workload drops the annotations restored in memory.
+		if recreated {
+			b.restoreRecreatedAnnotations(res)
+		}
+	}
+
 	// Extract dependent targets from workload annotations.
-	targets, err := b.extractTargets(ctx, res)
+	targets, patterns, err := b.resolveTargets(ctx, res)
 	if err != nil {
+		b.Recorder.Eventf(
+			res,
+			nil,
+			corev1.EventTypeWarning,
+			"InvalidTargets",
+			"ExtractTargets",
+			"Cascader cannot determine the targets: %v",
+			err,
+		)
 		return ctrl.Result{}, fmt.Errorf("failed to create targets: %w", err)
 	}
+	if !observed {
+		// Report the targets of patterns only when restart was just detected.
+		b.reportPatterns(workload, patterns)
+	}
 	// Set the number of targets as a metric, even if no targets are found.
 	b.Metrics.SetWorkloadTargets(ns, name, kind, float64(len(targets)))
 
 	if len(targets) == 0 {
+		b.forgetRecreation(res)
 		log.Info("No targets found; skipping reload.")
 		return ctrl.Result{}, nil
 	}
+	// Scale targets following the scale of the workload to zero along with it; they are not restarted meanwhile.
+	followers := scaleFollowers(res, targets)
+	resolved := targets
+	if scaledToZero(res) && len(followers) > 0 {
+		b.followScaleDown(ctx, workload, followers)
+		targets = withoutTargets(targets, followers)
+	}
+	// Skip targets whose edge conditions are not met by the changes of the workload.
+	targets = b.filterConditions(workload, targets, observed)
+	if !observed {
+		// Other upstreams of the skipped targets do not wait for the workload in its cascade.
+		b.skipJoins(ctx, workload, graph, withoutTargets(resolved, targets))
+	}
 	if !observed {
 		// Log targets only when restart was just detected.
 		log.Info("Dependent targets extracted", "targets", targetIDs(targets))
+
+		// Block cascades which would restart more workloads than allowed, before any target is restarted.
+		// The restart is detected again on the next attempt, so that the check is not skipped.
+		blocked, err := b.checkBlastRadius(ctx, workload, graph, targets)
+		if err != nil || blocked {
+			if blocked {
+				b.forgetRecreation(res)
+			}
+			if err := b.clearLastObservedRestartAnnotation(ctx, workload); err != nil {
+				log.Error(err, "Failed to delete restartedAt annotation")
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Stop targets which are quiesced while the workload is updated; they are restored once it is stable.
+	if quiesced := quiescedTargets(res, targets); !observed && len(quiesced) > 0 {
+		b.quiesceTargets(ctx, workload, quiesced, time.Now())
 	}
 
 	// Determine requeue interval.
@@ -92,6 +267,12 @@ func (b *BaseReconciler) ReconcileWorkload(ctx context.Context, workload workloa
 		log.Error(err, fmt.Sprintf("Invalid requeue annotation, using default: %s", b.RequeueAfterDefault))
 	}
 
+	// Handle targets which do not exist according to the missing-targets mode of the workload.
+	targets, result := b.handleMissingTargets(ctx, workload, targets, observed, dur)
+	if result != nil {
+		return *result, nil
+	}
+
 	// Check for and handle circular dependencies among workloads to prevent infinite reload loops.
 	if err := b.checkCycle(ctx, id, targets); err != nil {
 		if cycleErr, ok := err.(*CycleError); ok {
@@ -107,6 +288,7 @@ func (b *BaseReconciler) ReconcileWorkload(ctx context.Context, workload workloa
 			)
 		}
 		log.Error(err, "Dependency cycle detected; skipping reload")
+		b.restoreQuiesced(ctx, workload, targets, time.Now(), true)
 		return ctrl.Result{}, nil // Do not return an error to avoid requeuing the workload.
 	}
 	// Reset dependency cycle metric to indicate no cycle was detected.
@@ -114,29 +296,90 @@ func (b *BaseReconciler) ReconcileWorkload(ctx context.Context, workload workloa
 
 	// Check if the workload is in a stable state before triggering reloads.
 	stable, reason := workload.Stable()
+	requeue := b.stabilityRequeue(res, dur)
+	gated := false
+	if stable {
+		// A stable workload must still pass its stability gates, e.g. a soak period.
+		gates, err := b.checkStabilityGates(ctx, workload, dur)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if gates.Failed == "" && gates.Pending == "" {
+			// A workload restarted by an upstream waits until the upstream verified its post-restart check.
+			gates = b.checkVerificationHold(ctx, workload, dur)
+		}
+		if gates.Failed != "" {
+			b.restoreQuiesced(ctx, workload, targets, time.Now(), true)
+			return b.abortCascade(ctx, workload, gates.Failed)
+		}
+		if gates.Pending != "" {
+			stable, reason, requeue, gated = false, gates.Pending, gates.Requeue, true
+		}
+	} else if err := b.clearGateState(ctx, workload); err != nil {
+		// The stability gates start over once the workload becomes stable again.
+		log.Error(err, "Failed to delete stability gate annotation")
+	}
 	if !stable {
-		log.Info(fmt.Sprintf("Workload not stable. Requeuing after %s.", dur), "reason", reason)
-		return ctrl.Result{RequeueAfter: dur}, nil
+		// The rollout of a workload waiting for its gates finished, so the stability timeout does not apply.
+		if !gated {
+			abort, err := b.abortReason(ctx, workload, reason)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if abort != "" {
+				b.restoreQuiesced(ctx, workload, targets, time.Now(), true)
+				return b.abortCascade(ctx, workload, abort)
+			}
+		}
+		// Quiesced targets are restored once their timeout expired, even if the workload never becomes stable.
+		if _, next := b.restoreQuiesced(ctx, workload, targets, time.Now(), false); next > 0 && next < requeue {
+			requeue = next
+		}
+		log.Info(fmt.Sprintf("Workload not stable. Requeuing after %s.", requeue), "reason", reason)
+		return ctrl.Result{RequeueAfter: requeue}, nil
 	}
 	log.Info("Workload is stable", "reason", reason)
+	if err := b.clearGateState(ctx, workload); err != nil {
+		log.Error(err, "Failed to delete stability gate annotation")
+	}
 
 	// Always remove the restartedAt annotation, even if target reloads will fail.
 	if err := b.clearLastObservedRestartAnnotation(ctx, workload); err != nil {
 		b.Logger.Error(err, "Failed to delete restartedAt annotation")
 	}
+	// Remember the template of this cascade to evaluate the edge conditions of the next one.
+	if err := b.recordTemplateHashes(ctx, workload); err != nil {
+		log.Error(err, "Failed to record template hashes")
+	}
 
-	// Trigger reloads on all dependent targets and collect success/failure counts.
-	succ, fail := b.triggerReloads(ctx, workload, targets)
-	if fail > 0 {
-		// Some targets failed to reload. We log the error but do not return it,
-		// to avoid requeuing the workload unnecessarily.
-		log.Error(errors.New("partial target reload failure"), "Some targets failed to reload", "succeeded", succ, "failed", fail)
-		return ctrl.Result{}, nil
+	// Targets restored to their replicas from before the workload was scaled to zero start fresh Pods anyway.
+	targets = withoutTargets(targets, b.restoreScale(ctx, workload, followers))
+	// Quiesced targets were stopped during the update and start fresh once restored.
+	restored, _ := b.restoreQuiesced(ctx, workload, targets, time.Now(), true)
+	targets = withoutTargets(targets, restored)
+
+	// Skip paused and scaled-down targets and defer targets which are rolling out, delayed, wait for other upstreams or for approval.
+	ready, rolling, joining, held := b.preflightTargets(ctx, workload, graph, targets, nil, time.Now())
+	if waiting := slices.Concat(rolling, joining, held); len(waiting) > 0 {
+		b.scheduleDeferral(ctx, workload, waiting)
+	}
+
+	// Trigger reloads on all dependent targets and collect the successes and failures.
+	succ, failed := b.triggerReloads(ctx, workload, ready)
+	if len(failed) > 0 {
+		// Some targets failed to reload. We log the error but do not return it, to avoid
+		// rate-limited requeues restarting all targets again. Only the failed targets are retried.
+		log.Error(errors.New("partial target reload failure"), "Some targets failed to reload", "succeeded", succ, "failed", len(failed))
+		// The restarted targets are verified once the retries finished.
+		_, _ = b.scheduleVerification(ctx, workload, restartedTargets(ready, failed), dur)
+		retry, err := b.scheduleRetry(ctx, workload, 0, failed)
+		return requeuePending(res, retry, dur), err
 	}
 
-	log.Info("Finished handling targets", "succeeded", succ, "failed", fail)
+	log.Info("Finished handling targets", "succeeded", succ, "failed", 0)
 
-	return ctrl.Result{}, nil
+	verify, err := b.scheduleVerification(ctx, workload, ready, dur)
+	return requeuePending(res, verify, dur), err
 }
 
 // setLastObservedRestartAnnotation sets the last-observed-restart annotation on the given workload.
@@ -169,11 +412,88 @@ func (b *BaseReconciler) clearLastObservedRestartAnnotation(
 
 // extractTargets parses annotations to extract dependent workload targets.
 func (b *BaseReconciler) extractTargets(ctx context.Context, source client.Object) ([]targets.Target, error) {
+	targetList, _, err := b.resolveTargets(ctx, source)
+	return targetList, err
+}
+
+// resolveTargets parses annotations to extract dependent workload targets. References with patterns are
+// resolved against the workloads in the cache and returned along with the workloads they matched.
+func (b *BaseReconciler) resolveTargets(ctx context.Context, source client.Object) ([]targets.Target, []resolvedPattern, error) {
 	var targetList []targets.Target
+	var patterns []resolvedPattern
 
 	annotations := source.GetAnnotations()
 	if annotations == nil {
-		return targetList, nil
+		return targetList, patterns, nil
+	}
+
+	// appendTarget appends a target unless it is already listed. Workloads referenced several times,
+	// e.g. by patterns or through groups, are restarted only once.
+	appendTarget := func(t targets.Target) {
+		if !slices.ContainsFunc(targetList, func(other targets.Target) bool { return other.ID() == t.ID() }) {
+			targetList = append(targetList, t)
+		}
+	}
+
+	// Target groups are loaded once, when the first group is referenced.
+	var groups targetGroups
+
+	// add appends the target of a reference, all workloads matching a reference with patterns, or
+	// the members of a group. Path holds the groups expanded so far and detects cycles between groups.
+	var add func(kind kinds.Kind, ref string, path []string) error
+	add = func(kind kinds.Kind, ref string, path []string) error {
+		if group, ok := strings.CutPrefix(ref, groupPrefix); ok {
+			if groups == nil {
+				var err error
+				if groups, err = b.loadTargetGroups(ctx); err != nil {
+					return fmt.Errorf("cannot resolve targets %q: %w", ref, err)
+				}
+			}
+			members, err := groups.members(group, path)
+			if err != nil {
+				return fmt.Errorf("cannot resolve targets %q: %w", ref, err)
+			}
+			path = append(slices.Clone(path), group)
+			for _, member := range members {
+				if err := add(kind, member, path); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		ns, name, err := utils.ParseTargetRef(ref, source.GetNamespace())
+		if err != nil || !isPattern(ns) && !isPattern(name) {
+			t, err := targets.NewTarget(ctx, b.KubeClient, kind, ref, source)
+			if err != nil {
+				return fmt.Errorf("cannot create target for workload: %w", err)
+			}
+			appendTarget(t)
+			return nil
+		}
+
+		matched, err := b.resolvePattern(ctx, source, kind, ns, name)
+		if err != nil {
+			return fmt.Errorf("cannot resolve targets %q: %w", ref, err)
+		}
+		pattern := resolvedPattern{Kind: kind, Ref: ref}
+		for _, t := range matched {
+			appendTarget(t)
+			pattern.Targets = append(pattern.Targets, t.ID())
+		}
+		patterns = append(patterns, pattern)
+		return nil
+	}
+
+	// Targets with per-edge options are specified in the structured targets annotation.
+	specs, err := parseTargetSpecs(source)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, spec := range specs {
+		if err := add(spec.Kind, spec.Namespace+"/"+spec.Name, nil); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	for key, kind := range b.AnnotationKindMap {
@@ -188,16 +508,13 @@ func (b *BaseReconciler) extractTargets(ctx context.Context, source client.Objec
 			if ref == "" {
 				continue
 			}
-
-			t, err := targets.NewTarget(ctx, b.KubeClient, kind, ref, source)
-			if err != nil {
-				return nil, fmt.Errorf("cannot create target for workload: %w", err)
+			if err := add(kind, ref, nil); err != nil {
+				return nil, nil, err
 			}
-			targetList = append(targetList, t)
 		}
 	}
 
-	return targetList, nil
+	return targetList, patterns, nil
 }
 
 // requeueDurationFor determines requeue interval from annotations or falls back to default.
@@ -220,17 +537,36 @@ func (b *BaseReconciler) requeueDurationFor(obj client.Object) (time.Duration, e
 	return dur, nil
 }
 
-// triggerReloads attempts to trigger reload for each target, returning success and failure counts.
-func (b *BaseReconciler) triggerReloads(ctx context.Context, workload workloads.Workload, targets []targets.Target) (succ, fail int) {
+// triggerReloads attempts to trigger reload for each target, returning the success count and the failed targets.
+func (b *BaseReconciler) triggerReloads(ctx context.Context, workload workloads.Workload, targetList []targets.Target) (succ int, failed []targets.Target) {
 	res := workload.Resource()
 	workloadID := workload.ID()
 	log := b.Logger.WithValues("workloadID", workloadID) // Append workload ID to logger context
 
-	for _, t := range targets {
+	for _, t := range targetList {
 		targetID := t.ID()
 		kind := t.Kind().String()
 
-		if err := t.Trigger(ctx); err != nil {
+		strategy, err := b.withStrategy(ctx, res, t)
+		if err == nil {
+			// Pass the cascade on, so that targets reached through several paths restart only once.
+			if err := b.stampCascade(ctx, res, t); err != nil {
+				log.Error(err, "Failed to pass cascade on to target", "targetID", targetID)
+			}
+			// Hold the cascade of the target until its post-restart check passed.
+			held, holdErr := b.holdForVerification(ctx, workloadID, t)
+			if holdErr != nil {
+				log.Error(holdErr, "Failed to hold cascade of target for its post-restart check", "targetID", targetID)
+			}
+
+			err = b.restartTarget(ctx, workload, t, strategy)
+			if err != nil && held != nil {
+				if err := b.releaseVerificationHold(ctx, held); err != nil {
+					log.Error(err, "Failed to release verification hold of target", "targetID", targetID)
+				}
+			}
+		}
+		if err != nil {
 			log.Error(err, "Failed to trigger reload", "targetID", targetID)
 			b.Recorder.Eventf(
 				res,
@@ -242,13 +578,16 @@ func (b *BaseReconciler) triggerReloads(ctx context.Context, workload workloads.
 				workloadID,
 				err,
 			)
-			fail++
+			failed = append(failed, t)
 
 			continue
 		}
 
 		b.Metrics.IncRestartsPerformed(t.Namespace(), t.Name(), kind)
 		log.Info("Successfully triggered reload", "targetID", targetID)
+		if err := b.completeApproval(ctx, workload, t); err != nil {
+			log.Error(err, "Failed to delete approval request of restarted target", "targetID", targetID)
+		}
 		b.Recorder.Eventf(
 			res,
 			nil,
@@ -261,5 +600,54 @@ func (b *BaseReconciler) triggerReloads(ctx context.Context, workload workloads.
 		succ++
 	}
 
-	return succ, fail
+	return succ, failed
+}
+
+// restartTarget triggers the restart of the target with the given strategy. Targets restarted by eviction
+// only evict their first Pod, the eviction is persisted on the source and advanced by its following reconciles.
+func (b *BaseReconciler) restartTarget(ctx context.Context, workload workloads.Workload, t targets.Target, strategy targets.Strategy) error {
+	if strategy != targets.EvictStrategy {
+		return t.Trigger(ctx)
+	}
+
+	evicting := targets.NewEvictionTarget(t, b.KubeClient, b.EvictionTimeout)
+	if err := evicting.Trigger(ctx); err != nil {
+		return err
+	}
+	return b.trackEviction(ctx, workload, evicting)
+}
+
+// withStrategy determines the restart strategy of the target from the edge or the annotation on the target workload.
+func (b *BaseReconciler) withStrategy(ctx context.Context, source client.Object, t targets.Target) (targets.Strategy, error) {
+	if job, ok := t.(*targets.JobTarget); ok && job.PendingRerun() != nil {
+		// The Job was deleted by a previous attempt and is created again from its persisted spec.
+		return targets.RolloutStrategy, nil
+	}
+
+	obj := t.Resource()
+	if err := b.KubeClient.Get(ctx, client.ObjectKey{Namespace: t.Namespace(), Name: t.Name()}, obj); err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", t.ID(), err)
+	}
+
+	// The strategy of the edge takes precedence over the strategy annotated on the target.
+	strategy := edgeSpecs(source).lookup(t).Strategy
+	if strategy == "" {
+		parsed, err := targets.ParseStrategy(obj.GetAnnotations()[flag.RestartStrategyAnnotation])
+		if err != nil {
+			return "", fmt.Errorf("invalid annotation on %s: %w", t.ID(), err)
+		}
+		strategy = parsed
+	}
+
+	if strategy == targets.EvictStrategy {
+		if t.Kind() == kinds.JobKind || t.Kind() == kinds.CronJobKind {
+			return "", fmt.Errorf("restart strategy %s is not supported for %s", strategy, t.ID())
+		}
+		// Evicting pods leaves the pod template unchanged, so the cascade would stop at the target.
+		if hasTargetAnnotation(obj, b.AnnotationKindMap) {
+			return "", fmt.Errorf("restart strategy %s is not supported for %s: it has targets of its own", strategy, t.ID())
+		}
+	}
+
+	return strategy, nil
 }
@@ -33,6 +33,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -66,6 +67,7 @@ func createBaseReconciler(objects ...client.Object) *BaseReconciler {
 		LastObservedRestartAnnotation: "cascader.tkb.ch/last-observed-restart",
 		RequeueAfterAnnotation:        "cascader.tkb.ch/requeueAfter",
 		RequeueAfterDefault:           defaultRequeuAfter,
+		JoinTimeout:                   30 * time.Minute,
 		AnnotationKindMap: kinds.AnnotationKindMap{
 			"cascader.tkb.ch/deployment":  kinds.DeploymentKind,
 			"cascader.tkb.ch/statefulset": kinds.StatefulSetKind,
@@ -79,6 +81,7 @@ func TestBaseReconciler_ReconcileWorkload(t *testing.T) {
 
 	scheme := runtime.NewScheme()
 	_ = appsv1.AddToScheme(scheme)
+	_ = batchv1.AddToScheme(scheme)
 
 	now := time.Now().Format(time.RFC3339)
 
@@ -432,7 +435,7 @@ func TestBaseReconciler_ReconcileWorkload(t *testing.T) {
 				AvailableReplicas:  4,
 				ReadyReplicas:      4,
 				UpdatedReplicas:    4,
-				ObservedGeneration: 5,
+				ObservedGeneration: 6,
 			},
 			Spec: appsv1.DeploymentSpec{
 				Replicas: testutils.Int32Ptr(4),
@@ -869,7 +872,7 @@ func TestTriggerReloads(t *testing.T) {
 		)
 
 		assert.Equal(t, 2, successes, "All reloads should succeed")
-		assert.Equal(t, 0, failures, "No failures should occur")
+		assert.Empty(t, failures, "No failures should occur")
 	})
 
 	t.Run("Some Reloads Fail", func(t *testing.T) {
@@ -903,7 +906,8 @@ func TestTriggerReloads(t *testing.T) {
 		)
 
 		assert.Equal(t, 1, successes, "One reload should succeed")
-		assert.Equal(t, 1, failures, "One reload should fail")
+		require.Len(t, failures, 1, "One reload should fail")
+		assert.Equal(t, "StatefulSet/default/nonexistent-statefulset", failures[0].ID())
 	})
 
 	t.Run("All Reloads Fail", func(t *testing.T) {
@@ -937,7 +941,147 @@ func TestTriggerReloads(t *testing.T) {
 		)
 
 		assert.Equal(t, 0, successes, "No reloads should succeed")
-		assert.Equal(t, 2, failures, "All reloads should fail")
+		assert.Len(t, failures, 2, "All reloads should fail")
+	})
+}
+
+func TestWithStrategy(t *testing.T) {
+	t.Parallel()
+
+	source := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source",
+			Namespace: "default",
+		},
+	}
+
+	t.Run("Default rollout strategy", func(t *testing.T) {
+		t.Parallel()
+
+		sts := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "statefulset",
+				Namespace: "default",
+			},
+		}
+
+		reconciler := createBaseReconciler(sts)
+		target := targets.NewStatefulSet("default", "statefulset", reconciler.KubeClient)
+
+		strategy, err := reconciler.withStrategy(t.Context(), source, target)
+		require.NoError(t, err)
+		assert.Equal(t, targets.RolloutStrategy, strategy)
+	})
+
+	t.Run("Evict strategy", func(t *testing.T) {
+		t.Parallel()
+
+		sts := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "statefulset",
+				Namespace: "default",
+				Annotations: map[string]string{
+					flag.RestartStrategyAnnotation: "evict",
+				},
+			},
+		}
+
+		reconciler := createBaseReconciler(sts)
+		target := targets.NewStatefulSet("default", "statefulset", reconciler.KubeClient)
+
+		strategy, err := reconciler.withStrategy(t.Context(), source, target)
+		require.NoError(t, err)
+		assert.Equal(t, targets.EvictStrategy, strategy)
+	})
+
+	t.Run("Strategy of the edge", func(t *testing.T) {
+		t.Parallel()
+
+		sts := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "statefulset",
+				Namespace: "default",
+				Annotations: map[string]string{
+					flag.RestartStrategyAnnotation: "rollout",
+				},
+			},
+		}
+		source := source.DeepCopy()
+		source.Annotations = map[string]string{
+			flag.TargetsAnnotation: `{"version":"v1","targets":[{"kind":"StatefulSet","name":"statefulset","strategy":"evict"}]}`,
+		}
+
+		reconciler := createBaseReconciler(sts)
+		target := targets.NewStatefulSet("default", "statefulset", reconciler.KubeClient)
+
+		strategy, err := reconciler.withStrategy(t.Context(), source, target)
+		require.NoError(t, err)
+		assert.Equal(t, targets.EvictStrategy, strategy)
+	})
+
+	t.Run("Evict strategy on Job", func(t *testing.T) {
+		t.Parallel()
+
+		job := newJob("warm-cache", map[string]string{flag.RestartStrategyAnnotation: "evict"})
+
+		reconciler := createBaseReconciler(job)
+		target := targets.NewJob("default", "warm-cache", "", reconciler.KubeClient)
+
+		_, err := reconciler.withStrategy(t.Context(), source, target)
+		require.EqualError(t, err, "restart strategy evict is not supported for Job/default/warm-cache")
+	})
+
+	t.Run("Evict strategy on target with targets", func(t *testing.T) {
+		t.Parallel()
+
+		sts := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "statefulset",
+				Namespace: "default",
+				Annotations: map[string]string{
+					flag.RestartStrategyAnnotation: "evict",
+					"cascader.tkb.ch/deployment":   "downstream",
+				},
+			},
+		}
+
+		reconciler := createBaseReconciler(sts)
+		target := targets.NewStatefulSet("default", "statefulset", reconciler.KubeClient)
+
+		_, err := reconciler.withStrategy(t.Context(), source, target)
+		require.EqualError(t, err, "restart strategy evict is not supported for StatefulSet/default/statefulset: it has targets of its own")
+	})
+
+	t.Run("Invalid strategy", func(t *testing.T) {
+		t.Parallel()
+
+		sts := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "statefulset",
+				Namespace: "default",
+				Annotations: map[string]string{
+					flag.RestartStrategyAnnotation: "recreate",
+				},
+			},
+		}
+
+		reconciler := createBaseReconciler(sts)
+		target := targets.NewStatefulSet("default", "statefulset", reconciler.KubeClient)
+
+		_, err := reconciler.withStrategy(t.Context(), source, target)
+		require.Error(t, err)
+		assert.EqualError(t, err, "invalid annotation on StatefulSet/default/statefulset: unsupported restart strategy: \"recreate\"")
+	})
+
+	t.Run("Target not found", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler()
+		target := targets.NewStatefulSet("default", "statefulset", reconciler.KubeClient)
+
+		_, err := reconciler.withStrategy(t.Context(), source, target)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to fetch StatefulSet/default/statefulset")
 	})
 }
 
@@ -0,0 +1,175 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// cascadeImpact describes the workloads restarted by a cascade of a source.
+type cascadeImpact struct {
+	FanOut    int      // Number of direct targets of the source.
+	Workloads []string // Sorted IDs of all workloads restarted by the cascade.
+	Depth     int      // Length of the longest dependency chain starting at the source.
+}
+
+// String returns a short summary of the impact.
+func (i cascadeImpact) String() string {
+	return fmt.Sprintf("fan-out=%d, workloads=%d, depth=%d", i.FanOut, len(i.Workloads), i.Depth)
+}
+
+// cascadeImpact computes the workloads a cascade of the source restarts, directly or transitively.
+func (b *BaseReconciler) cascadeImpact(
+	ctx context.Context,
+	graph *dependencyGraph,
+	sourceID string,
+	targetList []targets.Target,
+) (cascadeImpact, error) {
+	listed, err := graph.Edges(ctx)
+	if err != nil {
+		return cascadeImpact{}, err
+	}
+	// The targets of the source may differ from the listed ones, e.g. for recreated workloads.
+	edges := maps.Clone(listed)
+	edges[sourceID] = targetIDs(targetList)
+
+	reachable := map[string]bool{sourceID: true}
+	queue := []string{sourceID}
+	var impacted []string
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, next := range edges[id] {
+			if !reachable[next] {
+				reachable[next] = true
+				impacted = append(impacted, next)
+				queue = append(queue, next)
+			}
+		}
+	}
+	slices.Sort(impacted)
+
+	return cascadeImpact{
+		FanOut:    len(edges[sourceID]),
+		Workloads: impacted,
+		Depth:     chainDepth(edges, sourceID, map[string]int{}, map[string]bool{}),
+	}, nil
+}
+
+// chainDepth returns the length of the longest dependency chain starting at id.
+// Workloads on the current path are not followed again, so that cycles terminate.
+func chainDepth(edges map[string][]string, id string, memo map[string]int, path map[string]bool) int {
+	if depth, ok := memo[id]; ok {
+		return depth
+	}
+
+	path[id] = true
+	depth := 0
+	for _, next := range edges[id] {
+		if !path[next] {
+			depth = max(depth, chainDepth(edges, next, memo, path)+1)
+		}
+	}
+	delete(path, id)
+
+	memo[id] = depth
+	return depth
+}
+
+// exceededLimits returns the blast radius limits exceeded by the impact.
+func (b *BaseReconciler) exceededLimits(impact cascadeImpact) []string {
+	var exceeded []string
+	if b.MaxFanOut > 0 && impact.FanOut > b.MaxFanOut {
+		exceeded = append(exceeded, fmt.Sprintf("fan-out %d > %d", impact.FanOut, b.MaxFanOut))
+	}
+	if b.MaxCascadeSize > 0 && len(impact.Workloads) > b.MaxCascadeSize {
+		exceeded = append(exceeded, fmt.Sprintf("workloads %d > %d", len(impact.Workloads), b.MaxCascadeSize))
+	}
+	if b.MaxCascadeDepth > 0 && impact.Depth > b.MaxCascadeDepth {
+		exceeded = append(exceeded, fmt.Sprintf("depth %d > %d", impact.Depth, b.MaxCascadeDepth))
+	}
+	return exceeded
+}
+
+// limitsEnabled reports whether any blast radius limit is configured.
+func (b *BaseReconciler) limitsEnabled() bool {
+	return b.MaxFanOut > 0 || b.MaxCascadeSize > 0 || b.MaxCascadeDepth > 0
+}
+
+// checkBlastRadius logs the impact of a starting cascade and reports whether it exceeds the blast radius
+// limits. Sources with the override annotation are never blocked. The impact cannot be computed without
+// listing all workloads; this only fails the check if limits are configured.
+func (b *BaseReconciler) checkBlastRadius(
+	ctx context.Context,
+	workload workloads.Workload,
+	graph *dependencyGraph,
+	targetList []targets.Target,
+) (blocked bool, err error) {
+	res := workload.Resource()
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	impact, err := b.cascadeImpact(ctx, graph, workload.ID(), targetList)
+	if err != nil {
+		if b.limitsEnabled() {
+			return false, fmt.Errorf("failed to compute cascade impact: %w", err)
+		}
+		log.Error(err, "Failed to compute cascade impact")
+		return false, nil
+	}
+	log.Info("Cascade impact computed", "fanOut", impact.FanOut, "depth", impact.Depth, "workloads", impact.Workloads)
+
+	exceeded := b.exceededLimits(impact)
+	if len(exceeded) == 0 {
+		return false, nil
+	}
+
+	if val, ok := res.GetAnnotations()[flag.BlastRadiusOverrideAnnotation]; ok {
+		override, err := strconv.ParseBool(val)
+		if err != nil {
+			log.Error(err, fmt.Sprintf("Invalid annotation %q, limits apply", flag.BlastRadiusOverrideAnnotation))
+		}
+		if override {
+			log.Info("Blast radius limits exceeded, overridden by annotation", "exceeded", exceeded)
+			return false, nil
+		}
+	}
+
+	b.Recorder.Eventf(
+		res,
+		nil,
+		corev1.EventTypeWarning,
+		"BlastRadiusExceeded",
+		"CheckBlastRadius",
+		"Cascade blocked, %s exceeds the limits (%s): %s",
+		impact,
+		strings.Join(exceeded, ", "),
+		strings.Join(impact.Workloads, ", "),
+	)
+	log.Info("Cascade blocked by blast radius limits", "exceeded", exceeded)
+	return true, nil
+}
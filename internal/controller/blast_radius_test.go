@@ -0,0 +1,186 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestChainDepth(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		edges    map[string][]string
+		expected int
+	}{
+		{name: "No targets", edges: map[string][]string{}, expected: 0},
+		{name: "Chain", edges: map[string][]string{"a": {"b"}, "b": {"c"}}, expected: 2},
+		{name: "Longest of several chains", edges: map[string][]string{"a": {"b", "c"}, "b": {"d"}, "c": {"d"}, "d": {"e"}}, expected: 3},
+		{name: "Cycle terminates", edges: map[string][]string{"a": {"b"}, "b": {"a"}}, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, chainDepth(tt.edges, "a", map[string]int{}, map[string]bool{}))
+		})
+	}
+}
+
+func TestCascadeImpact(t *testing.T) {
+	t.Parallel()
+
+	reconciler := createBaseReconciler(newDiamond()...)
+
+	impact, err := reconciler.cascadeImpact(t.Context(), reconciler.newDependencyGraph(), "Deployment/default/a", []targets.Target{
+		targets.NewDeployment("default", "b", reconciler.KubeClient),
+		targets.NewDeployment("default", "c", reconciler.KubeClient),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, cascadeImpact{
+		FanOut:    2,
+		Workloads: []string{"Deployment/default/b", "Deployment/default/c", "Deployment/default/d"},
+		Depth:     2,
+	}, impact)
+	assert.Equal(t, "fan-out=2, workloads=3, depth=2", impact.String())
+}
+
+func TestExceededLimits(t *testing.T) {
+	t.Parallel()
+
+	impact := cascadeImpact{FanOut: 2, Workloads: []string{"b", "c", "d"}, Depth: 2}
+
+	tests := []struct {
+		name     string
+		fanOut   int
+		size     int
+		depth    int
+		expected []string
+	}{
+		{name: "No limits", expected: nil},
+		{name: "Within limits", fanOut: 2, size: 3, depth: 2, expected: nil},
+		{name: "Fan-out exceeded", fanOut: 1, expected: []string{"fan-out 2 > 1"}},
+		{name: "All exceeded", fanOut: 1, size: 2, depth: 1, expected: []string{"fan-out 2 > 1", "workloads 3 > 2", "depth 2 > 1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reconciler := createBaseReconciler()
+			reconciler.MaxFanOut = tt.fanOut
+			reconciler.MaxCascadeSize = tt.size
+			reconciler.MaxCascadeDepth = tt.depth
+			assert.Equal(t, tt.expected, reconciler.exceededLimits(impact))
+		})
+	}
+}
+
+func TestReconcileWorkload_BlastRadius(t *testing.T) {
+	t.Parallel()
+
+	b := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "b"}}
+
+	t.Run("Cascade exceeding limits is blocked", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler(newDiamond()...)
+		reconciler.MaxCascadeSize = 2
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "a"))
+		assert.Equal(
+			t,
+			"Warning BlastRadiusExceeded Cascade blocked, fan-out=2, workloads=3, depth=2 exceeds the limits (workloads 3 > 2): "+
+				"Deployment/default/b, Deployment/default/c, Deployment/default/d",
+			<-recorder.Events,
+		)
+		assert.False(t, restarted(t, reconciler.KubeClient, b))
+
+		a := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "a"}, a))
+		assert.NotContains(t, a.Annotations, flag.LastObservedRestartAnnotation)
+	})
+
+	t.Run("Override annotation lifts limits", func(t *testing.T) {
+		t.Parallel()
+
+		objs := newDiamond()
+		objs[0].SetAnnotations(map[string]string{
+			"cascader.tkb.ch/deployment":       "b,c",
+			flag.BlastRadiusOverrideAnnotation: "true",
+		})
+		reconciler := createBaseReconciler(objs...)
+		reconciler.MaxFanOut = 1
+
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "a"))
+		assert.True(t, restarted(t, reconciler.KubeClient, b))
+	})
+
+	t.Run("Cascade within limits restarts targets", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler(newDiamond()...)
+		reconciler.MaxFanOut = 2
+		reconciler.MaxCascadeSize = 3
+		reconciler.MaxCascadeDepth = 2
+
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "a"))
+		assert.True(t, restarted(t, reconciler.KubeClient, b))
+	})
+
+	t.Run("Impact cannot be computed", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler()
+		reconciler.MaxCascadeSize = 10
+		reconciler.KubeClient = fake.NewClientBuilder().
+			WithObjects(newDiamond()...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+					return errors.New("list failed")
+				},
+			}).
+			Build()
+
+		a := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "a"}, a))
+		_, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: a})
+		assert.EqualError(t, err, "failed to compute cascade impact: failed to list Deployment workloads: list failed")
+		assert.NotContains(t, a.Annotations, flag.LastObservedRestartAnnotation)
+		assert.False(t, restarted(t, reconciler.KubeClient, b))
+	})
+}
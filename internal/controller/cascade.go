@@ -0,0 +1,393 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/utils"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	"github.com/google/uuid"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cascadeStamp identifies the cascade a workload takes part in. It is persisted as JSON in an
+// annotation on the workload. An upstream stamps its targets before restarting them, with the
+// generation the target is expected to have once restarted. The target adopts the stamp when it
+// detects the restart, so that stamps of failed or superseded restarts are never adopted.
+type cascadeStamp struct {
+	ID         string `json:"id"`                   // ID of the cascade, shared by all workloads it reaches.
+	Root       string `json:"root"`                 // ID of the workload which started the cascade.
+	Generation int64  `json:"generation,omitempty"` // Expected generation of a stamped target, zero once adopted.
+}
+
+// joinState tracks the upstreams of a cascade which arrived at a target with several upstreams.
+// It is persisted as JSON in an annotation on the target, so that the target is restarted only once
+// per cascade, by the last upstream to arrive. Upstreams which do not restart the target record that
+// they skipped it, so that neither the target nor the workloads behind it wait for them.
+type joinState struct {
+	ID          string   `json:"id"`                    // ID of the cascade.
+	Arrived     []string `json:"arrived"`               // IDs of the upstreams which became stable.
+	Skipped     []string `json:"skipped,omitempty"`     // IDs of the upstreams which do not restart the target.
+	Dropped     string   `json:"dropped,omitempty"`     // Why the target is not restarted in this cascade, e.g. it is paused.
+	RestartedBy string   `json:"restartedBy,omitempty"` // ID of the upstream which restarted the target.
+}
+
+// loadCascadeStamp reads the cascade stamp from the workload annotations.
+// Returns nil if the workload was never stamped.
+func loadCascadeStamp(obj client.Object) (*cascadeStamp, error) {
+	val, ok := obj.GetAnnotations()[flag.CascadeAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	stamp := &cascadeStamp{}
+	if err := json.Unmarshal([]byte(val), stamp); err != nil {
+		return nil, fmt.Errorf("invalid annotation %q: %w", flag.CascadeAnnotation, err)
+	}
+	return stamp, nil
+}
+
+// loadJoinState reads the join state from the target annotations.
+// Returns nil if no upstream arrived yet.
+func loadJoinState(obj client.Object) (*joinState, error) {
+	val, ok := obj.GetAnnotations()[flag.CascadeArrivalsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	state := &joinState{}
+	if err := json.Unmarshal([]byte(val), state); err != nil {
+		return nil, fmt.Errorf("invalid annotation %q: %w", flag.CascadeArrivalsAnnotation, err)
+	}
+	return state, nil
+}
+
+// patchJSONAnnotation stores value as JSON annotation on obj. The patch fails with a conflict if obj
+// changed in the meantime, since several upstreams may update the same target concurrently.
+func (b *BaseReconciler) patchJSONAnnotation(ctx context.Context, obj client.Object, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to serialize annotation %q: %w", key, err)
+	}
+
+	original := obj.DeepCopyObject().(client.Object)
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[key] = string(data)
+	obj.SetAnnotations(annotations)
+
+	if err := b.KubeClient.Patch(ctx, obj, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+		return fmt.Errorf("failed to patch annotation %q: %w", key, err)
+	}
+	return nil
+}
+
+// adoptCascade determines the cascade of a workload which detected a restart. A stamp left by an
+// upstream for this very restart is adopted, otherwise the workload starts a new cascade.
+func (b *BaseReconciler) adoptCascade(ctx context.Context, workload workloads.Workload) (*cascadeStamp, error) {
+	res := workload.Resource()
+
+	stamp, err := loadCascadeStamp(res)
+	if err != nil || stamp == nil || stamp.Generation == 0 || stamp.Generation != res.GetGeneration() {
+		stamp = &cascadeStamp{ID: uuid.NewString(), Root: workload.ID()}
+	}
+	stamp.Generation = 0
+
+	if err := b.patchJSONAnnotation(ctx, res, flag.CascadeAnnotation, stamp); err != nil {
+		return nil, err
+	}
+	return stamp, nil
+}
+
+// stampCascade passes the cascade of the source on to a target it is about to restart. Only targets
+// with targets of their own are stamped, since the cascade ends at all other targets.
+func (b *BaseReconciler) stampCascade(ctx context.Context, source client.Object, t targets.Target) error {
+	stamp, err := loadCascadeStamp(source)
+	if err != nil || stamp == nil {
+		return err
+	}
+
+	obj := t.Resource()
+	if err := b.KubeClient.Get(ctx, client.ObjectKey{Namespace: t.Namespace(), Name: t.Name()}, obj); err != nil {
+		if kerrors.IsNotFound(err) {
+			// Missing targets are reported by the restart; re-runs of deleted Jobs keep their stamp.
+			return nil
+		}
+		return fmt.Errorf("failed to fetch %s: %w", t.ID(), err)
+	}
+	if !hasTargetAnnotation(obj, b.AnnotationKindMap) {
+		return nil
+	}
+
+	// Restarting the target changes its pod template and thereby increments its generation.
+	// Re-running a Job recreates it, so its generation starts over.
+	generation := obj.GetGeneration() + 1
+	if t.Kind() == kinds.JobKind {
+		generation = 1
+	}
+	return b.patchJSONAnnotation(ctx, obj, flag.CascadeAnnotation, &cascadeStamp{
+		ID:         stamp.ID,
+		Root:       stamp.Root,
+		Generation: generation,
+	})
+}
+
+// cascadeUpstreams returns the sorted IDs of the workloads reachable from the root of the cascade which
+// have the target as direct dependency, i.e. all upstreams which restart the target during the cascade.
+// Edges which were skipped in the cascade are not followed, see joinState.
+func (b *BaseReconciler) cascadeUpstreams(ctx context.Context, graph *dependencyGraph, stamp *cascadeStamp, targetID string) ([]string, error) {
+	edges, err := graph.Edges(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Walk the graph from the root to find all workloads the cascade reaches.
+	reachable := map[string]bool{stamp.Root: true}
+	queue := []string{stamp.Root}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, next := range edges[id] {
+			if !reachable[next] && !graph.skipped(stamp.ID, id, next) {
+				reachable[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	var upstreams []string
+	for id := range reachable {
+		if id != targetID && slices.Contains(edges[id], targetID) {
+			upstreams = append(upstreams, id)
+		}
+	}
+	slices.Sort(upstreams)
+	return upstreams, nil
+}
+
+// dependencyGraph holds the IDs of the targets of all annotated workloads, keyed by workload ID. Listing all
+// workloads is expensive, so the graph is built on first use and shared by all checks of a reconciliation.
+type dependencyGraph struct {
+	list  func(ctx context.Context) (map[string][]string, map[string]*joinState, error)
+	edges map[string][]string
+	joins map[string]*joinState
+	err   error
+	built bool
+}
+
+// newDependencyGraph returns a dependency graph which is built from the workloads on first use.
+func (b *BaseReconciler) newDependencyGraph() *dependencyGraph {
+	return &dependencyGraph{list: b.listDependencies}
+}
+
+// Edges returns the IDs of the targets of all annotated workloads, keyed by workload ID.
+// The returned map is shared and must not be modified.
+func (g *dependencyGraph) Edges(ctx context.Context) (map[string][]string, error) {
+	if !g.built {
+		g.edges, g.joins, g.err = g.list(ctx)
+		g.built = true
+	}
+	return g.edges, g.err
+}
+
+// skipped returns true if the edge from the upstream to the target is not taken in the given cascade,
+// because the upstream skipped the target or the target is not restarted at all. Requires a built graph.
+func (g *dependencyGraph) skipped(cascadeID, upstreamID, targetID string) bool {
+	state := g.joins[targetID]
+	if state == nil || state.ID != cascadeID {
+		return false
+	}
+	return state.Dropped != "" || slices.Contains(state.Skipped, upstreamID)
+}
+
+// listDependencies lists all annotated workloads and returns the IDs of their targets, along with their
+// join states, keyed by workload ID.
+func (b *BaseReconciler) listDependencies(ctx context.Context) (map[string][]string, map[string]*joinState, error) {
+	edges := make(map[string][]string)
+	joins := make(map[string]*joinState)
+
+	for _, kind := range []kinds.Kind{kinds.DeploymentKind, kinds.StatefulSetKind, kinds.DaemonSetKind, kinds.JobKind} {
+		list, err := newWorkloadList(kind)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := b.KubeClient.List(ctx, list); err != nil {
+			return nil, nil, fmt.Errorf("failed to list %s workloads: %w", kind, err)
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to extract %s workloads: %w", kind, err)
+		}
+
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok || !hasTargetAnnotation(obj, b.AnnotationKindMap) {
+				continue
+			}
+			ts, err := b.extractTargets(ctx, obj)
+			if err != nil {
+				// A workload with invalid annotations does not restart any target.
+				continue
+			}
+			id := utils.GenerateID(kind, obj.GetNamespace(), obj.GetName())
+			edges[id] = targetIDs(ts)
+			if state, err := loadJoinState(obj); err == nil && state != nil {
+				joins[id] = state
+			}
+		}
+	}
+
+	return edges, joins, nil
+}
+
+// join records the arrival of the source at a target with several upstreams in the cascade of the source.
+// The target is restarted by the last upstream to arrive; all other upstreams wait for it, unless force is
+// set. Targets which are not reached through several paths are restarted right away.
+func (b *BaseReconciler) join(
+	ctx context.Context,
+	workload workloads.Workload,
+	graph *dependencyGraph,
+	obj client.Object,
+	targetID string,
+	force bool,
+) (preflightDecision, string, error) {
+	stamp, err := loadCascadeStamp(workload.Resource())
+	if err != nil || stamp == nil {
+		return preflightRestart, "", err
+	}
+
+	upstreams, err := b.cascadeUpstreams(ctx, graph, stamp, targetID)
+	if err != nil {
+		return "", "", err
+	}
+	if len(upstreams) < 2 {
+		return preflightRestart, "", nil
+	}
+
+	state, err := loadJoinState(obj)
+	if err != nil || state == nil || state.ID != stamp.ID {
+		state = &joinState{ID: stamp.ID}
+	}
+
+	sourceID := workload.ID()
+	switch state.RestartedBy {
+	case sourceID:
+		// The restart is retried by the upstream which attempted it.
+		return preflightRestart, "", nil
+	case "":
+	default:
+		return preflightJoined, fmt.Sprintf("restarted by %s", state.RestartedBy), nil
+	}
+
+	if !slices.Contains(state.Arrived, sourceID) {
+		state.Arrived = append(state.Arrived, sourceID)
+	}
+	state.Skipped = slices.DeleteFunc(state.Skipped, func(id string) bool { return id == sourceID })
+
+	var missing []string
+	for _, id := range upstreams {
+		if !slices.Contains(state.Arrived, id) && !slices.Contains(state.Skipped, id) {
+			missing = append(missing, id)
+		}
+	}
+
+	decision, reason := preflightRestart, ""
+	if len(missing) == 0 || force {
+		state.RestartedBy, state.Dropped = sourceID, ""
+	} else {
+		decision, reason = preflightJoining, "waiting for "+strings.Join(missing, ", ")
+	}
+
+	if err := b.patchJSONAnnotation(ctx, obj, flag.CascadeArrivalsAnnotation, state); err != nil {
+		return "", "", err
+	}
+	return decision, reason, nil
+}
+
+// skipJoin records that the source does not restart the target in its cascade, so that the other upstreams
+// of the target do not wait for it. A non-empty reason marks the target as dropped from the cascade, so that
+// the workloads behind it do not wait for it either. Targets which are neither shared nor pass the cascade on
+// are not recorded.
+func (b *BaseReconciler) skipJoin(
+	ctx context.Context,
+	workload workloads.Workload,
+	graph *dependencyGraph,
+	obj client.Object,
+	targetID string,
+	reason string,
+) error {
+	stamp, err := loadCascadeStamp(workload.Resource())
+	if err != nil || stamp == nil {
+		return err
+	}
+
+	state, err := loadJoinState(obj)
+	if err != nil || state == nil || state.ID != stamp.ID {
+		if !hasTargetAnnotation(obj, b.AnnotationKindMap) {
+			upstreams, err := b.cascadeUpstreams(ctx, graph, stamp, targetID)
+			if err != nil || len(upstreams) < 2 {
+				return err
+			}
+		}
+		state = &joinState{ID: stamp.ID}
+	}
+
+	sourceID := workload.ID()
+	state.Arrived = slices.DeleteFunc(state.Arrived, func(id string) bool { return id == sourceID })
+	if !slices.Contains(state.Skipped, sourceID) {
+		state.Skipped = append(state.Skipped, sourceID)
+	}
+	if state.RestartedBy == sourceID {
+		state.RestartedBy = ""
+	}
+	if reason != "" {
+		state.Dropped = reason
+	}
+
+	return b.patchJSONAnnotation(ctx, obj, flag.CascadeArrivalsAnnotation, state)
+}
+
+// skipJoins records that the source does not restart the given targets in its cascade, see skipJoin.
+func (b *BaseReconciler) skipJoins(ctx context.Context, workload workloads.Workload, graph *dependencyGraph, skipped []targets.Target) {
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	for _, t := range skipped {
+		obj := t.Resource()
+		if err := b.KubeClient.Get(ctx, client.ObjectKey{Namespace: t.Namespace(), Name: t.Name()}, obj); err != nil {
+			if !kerrors.IsNotFound(err) {
+				log.Error(err, "Failed to fetch skipped target", "targetID", t.ID())
+			}
+			continue
+		}
+		if err := b.skipJoin(ctx, workload, graph, obj, t.ID(), ""); err != nil {
+			log.Error(err, "Failed to record skipped target", "targetID", t.ID())
+		}
+	}
+}
@@ -0,0 +1,359 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// reconcileDeployment fetches the named Deployment and reconciles it, like the Deployment reconciler does.
+func reconcileDeployment(t *testing.T, reconciler *BaseReconciler, name string) ctrl.Result {
+	t.Helper()
+
+	dep := &appsv1.Deployment{}
+	require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: name}, dep))
+	result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: dep})
+	require.NoError(t, err)
+	return result
+}
+
+// rollOut simulates the rollout triggered by a restart, which increments the generation of the Deployment.
+func rollOut(t *testing.T, c client.Client, name string) {
+	t.Helper()
+
+	dep := &appsv1.Deployment{}
+	require.NoError(t, c.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: name}, dep))
+	dep.Generation++
+	require.NoError(t, c.Update(t.Context(), dep))
+	dep.Status.ObservedGeneration = dep.Generation
+	require.NoError(t, c.Status().Update(t.Context(), dep))
+}
+
+// resetRestart removes the restart annotation Cascader set on the pod template of a target.
+func resetRestart(t *testing.T, c client.Client, name string) {
+	t.Helper()
+
+	dep := &appsv1.Deployment{}
+	require.NoError(t, c.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: name}, dep))
+	delete(dep.Spec.Template.Annotations, flag.LastObservedRestartAnnotation)
+	require.NoError(t, c.Update(t.Context(), dep))
+}
+
+// newDiamond returns the Deployments of the graph A→B, A→C, B→D, C→D and an unrelated E→D.
+func newDiamond() []client.Object {
+	return []client.Object{
+		newStableDeployment("a", map[string]string{"cascader.tkb.ch/deployment": "b,c"}),
+		newStableDeployment("b", map[string]string{"cascader.tkb.ch/deployment": "d"}),
+		newStableDeployment("c", map[string]string{"cascader.tkb.ch/deployment": "d"}),
+		newStableDeployment("d", nil),
+		newStableDeployment("e", map[string]string{"cascader.tkb.ch/deployment": "d"}),
+	}
+}
+
+func TestAdoptCascade(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		stamp   string
+		adopted bool
+	}{
+		{name: "Stamped for this restart", stamp: `{"id":"cascade-1","root":"Deployment/default/a","generation":1}`, adopted: true},
+		{name: "Stamped for another generation", stamp: `{"id":"cascade-1","root":"Deployment/default/a","generation":2}`},
+		{name: "Stamp already adopted", stamp: `{"id":"cascade-1","root":"Deployment/default/a"}`},
+		{name: "Invalid stamp", stamp: `{`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := newStableDeployment("b", map[string]string{flag.CascadeAnnotation: tt.stamp})
+			reconciler := createBaseReconciler(source)
+
+			stamp, err := reconciler.adoptCascade(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+			require.NoError(t, err)
+			if tt.adopted {
+				assert.Equal(t, &cascadeStamp{ID: "cascade-1", Root: "Deployment/default/a"}, stamp)
+			} else {
+				assert.NotEqual(t, "cascade-1", stamp.ID)
+				assert.Equal(t, "Deployment/default/b", stamp.Root)
+			}
+
+			persisted, err := loadCascadeStamp(source)
+			require.NoError(t, err)
+			assert.Equal(t, stamp, persisted)
+			assert.Zero(t, persisted.Generation)
+		})
+	}
+}
+
+func TestStampCascade(t *testing.T) {
+	t.Parallel()
+
+	source := newStableDeployment("a", map[string]string{flag.CascadeAnnotation: `{"id":"cascade-1","root":"Deployment/default/a"}`})
+	objs := append(newDiamond()[1:], source)
+	reconciler := createBaseReconciler(objs...)
+
+	t.Run("Target with targets", func(t *testing.T) {
+		t.Parallel()
+
+		target := newDiamond()[1]
+		require.NoError(t, reconciler.stampCascade(t.Context(), source, targets.NewDeployment("default", "b", reconciler.KubeClient)))
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(target), target))
+
+		stamp, err := loadCascadeStamp(target)
+		require.NoError(t, err)
+		assert.Equal(t, &cascadeStamp{ID: "cascade-1", Root: "Deployment/default/a", Generation: 2}, stamp)
+	})
+
+	t.Run("Target without targets", func(t *testing.T) {
+		t.Parallel()
+
+		target := newDiamond()[3]
+		require.NoError(t, reconciler.stampCascade(t.Context(), source, targets.NewDeployment("default", "d", reconciler.KubeClient)))
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(target), target))
+		assert.NotContains(t, target.GetAnnotations(), flag.CascadeAnnotation)
+	})
+}
+
+func TestCascadeUpstreams(t *testing.T) {
+	t.Parallel()
+
+	objs := newDiamond()
+	objs[2].SetAnnotations(map[string]string{
+		"cascader.tkb.ch/deployment":   "d",
+		flag.CascadeArrivalsAnnotation: `{"id":"cascade-2","arrived":[],"skipped":["Deployment/default/a"]}`,
+	})
+	reconciler := createBaseReconciler(objs...)
+
+	tests := []struct {
+		name     string
+		cascade  string
+		root     string
+		target   string
+		expected []string
+	}{
+		{name: "Diamond joins at shared dependent", root: "Deployment/default/a", target: "Deployment/default/d", expected: []string{"Deployment/default/b", "Deployment/default/c"}},
+		{name: "Single path", root: "Deployment/default/a", target: "Deployment/default/b", expected: []string{"Deployment/default/a"}},
+		{name: "Cascade started within the diamond", root: "Deployment/default/b", target: "Deployment/default/d", expected: []string{"Deployment/default/b"}},
+		{name: "Unrelated root", root: "Deployment/default/e", target: "Deployment/default/d", expected: []string{"Deployment/default/e"}},
+		{name: "Skipped edge is not followed", cascade: "cascade-2", root: "Deployment/default/a", target: "Deployment/default/d", expected: []string{"Deployment/default/b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stamp := &cascadeStamp{ID: "cascade-1", Root: tt.root}
+			if tt.cascade != "" {
+				stamp.ID = tt.cascade
+			}
+			upstreams, err := reconciler.cascadeUpstreams(t.Context(), reconciler.newDependencyGraph(), stamp, tt.target)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, upstreams)
+		})
+	}
+}
+
+func TestDependencyGraph(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Built once per reconciliation", func(t *testing.T) {
+		t.Parallel()
+
+		var lists atomic.Int32
+		reconciler := createBaseReconciler()
+		reconciler.KubeClient = fake.NewClientBuilder().
+			WithObjects(newDiamond()...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+					if _, ok := list.(*appsv1.DeploymentList); ok {
+						lists.Add(1)
+					}
+					return c.List(ctx, list, opts...)
+				},
+			}).
+			Build()
+
+		// The blast radius and the joins of both targets share the graph.
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "a"))
+		assert.Equal(t, int32(1), lists.Load())
+	})
+
+	t.Run("Built on first use", func(t *testing.T) {
+		t.Parallel()
+
+		graph := &dependencyGraph{list: func(context.Context) (map[string][]string, map[string]*joinState, error) {
+			return nil, nil, errors.New("list failed")
+		}}
+		assert.False(t, graph.built)
+
+		_, err := graph.Edges(t.Context())
+		assert.EqualError(t, err, "list failed")
+		assert.True(t, graph.built)
+	})
+}
+
+func TestReconcileWorkload_DiamondJoin(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Shared dependent restarts once after all upstreams", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler(newDiamond()...)
+		c := reconciler.KubeClient
+
+		// A restarts B and C and passes its cascade on.
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "a"))
+		assert.True(t, restarted(t, c, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "b"}}))
+		assert.True(t, restarted(t, c, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "c"}}))
+		rollOut(t, c, "b")
+		rollOut(t, c, "c")
+
+		// B arrives first and waits for C.
+		d := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "d"}}
+		assert.Equal(t, ctrl.Result{RequeueAfter: defaultRequeuAfter}, reconcileDeployment(t, reconciler, "b"))
+		assert.False(t, restarted(t, c, d))
+
+		// C arrives last and restarts D.
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "c"))
+		assert.True(t, restarted(t, c, d))
+		resetRestart(t, c, "d")
+
+		// After an operator restart, B resumes its deferral and finds D restarted by C.
+		resumed := createBaseReconciler()
+		resumed.KubeClient = c
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, resumed, "b"))
+		assert.False(t, restarted(t, c, d))
+
+		require.NoError(t, c.Get(t.Context(), client.ObjectKeyFromObject(d), d))
+		state, err := loadJoinState(d)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Deployment/default/b", "Deployment/default/c"}, state.Arrived)
+		assert.Equal(t, "Deployment/default/c", state.RestartedBy)
+
+		b := &appsv1.Deployment{}
+		require.NoError(t, c.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "b"}, b))
+		assert.NotContains(t, b.Annotations, flag.DeferredTargetsAnnotation)
+	})
+
+	t.Run("Upstream restarting on its own does not wait", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler(newDiamond()...)
+
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "b"))
+		assert.True(t, restarted(t, reconciler.KubeClient, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "d"}}))
+	})
+
+	t.Run("Missing upstream is given up on after timeout", func(t *testing.T) {
+		t.Parallel()
+
+		objs := newDiamond()
+		b := objs[1].(*appsv1.Deployment)
+		b.Annotations[flag.CascadeAnnotation] = `{"id":"cascade-1","root":"Deployment/default/a"}`
+		b.Annotations[flag.DeferredTargetsAnnotation] = `{"generation":1,"since":"` + time.Now().Add(-time.Hour).Format(time.RFC3339) + `","targets":["Deployment/default/d"]}`
+		d := objs[3].(*appsv1.Deployment)
+		d.Annotations = map[string]string{flag.CascadeArrivalsAnnotation: `{"id":"cascade-1","arrived":["Deployment/default/b"]}`}
+		reconciler := createBaseReconciler(objs...)
+
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "b"))
+		assert.True(t, restarted(t, reconciler.KubeClient, d))
+
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(d), d))
+		state, err := loadJoinState(d)
+		require.NoError(t, err)
+		assert.Equal(t, "Deployment/default/b", state.RestartedBy)
+	})
+	t.Run("Skipped upstream does not hold the join", func(t *testing.T) {
+		t.Parallel()
+
+		objs := newDiamond()
+		b := objs[1].(*appsv1.Deployment)
+		b.Annotations[flag.CascadeAnnotation] = `{"id":"cascade-1","root":"Deployment/default/a","generation":1}`
+		d := objs[3].(*appsv1.Deployment)
+		d.Annotations = map[string]string{flag.CascadeArrivalsAnnotation: `{"id":"cascade-1","arrived":[],"skipped":["Deployment/default/c"]}`}
+		reconciler := createBaseReconciler(objs...)
+
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "b"))
+		assert.True(t, restarted(t, reconciler.KubeClient, d))
+	})
+
+	t.Run("Paused upstream is dropped from the cascade", func(t *testing.T) {
+		t.Parallel()
+
+		objs := newDiamond()
+		objs[2].(*appsv1.Deployment).Spec.Paused = true
+		reconciler := createBaseReconciler(objs...)
+		c := reconciler.KubeClient
+
+		// A restarts B, skips the paused C and records it as dropped.
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "a"))
+		paused := &appsv1.Deployment{}
+		require.NoError(t, c.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "c"}, paused))
+		state, err := loadJoinState(paused)
+		require.NoError(t, err)
+		require.NotNil(t, state)
+		assert.Equal(t, "rollout is paused", state.Dropped)
+		assert.Equal(t, []string{"Deployment/default/a"}, state.Skipped)
+		rollOut(t, c, "b")
+
+		// B does not wait for C, which is not restarted in this cascade.
+		d := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "d"}}
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "b"))
+		assert.True(t, restarted(t, c, d))
+	})
+
+	t.Run("Waiting upstream gives up after join timeout", func(t *testing.T) {
+		t.Parallel()
+
+		objs := newDiamond()
+		b := objs[1].(*appsv1.Deployment)
+		b.Annotations[flag.CascadeAnnotation] = `{"id":"cascade-1","root":"Deployment/default/a"}`
+		b.Annotations[flag.DeferredTargetsAnnotation] = `{"generation":1,"since":"` + time.Now().Add(-10*time.Minute).Format(time.RFC3339) + `","targets":["Deployment/default/d"]}`
+		d := objs[3].(*appsv1.Deployment)
+		d.Annotations = map[string]string{flag.CascadeArrivalsAnnotation: `{"id":"cascade-1","arrived":["Deployment/default/b"]}`}
+		reconciler := createBaseReconciler(objs...)
+
+		// The stability timeout does not bound the join.
+		reconciler.StabilityTimeout = 5 * time.Minute
+		assert.Equal(t, ctrl.Result{RequeueAfter: defaultRequeuAfter}, reconcileDeployment(t, reconciler, "b"))
+		assert.False(t, restarted(t, reconciler.KubeClient, d))
+
+		reconciler.JoinTimeout = 10 * time.Minute
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "b"))
+		assert.True(t, restarted(t, reconciler.KubeClient, d))
+	})
+}
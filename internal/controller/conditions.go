@@ -0,0 +1,197 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"slices"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/utils"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Template fields of a source which can be used as conditions of an edge.
+const (
+	fieldImages      = "images"      // Images of all containers.
+	fieldEnv         = "env"         // Environment variables of all containers.
+	fieldCommand     = "command"     // Commands and arguments of all containers.
+	fieldResources   = "resources"   // Resource requests and limits of all containers.
+	fieldVolumes     = "volumes"     // Volumes of the Pods.
+	fieldAnnotations = "annotations" // Annotations of the pod template, e.g. set by "kubectl rollout restart".
+)
+
+// templateFieldNames lists the supported conditions of an edge.
+var templateFieldNames = []string{fieldImages, fieldEnv, fieldCommand, fieldResources, fieldVolumes, fieldAnnotations}
+
+// templateHashes returns a hash of every template field which can be used as condition of an edge.
+func templateHashes(tpl *corev1.PodTemplateSpec) (map[string]string, error) {
+	containers := slices.Concat(tpl.Spec.InitContainers, tpl.Spec.Containers)
+
+	images := make(map[string]string, len(containers))
+	env := make(map[string]any, len(containers))
+	command := make(map[string]any, len(containers))
+	resources := make(map[string]corev1.ResourceRequirements, len(containers))
+	for _, c := range containers {
+		images[c.Name] = c.Image
+		env[c.Name] = []any{c.Env, c.EnvFrom}
+		command[c.Name] = []any{c.Command, c.Args}
+		resources[c.Name] = c.Resources
+	}
+
+	fields := map[string]any{
+		fieldImages:      images,
+		fieldEnv:         env,
+		fieldCommand:     command,
+		fieldResources:   resources,
+		fieldVolumes:     tpl.Spec.Volumes,
+		fieldAnnotations: tpl.Annotations,
+	}
+
+	hashes := make(map[string]string, len(fields))
+	for name, value := range fields {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize template field %q: %w", name, err)
+		}
+		h := fnv.New64a()
+		_, _ = h.Write(data)
+		hashes[name] = fmt.Sprintf("%x", h.Sum64())
+	}
+	return hashes, nil
+}
+
+// loadTemplateHashes reads the template hashes recorded by the previous cascade of the source.
+// Returns nil if none were recorded.
+func loadTemplateHashes(obj client.Object) (map[string]string, error) {
+	val, ok := obj.GetAnnotations()[flag.TemplateHashesAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	hashes := map[string]string{}
+	if err := json.Unmarshal([]byte(val), &hashes); err != nil {
+		return nil, fmt.Errorf("invalid annotation %q: %w", flag.TemplateHashesAnnotation, err)
+	}
+	return hashes, nil
+}
+
+// hasConditions reports whether any edge of the source has conditions.
+func hasConditions(specs edgeSpecList) bool {
+	for _, spec := range specs {
+		if len(spec.When) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// changedFields returns the template fields of the source which changed since its previous cascade.
+// Returns false if the source has no previous cascade to compare with.
+func changedFields(workload workloads.Workload) ([]string, bool, error) {
+	previous, err := loadTemplateHashes(workload.Resource())
+	if err != nil || previous == nil {
+		return nil, false, err
+	}
+
+	current, err := templateHashes(workload.PodTemplateSpec())
+	if err != nil {
+		return nil, false, err
+	}
+
+	var changed []string
+	for _, name := range templateFieldNames {
+		if current[name] != previous[name] {
+			changed = append(changed, name)
+		}
+	}
+	return changed, true, nil
+}
+
+// filterConditions removes the targets whose edge conditions are not met, i.e. none of the template
+// fields listed in "when" changed since the previous cascade of the source. Without a previous cascade
+// the conditions cannot be evaluated and all targets are kept.
+func (b *BaseReconciler) filterConditions(workload workloads.Workload, targetList []targets.Target, observed bool) []targets.Target {
+	res := workload.Resource()
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	specs := edgeSpecs(res)
+	if !hasConditions(specs) {
+		return targetList
+	}
+
+	changed, ok, err := changedFields(workload)
+	if err != nil {
+		log.Error(err, "Cannot evaluate conditions, restarting all targets")
+	}
+	if !ok {
+		return targetList
+	}
+
+	kept := make([]targets.Target, 0, len(targetList))
+	for _, t := range targetList {
+		spec := specs.lookup(t)
+		if len(spec.When) == 0 || slices.ContainsFunc(spec.When, func(field string) bool {
+			return slices.Contains(changed, field)
+		}) {
+			kept = append(kept, t)
+			continue
+		}
+
+		if !observed {
+			// Report skipped targets only once per cascade.
+			log.Info("Skipping target whose conditions are not met", "targetID", t.ID(), "when", spec.When, "changed", changed)
+			b.Recorder.Eventf(
+				res,
+				nil,
+				corev1.EventTypeNormal,
+				"RestartSkipped",
+				"ResolveTargets",
+				"Cascader skipped restart of %s: none of %v changed",
+				t.ID(),
+				spec.When,
+			)
+		}
+	}
+	return kept
+}
+
+// recordTemplateHashes records the template hashes of a source with edge conditions, so that the next
+// cascade can determine which template fields changed.
+func (b *BaseReconciler) recordTemplateHashes(ctx context.Context, workload workloads.Workload) error {
+	res := workload.Resource()
+	if !hasConditions(edgeSpecs(res)) {
+		return nil
+	}
+
+	hashes, err := templateHashes(workload.PodTemplateSpec())
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(hashes)
+	if err != nil {
+		return fmt.Errorf("failed to serialize template hashes: %w", err)
+	}
+	return utils.PatchWorkloadAnnotation(ctx, b.KubeClient, res, flag.TemplateHashesAnnotation, string(data))
+}
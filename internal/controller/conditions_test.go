@@ -0,0 +1,145 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestTemplateHashes(t *testing.T) {
+	t.Parallel()
+
+	base := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "app:v1"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		mutate   func(tpl *corev1.PodTemplateSpec)
+		expected []string
+	}{
+		{name: "Unchanged", mutate: func(*corev1.PodTemplateSpec) {}},
+		{
+			name:     "Image changed",
+			mutate:   func(tpl *corev1.PodTemplateSpec) { tpl.Spec.Containers[0].Image = "app:v2" },
+			expected: []string{fieldImages},
+		},
+		{
+			name: "Env changed",
+			mutate: func(tpl *corev1.PodTemplateSpec) {
+				tpl.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}}
+			},
+			expected: []string{fieldEnv},
+		},
+		{
+			name:     "Args changed",
+			mutate:   func(tpl *corev1.PodTemplateSpec) { tpl.Spec.Containers[0].Args = []string{"--verbose"} },
+			expected: []string{fieldCommand},
+		},
+		{
+			name:     "Volumes changed",
+			mutate:   func(tpl *corev1.PodTemplateSpec) { tpl.Spec.Volumes = []corev1.Volume{{Name: "config"}} },
+			expected: []string{fieldVolumes},
+		},
+		{
+			name: "Restarted with kubectl",
+			mutate: func(tpl *corev1.PodTemplateSpec) {
+				tpl.Annotations = map[string]string{"kubectl.kubernetes.io/restartedAt": "2026-01-01T00:00:00Z"}
+			},
+			expected: []string{fieldAnnotations},
+		},
+	}
+
+	previous, err := templateHashes(&base)
+	require.NoError(t, err)
+	assert.Len(t, previous, len(templateFieldNames))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tpl := base.DeepCopy()
+			tt.mutate(tpl)
+			current, err := templateHashes(tpl)
+			require.NoError(t, err)
+
+			var changed []string
+			for _, name := range templateFieldNames {
+				if current[name] != previous[name] {
+					changed = append(changed, name)
+				}
+			}
+			assert.Equal(t, tt.expected, changed)
+		})
+	}
+}
+
+func TestReconcileWorkload_Conditions(t *testing.T) {
+	t.Parallel()
+
+	source := newStableDeployment("a", map[string]string{
+		flag.TargetsAnnotation: `{"version":"v1","targets":[` +
+			`{"kind":"Deployment","name":"b","when":["images"]},` +
+			`{"kind":"Deployment","name":"c","when":["env","volumes"]},` +
+			`{"kind":"Deployment","name":"d"}]}`,
+	})
+	source.Spec.Template.Spec.Containers = []corev1.Container{{Name: "app", Image: "app:v1"}}
+	b := newStableDeployment("b", nil)
+	c := newStableDeployment("c", nil)
+	d := newStableDeployment("d", nil)
+
+	recorder := events.NewFakeRecorder(10)
+	reconciler := createBaseReconciler(source, b.DeepCopy(), c.DeepCopy(), d.DeepCopy())
+	reconciler.Recorder = recorder
+
+	// Without a previous cascade, the conditions cannot be evaluated.
+	assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "a"))
+	assert.True(t, restarted(t, reconciler.KubeClient, b))
+	assert.True(t, restarted(t, reconciler.KubeClient, c))
+	assert.True(t, restarted(t, reconciler.KubeClient, d))
+
+	updated := &appsv1.Deployment{}
+	require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(source), updated))
+	assert.Contains(t, updated.Annotations, flag.TemplateHashesAnnotation)
+
+	for _, name := range []string{"b", "c", "d"} {
+		resetRestart(t, reconciler.KubeClient, name)
+		<-recorder.Events
+	}
+
+	// Only the image changes in the next restart.
+	updated.Spec.Template.Spec.Containers[0].Image = "app:v2"
+	require.NoError(t, reconciler.KubeClient.Update(t.Context(), updated))
+
+	assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "a"))
+	assert.True(t, restarted(t, reconciler.KubeClient, b))
+	assert.False(t, restarted(t, reconciler.KubeClient, c))
+	assert.True(t, restarted(t, reconciler.KubeClient, d))
+	assert.Equal(t, "Normal RestartSkipped Cascader skipped restart of Deployment/default/c: none of [env volumes] changed", <-recorder.Events)
+}
@@ -24,6 +24,9 @@ import (
 
 	"github.com/thurgauerkb/cascader/internal/targets"
 
+	batchv1 "k8s.io/api/batch/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -97,9 +100,18 @@ func (b *BaseReconciler) detectCycle(ctx context.Context, target targets.Target,
 	// Fetch target resource
 	res := target.Resource()
 	if err := b.KubeClient.Get(ctx, client.ObjectKey{Namespace: target.Namespace(), Name: target.Name()}, res); err != nil {
+		if kerrors.IsNotFound(err) {
+			// A missing target has no dependencies and cannot be part of a cycle.
+			return false, nil, nil
+		}
 		return false, nil, fmt.Errorf("failed to fetch resource %s: %w", targetID, err)
 	}
 
+	// Jobs created from a CronJob carry the annotations of its job template.
+	if cj, ok := res.(*batchv1.CronJob); ok {
+		res = jobTemplateSource(cj)
+	}
+
 	// Extract dependencies from resource
 	dependencies, err := b.extractTargets(ctx, res)
 	if err != nil {
@@ -125,3 +137,14 @@ func (b *BaseReconciler) detectCycle(ctx context.Context, target targets.Target,
 
 	return false, nil, nil
 }
+
+// jobTemplateSource returns a Job standing in for the Jobs created from the CronJob as sources.
+func jobTemplateSource(cj *batchv1.CronJob) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        cj.Name,
+			Namespace:   cj.Namespace,
+			Annotations: cj.Spec.JobTemplate.Annotations,
+		},
+	}
+}
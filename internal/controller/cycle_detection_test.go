@@ -22,9 +22,11 @@ import (
 
 	"github.com/thurgauerkb/cascader/internal/kinds"
 	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/test/testutils"
 
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -35,6 +37,7 @@ func TestCheckCycle(t *testing.T) {
 
 	scheme := runtime.NewScheme()
 	_ = appsv1.AddToScheme(scheme)
+	_ = batchv1.AddToScheme(scheme)
 
 	t.Run("No Cycle", func(t *testing.T) {
 		t.Parallel()
@@ -148,7 +151,54 @@ func TestCheckCycle(t *testing.T) {
 		assert.EqualError(t, err, "indirect cycle detected: adding dependency from Deployment/indirect-cycle/first creates a indirect cycle: Deployment/indirect-cycle/first -> Deployment/indirect-cycle/second -> Deployment/indirect-cycle/first")
 	})
 
-	t.Run("Error when fetching resource", func(t *testing.T) {
+	t.Run("Cycle through the job template of a CronJob", func(t *testing.T) {
+		t.Parallel()
+
+		dep := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cache",
+				Namespace: "cronjob-cycle",
+				Annotations: map[string]string{
+					"cascader.tkb.ch/cronjob": "cache-warmer",
+				},
+			},
+		}
+		cronJob := &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cache-warmer",
+				Namespace: "cronjob-cycle",
+			},
+			Spec: batchv1.CronJobSpec{
+				JobTemplate: batchv1.JobTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{
+							"cascader.tkb.ch/deployment": "cache",
+						},
+					},
+				},
+			},
+		}
+
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(dep, cronJob).Build()
+
+		reconciler := &BaseReconciler{
+			KubeClient: fakeClient,
+			AnnotationKindMap: kinds.AnnotationKindMap{
+				"cascader.tkb.ch/deployment": kinds.DeploymentKind,
+				"cascader.tkb.ch/cronjob":    kinds.CronJobKind,
+			},
+		}
+
+		srcID := "Deployment/cronjob-cycle/cache"
+		targetDeps := []targets.Target{
+			targets.NewCronJob("cronjob-cycle", "cache-warmer", srcID, fakeClient),
+		}
+
+		err := reconciler.checkCycle(t.Context(), srcID, targetDeps)
+		assert.EqualError(t, err, "indirect cycle detected: adding dependency from Deployment/cronjob-cycle/cache creates a indirect cycle: Deployment/cronjob-cycle/cache -> CronJob/cronjob-cycle/cache-warmer -> Deployment/cronjob-cycle/cache")
+	})
+
+	t.Run("Missing resource is not a cycle", func(t *testing.T) {
 		t.Parallel()
 
 		depA := &appsv1.Deployment{
@@ -186,8 +236,7 @@ func TestCheckCycle(t *testing.T) {
 		}
 
 		err := reconciler.checkCycle(t.Context(), srcID, targetDeps)
-		assert.Error(t, err)
-		assert.EqualError(t, err, "dependency cycle check failed: failed to fetch resource Deployment/error-fetching/non-existing: deployments.apps \"non-existing\" not found")
+		assert.NoError(t, err)
 	})
 
 	t.Run("Error when extracting dependencies", func(t *testing.T) {
@@ -260,7 +309,10 @@ func TestDetectCycle(t *testing.T) {
 		t.Parallel()
 
 		ctx := t.Context()
-		fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		fakeClient := &testutils.MockClientWithError{
+			Client:      fake.NewClientBuilder().WithScheme(scheme).Build(),
+			GetErrorFor: testutils.NamedError{Namespace: "test-namespace", Name: "test-deployment"},
+		}
 
 		target := targets.NewDeployment("test-namespace", "test-deployment", fakeClient)
 		depChain := []string{"root"}
@@ -274,7 +326,26 @@ func TestDetectCycle(t *testing.T) {
 		assert.False(t, hasCycle, "Expected no cycle detected")
 		assert.Nil(t, updatedChain, "Expected nil cycle path")
 		assert.Error(t, err)
-		assert.EqualError(t, err, "failed to fetch resource Deployment/test-namespace/test-deployment: deployments.apps \"test-deployment\" not found")
+		assert.EqualError(t, err, "failed to fetch resource Deployment/test-namespace/test-deployment: simulated get error")
+	})
+
+	t.Run("Missing resource", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := t.Context()
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		target := targets.NewDeployment("test-namespace", "test-deployment", fakeClient)
+
+		reconciler := &BaseReconciler{
+			KubeClient: fakeClient,
+		}
+
+		hasCycle, updatedChain, err := reconciler.detectCycle(ctx, target, "root", []string{"root"})
+
+		assert.NoError(t, err)
+		assert.False(t, hasCycle, "Expected no cycle detected")
+		assert.Nil(t, updatedChain, "Expected nil cycle path")
 	})
 
 	t.Run("Indirect cycle detected", func(t *testing.T) {
@@ -385,7 +456,7 @@ func TestDetectCycle(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
-	t.Run("Error during traversal - target not found", func(t *testing.T) {
+	t.Run("Traversal skips missing targets", func(t *testing.T) {
 		t.Parallel()
 
 		ctx := t.Context()
@@ -431,9 +502,8 @@ func TestDetectCycle(t *testing.T) {
 
 		hasCycle, cyclePath, err := reconciler.detectCycle(ctx, target, sourceID, traversalPath)
 
+		assert.NoError(t, err)
 		assert.False(t, hasCycle, "Expected no cycle detected")
 		assert.Nil(t, cyclePath, "Expected nil cycle path")
-		assert.Error(t, err)
-		assert.EqualError(t, err, "failed to fetch resource Deployment/test-ns/not-existing: deployments.apps \"not-existing\" not found")
 	})
 }
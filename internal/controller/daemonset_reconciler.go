@@ -20,13 +20,20 @@ import (
 	"context"
 	"errors"
 
+	"github.com/thurgauerkb/cascader/internal/kinds"
 	"github.com/thurgauerkb/cascader/internal/predicates"
+	"github.com/thurgauerkb/cascader/internal/recreation"
 	"github.com/thurgauerkb/cascader/internal/workloads"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // DaemonSetReconciler reconciles DaemonSets to detect restarts and target reloads.
@@ -45,6 +52,10 @@ func (r *DaemonSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	ds := &appsv1.DaemonSet{}
 	if err := r.KubeClient.Get(ctx, req.NamespacedName, ds); err != nil {
 		if kerrors.IsNotFound(err) {
+			if _, ok := r.Recreations.Lookup(req.NamespacedName); ok {
+				logger.Info("DaemonSet deleted; dependents will be restarted once it is recreated")
+				return ctrl.Result{}, nil
+			}
 			logger.Info("DaemonSet not found; ignoring since object must be deleted")
 			return ctrl.Result{}, nil
 		}
@@ -56,14 +67,40 @@ func (r *DaemonSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *DaemonSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&appsv1.DaemonSet{}).
-		WithEventFilter(predicates.NewPredicate(
-			r.AnnotationKindMap,
-			predicates.SpecChanged,
-			predicates.WrapSingleObjectCheck(predicates.DaemonSetTransitioning),
-			predicates.ScaledToZero,
-			predicates.ScaledFromZero,
-		)).
-		Complete(r)
+	if r.Recreations == nil {
+		r.Recreations = recreation.NewTracker()
+	}
+
+	// Predicates are set per watch, since Pod events must not be filtered by the workload predicates.
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1.DaemonSet{}, builder.WithPredicates(
+			predicate.Or(
+				predicates.Recreated(r.Recreations),
+				predicates.NewPredicate(
+					r.AnnotationKindMap,
+					predicates.SpecChanged,
+					predicates.WrapSingleObjectCheck(predicates.DaemonSetTransitioning),
+					predicates.ScaledToZero,
+					predicates.ScaledFromZero,
+					predicates.ImageDigestsChanged,
+					predicates.PodRestartsDetected,
+					predicates.CascadePending(r.LastObservedRestartAnnotation),
+				),
+			),
+		))
+
+	if r.WatchPods {
+		b = b.Watches(
+			&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.pendingOwnerOf(kinds.DaemonSetKind)),
+			builder.WithPredicates(predicates.PodReadinessChanged()),
+		)
+	}
+
+	// Resumed workloads bypass the predicates, as their cascade is already in flight.
+	if r.Resume != nil {
+		b = b.WatchesRawSource(source.Channel(r.Resume, &handler.EnqueueRequestForObject{}))
+	}
+
+	return b.Complete(r)
 }
@@ -0,0 +1,195 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/utils"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// evictionState tracks the targets of a source which are restarted by evicting their Pods.
+// It is persisted as JSON in an annotation on the source, so that every reconcile advances each
+// eviction by one step instead of blocking the worker while Pods are replaced.
+type evictionState struct {
+	Targets []targets.Eviction `json:"targets"` // Evictions in progress.
+}
+
+// loadEvictionState reads the eviction state from the source annotations.
+// Returns nil if no eviction is in progress.
+func loadEvictionState(obj client.Object) (*evictionState, error) {
+	val, ok := obj.GetAnnotations()[flag.PendingEvictionsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	state := &evictionState{}
+	if err := json.Unmarshal([]byte(val), state); err != nil {
+		return nil, fmt.Errorf("invalid annotation %q: %w", flag.PendingEvictionsAnnotation, err)
+	}
+	return state, nil
+}
+
+// saveEvictionState persists the eviction state on the source workload, removing it once all evictions finished.
+func (b *BaseReconciler) saveEvictionState(ctx context.Context, workload workloads.Workload, state *evictionState) error {
+	if len(state.Targets) == 0 {
+		if !hasAnnotation(workload.Resource(), flag.PendingEvictionsAnnotation) {
+			return nil
+		}
+		return utils.DeleteWorkloadAnnotation(ctx, b.KubeClient, workload.Resource(), flag.PendingEvictionsAnnotation)
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to serialize eviction state: %w", err)
+	}
+	return utils.PatchWorkloadAnnotation(ctx, b.KubeClient, workload.Resource(), flag.PendingEvictionsAnnotation, string(data))
+}
+
+// trackEviction persists the eviction of the target started by its trigger, so that the following
+// reconciles of the source advance it. An eviction of the target in progress is replaced.
+func (b *BaseReconciler) trackEviction(ctx context.Context, workload workloads.Workload, t *targets.EvictionTarget) error {
+	state, err := loadEvictionState(workload.Resource())
+	if err != nil || state == nil {
+		state = &evictionState{}
+	}
+	state.Targets = slices.DeleteFunc(state.Targets, func(e targets.Eviction) bool { return e.Target == t.ID() })
+
+	eviction := t.Eviction()
+	if !b.reportEvictionStep(workload, t, t.LastStep()) {
+		state.Targets = append(state.Targets, *eviction)
+	}
+
+	return b.saveEvictionState(ctx, workload, state)
+}
+
+// progressEvictions advances the evictions in progress of the source by one step each. Evictions which
+// fail or exceed their deadline are given up.
+func (b *BaseReconciler) progressEvictions(ctx context.Context, workload workloads.Workload, now time.Time) {
+	res := workload.Resource()
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	state, err := loadEvictionState(res)
+	if err != nil {
+		log.Error(err, "Discarding invalid eviction state")
+		state = &evictionState{}
+	}
+	if state == nil {
+		return
+	}
+
+	pending := make([]targets.Eviction, 0, len(state.Targets))
+	for _, eviction := range state.Targets {
+		kind, ns, name, err := utils.ParseID(eviction.Target)
+		if err != nil {
+			log.Error(err, "Discarding eviction of invalid target", "targetID", eviction.Target)
+			continue
+		}
+		t, err := targets.NewTarget(ctx, b.KubeClient, kind, ns+"/"+name, res)
+		if err != nil {
+			log.Error(err, "Discarding eviction of invalid target", "targetID", eviction.Target)
+			continue
+		}
+
+		evicting := targets.ResumeEviction(t, b.KubeClient, &eviction)
+		step, err := evicting.Step(ctx, now)
+		if err != nil {
+			log.Error(err, "Giving up eviction", "targetID", eviction.Target)
+			b.Recorder.Eventf(
+				res,
+				nil,
+				corev1.EventTypeWarning,
+				"EvictionFailed",
+				"EvictPod",
+				"Cascader failed to restart %q by eviction: %v",
+				eviction.Target,
+				err,
+			)
+			continue
+		}
+		if b.reportEvictionStep(workload, evicting, step) {
+			log.Info("Finished evicting target", "targetID", eviction.Target)
+			continue
+		}
+		pending = append(pending, eviction)
+	}
+	state.Targets = pending
+
+	if err := b.saveEvictionState(ctx, workload, state); err != nil {
+		log.Error(err, "Failed to persist eviction state")
+	}
+}
+
+// reportEvictionStep emits the events for a step of the eviction of the target and reports whether the eviction is done.
+func (b *BaseReconciler) reportEvictionStep(workload workloads.Workload, t *targets.EvictionTarget, step targets.EvictionStep) bool {
+	eviction := t.Eviction()
+
+	switch {
+	case step.Blocked != nil:
+		b.Recorder.Eventf(
+			workload.Resource(),
+			nil,
+			corev1.EventTypeWarning,
+			"EvictionBlocked",
+			"EvictPod",
+			"Eviction of pod %s/%s of %q blocked by a PodDisruptionBudget; retrying: %v",
+			t.Namespace(),
+			eviction.Pods[0],
+			t.ID(),
+			step.Blocked,
+		)
+	case step.Evicted != "":
+		b.Recorder.Eventf(
+			workload.Resource(),
+			nil,
+			corev1.EventTypeNormal,
+			"PodEvicted",
+			"EvictPod",
+			"Evicted pod %s/%s of %q (%d/%d)",
+			t.Namespace(),
+			step.Evicted,
+			t.ID(),
+			eviction.Total-len(eviction.Pods),
+			eviction.Total,
+		)
+	}
+
+	return step.Done
+}
+
+// requeueEvictions requeues the source after the default requeue interval while evictions are in progress,
+// unless the result already requeues it earlier.
+func (b *BaseReconciler) requeueEvictions(obj client.Object, result ctrl.Result) ctrl.Result {
+	if !hasAnnotation(obj, flag.PendingEvictionsAnnotation) {
+		return result
+	}
+	if result.RequeueAfter == 0 || result.RequeueAfter > b.RequeueAfterDefault {
+		result.RequeueAfter = b.RequeueAfterDefault
+	}
+	return result
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+	"github.com/thurgauerkb/cascader/test/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newEvictionFixture returns a source Deployment and a StatefulSet "db" with the given ready pods.
+func newEvictionFixture(pods ...string) []client.Object {
+	objs := []client.Object{
+		newStableDeployment("source", nil),
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: testutils.Int32Ptr(int32(len(pods))),
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			},
+		},
+	}
+	for _, name := range pods {
+		objs = append(objs, newEvictionPod(name, types.UID(name+"-uid"), true))
+	}
+	return objs
+}
+
+// newEvictionPod returns a Pod of the StatefulSet "db".
+func newEvictionPod(name string, uid types.UID, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       uid,
+			Labels:    map[string]string{"app": "db"},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func newEvictionReconciler(evict func() error, objs ...client.Object) *BaseReconciler {
+	reconciler := createBaseReconciler()
+	reconciler.EvictionTimeout = time.Minute
+	reconciler.KubeClient = fake.NewClientBuilder().
+		WithObjects(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceCreate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+				if err := evict(); err != nil {
+					return err
+				}
+				return c.SubResource(subResourceName).Create(ctx, obj, subResource, opts...)
+			},
+		}).
+		Build()
+	return reconciler
+}
+
+func podExists(t *testing.T, c client.Client, name string) bool {
+	t.Helper()
+
+	err := c.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: name}, &corev1.Pod{})
+	if kerrors.IsNotFound(err) {
+		return false
+	}
+	require.NoError(t, err)
+	return true
+}
+
+func TestEvictionProgress(t *testing.T) {
+	t.Parallel()
+
+	reconciler := newEvictionReconciler(func() error { return nil }, newEvictionFixture("db-0", "db-1")...)
+	source := &appsv1.Deployment{}
+	require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "source"}, source))
+	workload := &workloads.DeploymentWorkload{Deployment: source}
+	target := targets.NewStatefulSet("default", "db", reconciler.KubeClient)
+	now := time.Now()
+
+	// The highest ordinal is evicted right away.
+	require.NoError(t, reconciler.restartTarget(t.Context(), workload, target, targets.EvictStrategy))
+	assert.False(t, podExists(t, reconciler.KubeClient, "db-1"))
+	assert.True(t, podExists(t, reconciler.KubeClient, "db-0"))
+
+	state, err := loadEvictionState(source)
+	require.NoError(t, err)
+	require.Len(t, state.Targets, 1)
+	assert.Equal(t, []string{"db-0"}, state.Targets[0].Pods)
+	assert.Equal(t, "db-1", state.Targets[0].Evicted)
+	assert.WithinDuration(t, now.Add(time.Minute), state.Targets[0].Deadline, 5*time.Second)
+
+	// The source is requeued while the eviction is in progress.
+	assert.Equal(t, ctrl.Result{RequeueAfter: defaultRequeuAfter}, reconciler.requeueEvictions(source, ctrl.Result{}))
+
+	// The next pod is evicted once the replacement of the evicted pod is ready.
+	require.NoError(t, reconciler.KubeClient.Create(t.Context(), newEvictionPod("db-1", "db-1-replacement", true)))
+	reconciler.progressEvictions(t.Context(), workload, now)
+	assert.False(t, podExists(t, reconciler.KubeClient, "db-0"))
+	state, err = loadEvictionState(source)
+	require.NoError(t, err)
+	require.Len(t, state.Targets, 1)
+	assert.Empty(t, state.Targets[0].Pods)
+	assert.Equal(t, "db-0", state.Targets[0].Evicted)
+
+	// The eviction finishes once the last pod was replaced.
+	require.NoError(t, reconciler.KubeClient.Create(t.Context(), newEvictionPod("db-0", "db-0-replacement", true)))
+	reconciler.progressEvictions(t.Context(), workload, now)
+	assert.NotContains(t, source.GetAnnotations(), flag.PendingEvictionsAnnotation)
+	assert.Equal(t, ctrl.Result{}, reconciler.requeueEvictions(source, ctrl.Result{}))
+
+	recorder := reconciler.Recorder.(*events.FakeRecorder)
+	assert.Len(t, recorder.Events, 2)
+	assert.Contains(t, <-recorder.Events, `Evicted pod default/db-1 of "StatefulSet/default/db" (1/2)`)
+	assert.Contains(t, <-recorder.Events, `Evicted pod default/db-0 of "StatefulSet/default/db" (2/2)`)
+}
+
+func TestEvictionWaitsForReplacement(t *testing.T) {
+	t.Parallel()
+
+	reconciler := newEvictionReconciler(func() error { return nil }, newEvictionFixture("db-0", "db-1")...)
+	source := &appsv1.Deployment{}
+	require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "source"}, source))
+	workload := &workloads.DeploymentWorkload{Deployment: source}
+	target := targets.NewStatefulSet("default", "db", reconciler.KubeClient)
+	now := time.Now()
+
+	require.NoError(t, reconciler.restartTarget(t.Context(), workload, target, targets.EvictStrategy))
+
+	// The evicted pod is not replaced yet.
+	reconciler.progressEvictions(t.Context(), workload, now)
+	assert.True(t, podExists(t, reconciler.KubeClient, "db-0"))
+
+	// The replacement of the evicted pod is not ready yet.
+	require.NoError(t, reconciler.KubeClient.Create(t.Context(), newEvictionPod("db-1", "db-1-replacement", false)))
+	reconciler.progressEvictions(t.Context(), workload, now)
+	assert.True(t, podExists(t, reconciler.KubeClient, "db-0"))
+
+	// The StatefulSet controller has not observed the current generation yet.
+	require.NoError(t, reconciler.KubeClient.Delete(t.Context(), newEvictionPod("db-1", "", false)))
+	require.NoError(t, reconciler.KubeClient.Create(t.Context(), newEvictionPod("db-1", "db-1-replacement", true)))
+	sts := &appsv1.StatefulSet{}
+	require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "db"}, sts))
+	sts.Generation = 2
+	require.NoError(t, reconciler.KubeClient.Update(t.Context(), sts))
+	sts.Status.ObservedGeneration = 1
+	require.NoError(t, reconciler.KubeClient.Status().Update(t.Context(), sts))
+	reconciler.progressEvictions(t.Context(), workload, now)
+	assert.True(t, podExists(t, reconciler.KubeClient, "db-0"))
+
+	state, err := loadEvictionState(source)
+	require.NoError(t, err)
+	require.Len(t, state.Targets, 1)
+	assert.Equal(t, []string{"db-0"}, state.Targets[0].Pods)
+	assert.Equal(t, "db-1", state.Targets[0].Evicted)
+
+	// The next pod is evicted once the current generation is observed.
+	sts.Status.ObservedGeneration = 2
+	require.NoError(t, reconciler.KubeClient.Status().Update(t.Context(), sts))
+	reconciler.progressEvictions(t.Context(), workload, now)
+	assert.False(t, podExists(t, reconciler.KubeClient, "db-0"))
+}
+
+func TestEvictionBlocked(t *testing.T) {
+	t.Parallel()
+
+	reconciler := newEvictionReconciler(
+		func() error { return kerrors.NewTooManyRequests("disruption budget", 0) },
+		newEvictionFixture("db-0")...,
+	)
+	source := &appsv1.Deployment{}
+	require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "source"}, source))
+	workload := &workloads.DeploymentWorkload{Deployment: source}
+	target := targets.NewStatefulSet("default", "db", reconciler.KubeClient)
+	now := time.Now()
+
+	require.NoError(t, reconciler.restartTarget(t.Context(), workload, target, targets.EvictStrategy))
+	reconciler.progressEvictions(t.Context(), workload, now)
+	assert.True(t, podExists(t, reconciler.KubeClient, "db-0"))
+
+	state, err := loadEvictionState(source)
+	require.NoError(t, err)
+	require.Len(t, state.Targets, 1)
+	assert.True(t, state.Targets[0].Blocked)
+
+	// The block is reported only once.
+	recorder := reconciler.Recorder.(*events.FakeRecorder)
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "EvictionBlocked")
+
+	// The eviction is given up after the timeout.
+	reconciler.progressEvictions(t.Context(), workload, now.Add(2*time.Minute))
+	assert.NotContains(t, source.GetAnnotations(), flag.PendingEvictionsAnnotation)
+	assert.Len(t, recorder.Events, 1)
+	event := <-recorder.Events
+	assert.Contains(t, event, "EvictionFailed")
+	assert.Contains(t, event, "StatefulSet/default/db did not finish within the eviction timeout")
+}
+
+func TestEvictionFailed(t *testing.T) {
+	t.Parallel()
+
+	reconciler := newEvictionReconciler(
+		func() error { return kerrors.NewInternalError(assert.AnError) },
+		newEvictionFixture("db-0")...,
+	)
+	source := &appsv1.Deployment{}
+	require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "source"}, source))
+	workload := &workloads.DeploymentWorkload{Deployment: source}
+	target := targets.NewStatefulSet("default", "db", reconciler.KubeClient)
+
+	err := reconciler.restartTarget(t.Context(), workload, target, targets.EvictStrategy)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to evict pod default/db-0")
+	assert.NotContains(t, source.GetAnnotations(), flag.PendingEvictionsAnnotation)
+}
+
+func TestLoadEvictionState(t *testing.T) {
+	t.Parallel()
+
+	t.Run("No annotation", func(t *testing.T) {
+		t.Parallel()
+
+		state, err := loadEvictionState(&appsv1.Deployment{})
+		require.NoError(t, err)
+		assert.Nil(t, state)
+	})
+
+	t.Run("Invalid annotation", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := loadEvictionState(newStableDeployment("source", map[string]string{flag.PendingEvictionsAnnotation: "{"}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid annotation "cascader.tkb.ch/pending-evictions"`)
+	})
+}
@@ -0,0 +1,357 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/readycheck"
+	"github.com/thurgauerkb/cascader/internal/utils"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// stabilityGates holds the requirements a source must meet after it became stable,
+// before its targets are restarted.
+type stabilityGates struct {
+	Soak                  time.Duration     // Minimum duration the source must stay stable.
+	NoRestarts            bool              // Restart the soak if a container of the source restarts.
+	ReadyChecks           int               // Number of consecutive checks all Pods must pass readiness.
+	MetricsGate           *metricsCheck     // PromQL expression which must satisfy its threshold, evaluated once the Pod gates passed.
+	ReadyCheck            *readycheck.Check // External endpoint which must be ready, probed once all other gates passed.
+	ReadyCheckMaxFailures int               // Number of failed probes after which the cascade is aborted, 0 retries until the stability timeout.
+}
+
+// enabled returns true if any stability gate is configured.
+func (g stabilityGates) enabled() bool {
+	return g.Soak > 0 || g.NoRestarts || g.ReadyChecks > 0 || g.MetricsGate != nil || g.ReadyCheck != nil
+}
+
+// parseStabilityGates reads the stability gates from the source annotations.
+// Invalid annotations and ready checks rejected by the policy are reported together, naming each annotation.
+func parseStabilityGates(obj client.Object, policy readycheck.Policy) (stabilityGates, error) {
+	var (
+		gates stabilityGates
+		errs  []error
+	)
+	annotations := obj.GetAnnotations()
+
+	if val := strings.TrimSpace(annotations[flag.SoakAnnotation]); val != "" {
+		d, err := time.ParseDuration(val)
+		if err == nil && d < 0 {
+			err = errors.New("must not be negative")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid annotation %q: %w", flag.SoakAnnotation, err))
+		} else {
+			gates.Soak = d
+		}
+	}
+	if val := strings.TrimSpace(annotations[flag.SoakNoRestartsAnnotation]); val != "" {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid annotation %q: %w", flag.SoakNoRestartsAnnotation, err))
+		} else {
+			gates.NoRestarts = b
+		}
+	}
+	if val := strings.TrimSpace(annotations[flag.ConsecutiveReadyChecksAnnotation]); val != "" {
+		n, err := strconv.Atoi(val)
+		if err == nil && n < 0 {
+			err = errors.New("must not be negative")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid annotation %q: %w", flag.ConsecutiveReadyChecksAnnotation, err))
+		} else {
+			gates.ReadyChecks = n
+		}
+	}
+
+	if check, err := parseMetricsCheck(annotations, flag.MetricsGateAnnotation, flag.MetricsGateThresholdAnnotation); err != nil {
+		errs = append(errs, err)
+	} else {
+		gates.MetricsGate = check
+	}
+	if val := strings.TrimSpace(annotations[flag.ReadyCheckAnnotation]); val != "" {
+		check, err := parseReadyCheck(annotations, val, policy)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			gates.ReadyCheck = check
+		}
+	}
+	if val := strings.TrimSpace(annotations[flag.ReadyCheckRetriesAnnotation]); val != "" {
+		n, err := strconv.Atoi(val)
+		if err == nil && n < 0 {
+			err = errors.New("must not be negative")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid annotation %q: %w", flag.ReadyCheckRetriesAnnotation, err))
+		} else {
+			gates.ReadyCheckMaxFailures = n + 1
+		}
+	}
+
+	return gates, errors.Join(errs...)
+}
+
+// parseReadyCheck parses the external ready check of a source, together with its timeout and expected status codes.
+// The timeout is capped by the policy.
+func parseReadyCheck(annotations map[string]string, endpoint string, policy readycheck.Policy) (*readycheck.Check, error) {
+	check, err := readycheck.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid annotation %q: %w", flag.ReadyCheckAnnotation, err)
+	}
+
+	if val := strings.TrimSpace(annotations[flag.ReadyCheckTimeoutAnnotation]); val != "" {
+		d, err := time.ParseDuration(val)
+		if err == nil && d <= 0 {
+			err = errors.New("must be greater than 0")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid annotation %q: %w", flag.ReadyCheckTimeoutAnnotation, err)
+		}
+		check.Timeout = d
+	}
+	if val := strings.TrimSpace(annotations[flag.ReadyCheckStatusAnnotation]); val != "" {
+		codes, err := readycheck.ParseStatusCodes(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid annotation %q: %w", flag.ReadyCheckStatusAnnotation, err)
+		}
+		check.Statuses = codes
+	}
+
+	if err := policy.Apply(check); err != nil {
+		return nil, fmt.Errorf("invalid annotation %q: %w", flag.ReadyCheckAnnotation, err)
+	}
+	return check, nil
+}
+
+// gateState tracks the progress of the stability gates of a source. It is persisted as JSON
+// in an annotation on the source, so that the soak survives restarts of the operator.
+type gateState struct {
+	StableSince    time.Time        `json:"stableSince"`              // Start of the soak.
+	Restarts       map[string]int32 `json:"restarts,omitempty"`       // Container restart counts at the start of the soak.
+	ReadyChecks    int              `json:"readyChecks,omitempty"`    // Number of consecutive passed ready checks.
+	LastReadyCheck time.Time        `json:"lastReadyCheck,omitzero"`  // Time of the last counted ready check.
+	Failures       int              `json:"failures,omitempty"`       // Number of consecutive failed external ready checks.
+	MetricsFailing bool             `json:"metricsFailing,omitempty"` // Whether the failed metrics gate was reported.
+	Invalid        string           `json:"invalid,omitempty"`        // Reported error of invalid gate annotations.
+}
+
+// loadGateState reads the stability gate state from the source annotations.
+// Returns nil if the source did not reach the gates yet.
+func loadGateState(obj client.Object) (*gateState, error) {
+	val, ok := obj.GetAnnotations()[flag.StabilityGatesAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	state := &gateState{}
+	if err := json.Unmarshal([]byte(val), state); err != nil {
+		return nil, fmt.Errorf("invalid annotation %q: %w", flag.StabilityGatesAnnotation, err)
+	}
+	return state, nil
+}
+
+// saveGateState persists the stability gate state on the source workload, if it changed.
+func (b *BaseReconciler) saveGateState(ctx context.Context, workload workloads.Workload, state *gateState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to serialize stability gate state: %w", err)
+	}
+	if workload.Resource().GetAnnotations()[flag.StabilityGatesAnnotation] == string(data) {
+		return nil
+	}
+	return utils.PatchWorkloadAnnotation(ctx, b.KubeClient, workload.Resource(), flag.StabilityGatesAnnotation, string(data))
+}
+
+// clearGateState removes the stability gate state from the source workload, if present.
+func (b *BaseReconciler) clearGateState(ctx context.Context, workload workloads.Workload) error {
+	if !hasAnnotation(workload.Resource(), flag.StabilityGatesAnnotation) {
+		return nil
+	}
+	return utils.DeleteWorkloadAnnotation(ctx, b.KubeClient, workload.Resource(), flag.StabilityGatesAnnotation)
+}
+
+// gateResult is the outcome of evaluating the stability gates of a source.
+type gateResult struct {
+	Pending string        // Pending describes the gates which did not pass yet, empty once all gates passed.
+	Requeue time.Duration // Requeue is the interval after which the gates should be checked again.
+	Failed  string        // Failed describes why the cascade must be aborted, e.g. an exhausted ready check.
+}
+
+// checkStabilityGates evaluates the stability gates of a stable source by looking at its Pods,
+// querying its metrics gate and probing its external ready check. Ready checks of the Pods are counted
+// at most once per requeue interval, while the metrics gate and the external ready check are only
+// evaluated once the preceding gates passed. Invalid gate annotations hold the cascade until they are fixed,
+// after which the gates start over.
+func (b *BaseReconciler) checkStabilityGates(
+	ctx context.Context,
+	workload workloads.Workload,
+	requeueAfter time.Duration,
+) (gateResult, error) {
+	res := workload.Resource()
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	gates, gatesErr := parseStabilityGates(res, b.ReadyChecks)
+	if gatesErr == nil && !gates.enabled() {
+		return gateResult{}, nil
+	}
+
+	state, err := loadGateState(res)
+	if err != nil {
+		log.Error(err, "Discarding invalid stability gate state")
+	}
+
+	now := time.Now()
+	if gatesErr != nil {
+		return b.holdInvalidGates(ctx, workload, state, gatesErr, now, requeueAfter)
+	}
+	if state != nil && state.Invalid != "" {
+		state = nil
+	}
+
+	var pods []corev1.Pod
+	if gates.NoRestarts || gates.ReadyChecks > 0 {
+		if pods, err = workloads.ListPods(ctx, b.KubeClient, res); err != nil {
+			return gateResult{}, fmt.Errorf("failed to check stability gates of %s: %w", workload.ID(), err)
+		}
+	}
+
+	restarts := workloads.ContainerRestarts(pods)
+	if state == nil {
+		state = &gateState{StableSince: now, Restarts: restarts}
+	} else if gates.NoRestarts {
+		if restarted := workloads.RestartedContainers(state.Restarts, restarts); len(restarted) > 0 {
+			log.Info("Containers restarted during soak, restarting soak", "containers", restarted)
+			state = &gateState{StableSince: now, Restarts: restarts}
+		}
+	}
+
+	var pending []string
+	result := gateResult{Requeue: requeueAfter}
+
+	if remaining := gates.Soak - now.Sub(state.StableSince); remaining > 0 {
+		pending = append(pending, fmt.Sprintf("soak %s remaining", remaining.Round(time.Second)))
+		result.Requeue = min(result.Requeue, remaining)
+	}
+
+	if gates.ReadyChecks > 0 {
+		ready, reason := workloads.PodsReady(pods)
+		switch {
+		case !ready:
+			state.ReadyChecks, state.LastReadyCheck = 0, time.Time{}
+		case state.ReadyChecks < gates.ReadyChecks && now.Sub(state.LastReadyCheck) >= requeueAfter:
+			state.ReadyChecks, state.LastReadyCheck = state.ReadyChecks+1, now
+		}
+		if state.ReadyChecks < gates.ReadyChecks {
+			check := fmt.Sprintf("ready checks %d/%d", state.ReadyChecks, gates.ReadyChecks)
+			if !ready {
+				check += ": " + reason
+			}
+			pending = append(pending, check)
+		}
+	}
+
+	if gates.MetricsGate != nil && len(pending) == 0 {
+		if err := b.evaluateMetrics(ctx, gates.MetricsGate); err != nil {
+			if !state.MetricsFailing {
+				b.Recorder.Eventf(
+					res,
+					nil,
+					corev1.EventTypeWarning,
+					"MetricsGateFailed",
+					"EvaluateMetricsGate",
+					"Cascade paused, metrics gate failed: %s",
+					err,
+				)
+			}
+			state.MetricsFailing = true
+			pending = append(pending, fmt.Sprintf("metrics gate: %s", err))
+		} else {
+			state.MetricsFailing = false
+		}
+	}
+
+	if gates.ReadyCheck != nil && len(pending) == 0 {
+		if err := gates.ReadyCheck.Run(ctx); err != nil {
+			state.Failures++
+			if gates.ReadyCheckMaxFailures > 0 && state.Failures >= gates.ReadyCheckMaxFailures {
+				result.Failed = fmt.Sprintf("ready check failed (attempt %d of %d): %s", state.Failures, gates.ReadyCheckMaxFailures, err)
+				return result, nil
+			}
+			log.Info("Ready check failed", "check", gates.ReadyCheck.String(), "failures", state.Failures, "error", err.Error())
+			pending = append(pending, fmt.Sprintf("ready check: %s", err))
+		} else {
+			state.Failures = 0
+		}
+	}
+
+	if err := b.saveGateState(ctx, workload, state); err != nil {
+		return gateResult{}, fmt.Errorf("failed to patch stability gate state: %w", err)
+	}
+
+	if len(pending) == 0 {
+		return gateResult{}, nil
+	}
+	result.Pending = "stability gates pending: " + strings.Join(pending, ", ")
+	return result, nil
+}
+
+// holdInvalidGates pauses the cascade of a source with invalid stability gate annotations.
+// A Warning event naming the invalid annotations is emitted once per distinct error.
+func (b *BaseReconciler) holdInvalidGates(
+	ctx context.Context,
+	workload workloads.Workload,
+	state *gateState,
+	gatesErr error,
+	now time.Time,
+	requeueAfter time.Duration,
+) (gateResult, error) {
+	if state == nil {
+		state = &gateState{StableSince: now}
+	}
+	msg := strings.ReplaceAll(gatesErr.Error(), "\n", "; ")
+	if state.Invalid != msg {
+		b.Logger.Error(gatesErr, "Invalid stability gate annotation, pausing cascade", "workloadID", workload.ID())
+		b.Recorder.Eventf(
+			workload.Resource(),
+			nil,
+			corev1.EventTypeWarning,
+			"InvalidStabilityGate",
+			"CheckStabilityGates",
+			"Cascade paused, invalid stability gate: %s",
+			msg,
+		)
+	}
+	state.Invalid = msg
+
+	if err := b.saveGateState(ctx, workload, state); err != nil {
+		return gateResult{}, fmt.Errorf("failed to patch stability gate state: %w", err)
+	}
+	return gateResult{Pending: "invalid stability gate: " + msg, Requeue: requeueAfter}, nil
+}
@@ -0,0 +1,580 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/readycheck"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// newGatedSource returns a stable source with the given annotations, whose Pods are selected by "app=source".
+func newGatedSource(annotations map[string]string) *appsv1.Deployment {
+	source := newStableDeployment("source", annotations)
+	source.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "source"}}
+	return source
+}
+
+// newSourcePod returns a Pod of the gated source with the given readiness and restart count.
+func newSourcePod(name string, ready bool, restarts int32) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "source"}},
+		Status: corev1.PodStatus{
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", RestartCount: restarts}},
+		},
+	}
+}
+
+// gateStateJSON serializes the given gate state for use as annotation value.
+func gateStateJSON(t *testing.T, state gateState) string {
+	t.Helper()
+
+	data, err := json.Marshal(state)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestParseStabilityGates(t *testing.T) {
+	t.Parallel()
+
+	t.Run("No gates", func(t *testing.T) {
+		t.Parallel()
+
+		gates, err := parseStabilityGates(newGatedSource(nil), readycheck.Policy{})
+		require.NoError(t, err)
+		assert.False(t, gates.enabled())
+	})
+
+	t.Run("All gates", func(t *testing.T) {
+		t.Parallel()
+
+		gates, err := parseStabilityGates(newGatedSource(map[string]string{
+			flag.SoakAnnotation:                   "5m",
+			flag.SoakNoRestartsAnnotation:         "true",
+			flag.ConsecutiveReadyChecksAnnotation: "3",
+		}), readycheck.Policy{})
+		require.NoError(t, err)
+		assert.Equal(t, stabilityGates{Soak: 5 * time.Minute, NoRestarts: true, ReadyChecks: 3}, gates)
+	})
+
+	t.Run("Ready check", func(t *testing.T) {
+		t.Parallel()
+
+		gates, err := parseStabilityGates(newGatedSource(map[string]string{
+			flag.ReadyCheckAnnotation:        "http://svc.ns.svc:8080/ready",
+			flag.ReadyCheckTimeoutAnnotation: "2s",
+			flag.ReadyCheckStatusAnnotation:  "200,503",
+			flag.ReadyCheckRetriesAnnotation: "2",
+		}), readycheck.Policy{})
+		require.NoError(t, err)
+		require.NotNil(t, gates.ReadyCheck)
+		assert.Equal(t, "http://svc.ns.svc:8080/ready", gates.ReadyCheck.String())
+		assert.Equal(t, 2*time.Second, gates.ReadyCheck.Timeout)
+		assert.Equal(t, "200,503", gates.ReadyCheck.Statuses.String())
+		assert.Equal(t, 3, gates.ReadyCheckMaxFailures)
+	})
+
+	t.Run("Ready check timeout capped", func(t *testing.T) {
+		t.Parallel()
+
+		gates, err := parseStabilityGates(newGatedSource(map[string]string{
+			flag.ReadyCheckAnnotation:        "http://svc.ns.svc:8080/ready",
+			flag.ReadyCheckTimeoutAnnotation: "1h",
+		}), readycheck.Policy{MaxTimeout: 30 * time.Second})
+		require.NoError(t, err)
+		require.NotNil(t, gates.ReadyCheck)
+		assert.Equal(t, 30*time.Second, gates.ReadyCheck.Timeout)
+	})
+
+	t.Run("Invalid ready check", func(t *testing.T) {
+		t.Parallel()
+
+		tests := map[string]map[string]string{
+			`invalid annotation "cascader.tkb.ch/ready-check"`: {
+				flag.ReadyCheckAnnotation: "ftp://svc.ns.svc/ready",
+			},
+			`invalid annotation "cascader.tkb.ch/ready-check": endpoint http://169.254.169.254/latest is not an in-cluster Service`: {
+				flag.ReadyCheckAnnotation: "http://169.254.169.254/latest",
+			},
+			`invalid annotation "cascader.tkb.ch/ready-check-timeout": must be greater than 0`: {
+				flag.ReadyCheckAnnotation:        "tcp://svc.ns.svc:5432",
+				flag.ReadyCheckTimeoutAnnotation: "0s",
+			},
+			`invalid annotation "cascader.tkb.ch/ready-check-status"`: {
+				flag.ReadyCheckAnnotation:       "http://svc.ns.svc/ready",
+				flag.ReadyCheckStatusAnnotation: "ok",
+			},
+		}
+		for msg, annotations := range tests {
+			gates, err := parseStabilityGates(newGatedSource(annotations), readycheck.Policy{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), msg)
+			assert.Nil(t, gates.ReadyCheck)
+		}
+	})
+
+	t.Run("Invalid annotations disable their gates", func(t *testing.T) {
+		t.Parallel()
+
+		gates, err := parseStabilityGates(newGatedSource(map[string]string{
+			flag.SoakAnnotation:                   "-1m",
+			flag.SoakNoRestartsAnnotation:         "maybe",
+			flag.ConsecutiveReadyChecksAnnotation: "three",
+		}), readycheck.Policy{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid annotation "cascader.tkb.ch/soak": must not be negative`)
+		assert.Contains(t, err.Error(), `invalid annotation "cascader.tkb.ch/soak-no-restarts"`)
+		assert.Contains(t, err.Error(), `invalid annotation "cascader.tkb.ch/consecutive-ready-checks"`)
+		assert.False(t, gates.enabled())
+	})
+}
+
+func TestCheckStabilityGates(t *testing.T) {
+	t.Parallel()
+
+	t.Run("No gates", func(t *testing.T) {
+		t.Parallel()
+
+		source := newGatedSource(nil)
+		reconciler := createBaseReconciler(source)
+
+		result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Empty(t, result.Pending)
+		assert.NotContains(t, source.Annotations, flag.StabilityGatesAnnotation)
+	})
+
+	t.Run("Invalid annotation holds cascade", func(t *testing.T) {
+		t.Parallel()
+
+		source := newGatedSource(map[string]string{flag.SoakAnnotation: "-5m"})
+		reconciler := createBaseReconciler(source)
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+
+		for range 2 {
+			result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+			require.NoError(t, err)
+			assert.Equal(t, `invalid stability gate: invalid annotation "cascader.tkb.ch/soak": must not be negative`, result.Pending)
+			assert.Equal(t, defaultRequeuAfter, result.Requeue)
+		}
+		assert.Equal(t, `Warning InvalidStabilityGate Cascade paused, invalid stability gate: invalid annotation "cascader.tkb.ch/soak": must not be negative`, <-recorder.Events)
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("Fixed annotation starts gates over", func(t *testing.T) {
+		t.Parallel()
+
+		source := newGatedSource(map[string]string{
+			flag.SoakAnnotation:           "5m",
+			flag.StabilityGatesAnnotation: gateStateJSON(t, gateState{StableSince: time.Now().Add(-10 * time.Minute), Invalid: "invalid"}),
+		})
+		reconciler := createBaseReconciler(source)
+
+		result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Equal(t, "stability gates pending: soak 5m0s remaining", result.Pending)
+
+		state, err := loadGateState(source)
+		require.NoError(t, err)
+		assert.Empty(t, state.Invalid)
+	})
+
+	t.Run("Soak starts once stable", func(t *testing.T) {
+		t.Parallel()
+
+		source := newGatedSource(map[string]string{flag.SoakAnnotation: "5m"})
+		reconciler := createBaseReconciler(source)
+
+		result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Equal(t, "stability gates pending: soak 5m0s remaining", result.Pending)
+		assert.Equal(t, defaultRequeuAfter, result.Requeue)
+
+		state, err := loadGateState(source)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), state.StableSince, time.Minute)
+	})
+
+	t.Run("Soak requeues when it ends", func(t *testing.T) {
+		t.Parallel()
+
+		source := newGatedSource(map[string]string{
+			flag.SoakAnnotation:           "5m",
+			flag.StabilityGatesAnnotation: gateStateJSON(t, gateState{StableSince: time.Now().Add(-5*time.Minute + 3*time.Second)}),
+		})
+		reconciler := createBaseReconciler(source)
+
+		result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Contains(t, result.Pending, "soak")
+		assert.LessOrEqual(t, result.Requeue, 3*time.Second)
+	})
+
+	t.Run("Soak passed", func(t *testing.T) {
+		t.Parallel()
+
+		source := newGatedSource(map[string]string{
+			flag.SoakAnnotation:           "5m",
+			flag.StabilityGatesAnnotation: gateStateJSON(t, gateState{StableSince: time.Now().Add(-10 * time.Minute)}),
+		})
+		reconciler := createBaseReconciler(source)
+
+		result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Empty(t, result.Pending)
+	})
+
+	t.Run("Container restart restarts soak", func(t *testing.T) {
+		t.Parallel()
+
+		source := newGatedSource(map[string]string{
+			flag.SoakAnnotation:           "5m",
+			flag.SoakNoRestartsAnnotation: "true",
+			flag.StabilityGatesAnnotation: gateStateJSON(t, gateState{
+				StableSince: time.Now().Add(-10 * time.Minute),
+				Restarts:    map[string]int32{"source-0/app": 0},
+			}),
+		})
+		reconciler := createBaseReconciler(source, newSourcePod("source-0", true, 1))
+
+		result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Equal(t, "stability gates pending: soak 5m0s remaining", result.Pending)
+
+		state, err := loadGateState(source)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int32{"source-0/app": 1}, state.Restarts)
+	})
+
+	t.Run("Restarts are ignored without no-restarts gate", func(t *testing.T) {
+		t.Parallel()
+
+		source := newGatedSource(map[string]string{
+			flag.SoakAnnotation: "5m",
+			flag.StabilityGatesAnnotation: gateStateJSON(t, gateState{
+				StableSince: time.Now().Add(-10 * time.Minute),
+				Restarts:    map[string]int32{"source-0/app": 0},
+			}),
+		})
+		reconciler := createBaseReconciler(source, newSourcePod("source-0", true, 1))
+
+		result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Empty(t, result.Pending)
+	})
+
+	t.Run("Ready checks are counted once per interval", func(t *testing.T) {
+		t.Parallel()
+
+		source := newGatedSource(map[string]string{flag.ConsecutiveReadyChecksAnnotation: "2"})
+		reconciler := createBaseReconciler(source, newSourcePod("source-0", true, 0))
+		workload := &workloads.DeploymentWorkload{Deployment: source}
+
+		result, err := reconciler.checkStabilityGates(t.Context(), workload, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Equal(t, "stability gates pending: ready checks 1/2", result.Pending)
+
+		result, err = reconciler.checkStabilityGates(t.Context(), workload, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Equal(t, "stability gates pending: ready checks 1/2", result.Pending, "Expected the check within the interval not to count")
+
+		result, err = reconciler.checkStabilityGates(t.Context(), workload, 0)
+		require.NoError(t, err)
+		assert.Empty(t, result.Pending)
+	})
+
+	t.Run("Unready pod resets ready checks", func(t *testing.T) {
+		t.Parallel()
+
+		source := newGatedSource(map[string]string{
+			flag.ConsecutiveReadyChecksAnnotation: "2",
+			flag.StabilityGatesAnnotation: gateStateJSON(t, gateState{
+				StableSince:    time.Now().Add(-time.Minute),
+				ReadyChecks:    1,
+				LastReadyCheck: time.Now().Add(-time.Minute),
+			}),
+		})
+		reconciler := createBaseReconciler(source, newSourcePod("source-0", true, 0), newSourcePod("source-1", false, 0))
+
+		result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Equal(t, "stability gates pending: ready checks 0/2: pod source-1 is not ready", result.Pending)
+	})
+
+	t.Run("Reports all pending gates", func(t *testing.T) {
+		t.Parallel()
+
+		source := newGatedSource(map[string]string{
+			flag.SoakAnnotation:                   "1m",
+			flag.ConsecutiveReadyChecksAnnotation: "3",
+		})
+		reconciler := createBaseReconciler(source, newSourcePod("source-0", true, 0))
+
+		result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Equal(t, "stability gates pending: soak 1m0s remaining, ready checks 1/3", result.Pending)
+	})
+}
+
+func TestCheckStabilityGates_ReadyCheck(t *testing.T) {
+	t.Parallel()
+
+	// newServer returns a server responding with the given status code and counting its requests.
+	newServer := func(t *testing.T, status int) (*httptest.Server, *atomic.Int32) {
+		t.Helper()
+
+		var hits atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(server.Close)
+		return server, &hits
+	}
+	// newReconciler returns a reconciler allowed to probe the test servers.
+	newReconciler := func(source *appsv1.Deployment) *BaseReconciler {
+		reconciler := createBaseReconciler(source)
+		reconciler.ReadyChecks = readycheck.Policy{AllowedHosts: []string{"127.0.0.1"}}
+		return reconciler
+	}
+
+	t.Run("Endpoint ready", func(t *testing.T) {
+		t.Parallel()
+
+		server, hits := newServer(t, http.StatusOK)
+		source := newGatedSource(map[string]string{flag.ReadyCheckAnnotation: server.URL + "/ready"})
+		reconciler := newReconciler(source)
+
+		result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Equal(t, gateResult{}, result)
+		assert.Equal(t, int32(1), hits.Load())
+	})
+
+	t.Run("Endpoint not ready", func(t *testing.T) {
+		t.Parallel()
+
+		server, _ := newServer(t, http.StatusServiceUnavailable)
+		source := newGatedSource(map[string]string{flag.ReadyCheckAnnotation: server.URL})
+		reconciler := newReconciler(source)
+
+		result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Equal(t, "stability gates pending: ready check: http check "+server.URL+" returned status 503, expected 2xx", result.Pending)
+		assert.Equal(t, defaultRequeuAfter, result.Requeue)
+		assert.Empty(t, result.Failed)
+
+		state, err := loadGateState(source)
+		require.NoError(t, err)
+		assert.Equal(t, 1, state.Failures)
+	})
+
+	t.Run("Probed only once other gates passed", func(t *testing.T) {
+		t.Parallel()
+
+		server, hits := newServer(t, http.StatusOK)
+		source := newGatedSource(map[string]string{
+			flag.ReadyCheckAnnotation: server.URL,
+			flag.SoakAnnotation:       "5m",
+		})
+		reconciler := newReconciler(source)
+
+		result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Equal(t, "stability gates pending: soak 5m0s remaining", result.Pending)
+		assert.Zero(t, hits.Load())
+	})
+
+	t.Run("Retries exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		server, _ := newServer(t, http.StatusServiceUnavailable)
+		source := newGatedSource(map[string]string{
+			flag.ReadyCheckAnnotation:        server.URL,
+			flag.ReadyCheckRetriesAnnotation: "1",
+			flag.StabilityGatesAnnotation:    gateStateJSON(t, gateState{StableSince: time.Now(), Failures: 1}),
+		})
+		reconciler := newReconciler(source)
+
+		result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Equal(t, "ready check failed (attempt 2 of 2): http check "+server.URL+" returned status 503, expected 2xx", result.Failed)
+	})
+
+	t.Run("Success resets failures", func(t *testing.T) {
+		t.Parallel()
+
+		server, _ := newServer(t, http.StatusOK)
+		source := newGatedSource(map[string]string{
+			flag.ReadyCheckAnnotation:     server.URL,
+			flag.StabilityGatesAnnotation: gateStateJSON(t, gateState{StableSince: time.Now(), Failures: 3}),
+		})
+		reconciler := newReconciler(source)
+
+		result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Empty(t, result.Pending)
+
+		state, err := loadGateState(source)
+		require.NoError(t, err)
+		assert.Zero(t, state.Failures)
+	})
+
+	t.Run("Aborts cascade once retries are exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		server, _ := newServer(t, http.StatusServiceUnavailable)
+		source := newGatedSource(map[string]string{
+			"cascader.tkb.ch/deployment":            "target",
+			"cascader.tkb.ch/last-observed-restart": time.Now().Format(time.RFC3339),
+			flag.ReadyCheckAnnotation:               server.URL,
+			flag.ReadyCheckRetriesAnnotation:        "0",
+		})
+		target := newStableDeployment("target", nil)
+		reconciler := createBaseReconciler(source, target)
+		reconciler.ReadyChecks = readycheck.Policy{AllowedHosts: []string{"127.0.0.1"}}
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.Equal(t, "Warning CascadeAborted Cascade aborted: ready check failed (attempt 1 of 1): http check "+server.URL+" returned status 503, expected 2xx", <-recorder.Events)
+
+		updatedTarget := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(target), updatedTarget))
+		assert.NotContains(t, updatedTarget.Spec.Template.Annotations, flag.LastObservedRestartAnnotation)
+
+		updatedSource := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(source), updatedSource))
+		assert.NotContains(t, updatedSource.Annotations, flag.StabilityGatesAnnotation)
+		assert.NotContains(t, updatedSource.Annotations, "cascader.tkb.ch/last-observed-restart")
+	})
+}
+
+func TestReconcileWorkload_StabilityGates(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Waits for soak before restarting targets", func(t *testing.T) {
+		t.Parallel()
+
+		source := newGatedSource(map[string]string{
+			"cascader.tkb.ch/deployment": "target",
+			flag.SoakAnnotation:          "5m",
+		})
+		target := newStableDeployment("target", nil)
+		reconciler := createBaseReconciler(source, target)
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: defaultRequeuAfter}, result)
+
+		updatedTarget := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(target), updatedTarget))
+		assert.NotContains(t, updatedTarget.Spec.Template.Annotations, flag.LastObservedRestartAnnotation)
+	})
+
+	t.Run("Invalid ready check holds cascade", func(t *testing.T) {
+		t.Parallel()
+
+		source := newGatedSource(map[string]string{
+			"cascader.tkb.ch/deployment":            "target",
+			"cascader.tkb.ch/last-observed-restart": time.Now().Add(-10 * time.Minute).Format(time.RFC3339),
+			flag.ReadyCheckAnnotation:               "http://169.254.169.254/latest",
+		})
+		target := newStableDeployment("target", nil)
+		reconciler := createBaseReconciler(source, target)
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: defaultRequeuAfter}, result)
+		assert.Contains(t, <-recorder.Events, `Warning InvalidStabilityGate Cascade paused, invalid stability gate: invalid annotation "cascader.tkb.ch/ready-check"`)
+
+		updatedTarget := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(target), updatedTarget))
+		assert.NotContains(t, updatedTarget.Spec.Template.Annotations, flag.LastObservedRestartAnnotation)
+	})
+
+	t.Run("Restarts targets after soak and clears state", func(t *testing.T) {
+		t.Parallel()
+
+		source := newGatedSource(map[string]string{
+			"cascader.tkb.ch/deployment":            "target",
+			"cascader.tkb.ch/last-observed-restart": time.Now().Add(-10 * time.Minute).Format(time.RFC3339),
+			flag.SoakAnnotation:                     "5m",
+			flag.StabilityGatesAnnotation:           gateStateJSON(t, gateState{StableSince: time.Now().Add(-6 * time.Minute)}),
+		})
+		target := newStableDeployment("target", nil)
+		reconciler := createBaseReconciler(source, target)
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+
+		updatedTarget := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(target), updatedTarget))
+		assert.Contains(t, updatedTarget.Spec.Template.Annotations, flag.LastObservedRestartAnnotation)
+
+		updatedSource := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(source), updatedSource))
+		assert.NotContains(t, updatedSource.Annotations, flag.StabilityGatesAnnotation)
+	})
+
+	t.Run("Unstable source starts gates over", func(t *testing.T) {
+		t.Parallel()
+
+		source := newGatedSource(map[string]string{
+			"cascader.tkb.ch/deployment":            "target",
+			"cascader.tkb.ch/last-observed-restart": time.Now().Format(time.RFC3339),
+			flag.SoakAnnotation:                     "5m",
+			flag.StabilityGatesAnnotation:           gateStateJSON(t, gateState{StableSince: time.Now().Add(-4 * time.Minute)}),
+		})
+		source.Status.UpdatedReplicas = 0
+		reconciler := createBaseReconciler(source, newStableDeployment("target", nil))
+
+		_, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+
+		updatedSource := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(source), updatedSource))
+		assert.NotContains(t, updatedSource.Annotations, flag.StabilityGatesAnnotation)
+	})
+}
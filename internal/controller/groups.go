@@ -0,0 +1,73 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// groupPrefix marks a reference to a named target group, e.g. "group:payments-consumers".
+const groupPrefix = "group:"
+
+// targetGroups maps the names of target groups to the references of their members.
+type targetGroups map[string][]string
+
+// parseTargetGroups parses the data of the target groups ConfigMap. Each key names a group and holds
+// references separated by commas or whitespace, so that members can be listed one per line.
+func parseTargetGroups(data map[string]string) targetGroups {
+	groups := make(targetGroups, len(data))
+	for name, val := range data {
+		groups[name] = strings.FieldsFunc(val, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+	}
+	return groups
+}
+
+// members returns the member references of the group reached through path, the groups expanded so far.
+// Groups which reference themselves, directly or through other groups, are rejected.
+func (g targetGroups) members(name string, path []string) ([]string, error) {
+	if slices.Contains(path, name) {
+		return nil, fmt.Errorf("target group cycle: %s", strings.Join(append(slices.Clone(path), name), " -> "))
+	}
+	refs, ok := g[name]
+	if !ok {
+		return nil, fmt.Errorf("target group %q not found", name)
+	}
+	return refs, nil
+}
+
+// loadTargetGroups reads the target groups from their ConfigMap. The ConfigMap is read from the watched
+// cache on every call, so that changed groups are picked up by the next cascade without a restart.
+func (b *BaseReconciler) loadTargetGroups(ctx context.Context) (targetGroups, error) {
+	if b.TargetGroups.Name == "" {
+		return nil, errors.New("target groups are not configured")
+	}
+
+	var cm corev1.ConfigMap
+	if err := b.KubeClient.Get(ctx, b.TargetGroups, &cm); err != nil {
+		return nil, fmt.Errorf("failed to get target groups %s: %w", b.TargetGroups, err)
+	}
+	return parseTargetGroups(cm.Data), nil
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// newTargetGroups returns the ConfigMap defining the given target groups.
+func newTargetGroups(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cascader-system", Name: "target-groups"},
+		Data:       data,
+	}
+}
+
+// createGroupReconciler returns a BaseReconciler reading target groups from the ConfigMap of newTargetGroups.
+func createGroupReconciler(objects ...client.Object) *BaseReconciler {
+	reconciler := createBaseReconciler(objects...)
+	reconciler.TargetGroups = types.NamespacedName{Namespace: "cascader-system", Name: "target-groups"}
+	return reconciler
+}
+
+func TestParseTargetGroups(t *testing.T) {
+	t.Parallel()
+
+	groups := parseTargetGroups(map[string]string{
+		"payments-consumers": "ledger, billing,jobs/settlement",
+		"workers":            "worker-1\nworker-2\n\n",
+		"empty":              "",
+	})
+
+	assert.Equal(t, targetGroups{
+		"payments-consumers": {"ledger", "billing", "jobs/settlement"},
+		"workers":            {"worker-1", "worker-2"},
+		"empty":              {},
+	}, groups)
+}
+
+func TestTargetGroupsMembers(t *testing.T) {
+	t.Parallel()
+
+	groups := targetGroups{"a": {"group:b"}, "b": {"api"}}
+
+	t.Run("Known group", func(t *testing.T) {
+		t.Parallel()
+
+		members, err := groups.members("b", []string{"a"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"api"}, members)
+	})
+
+	t.Run("Unknown group", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := groups.members("c", nil)
+		assert.EqualError(t, err, `target group "c" not found`)
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		t.Parallel()
+
+		path := []string{"a", "b"}
+		_, err := groups.members("a", path)
+		assert.EqualError(t, err, "target group cycle: a -> b -> a")
+		assert.Equal(t, []string{"a", "b"}, path)
+	})
+}
+
+func TestResolveTargetGroups(t *testing.T) {
+	t.Parallel()
+
+	groups := newTargetGroups(map[string]string{
+		"payments-consumers": "ledger, billing\njobs/settlement",
+		"all-payments":       "group:payments-consumers, fraud",
+		"loop-a":             "api, group:loop-b",
+		"loop-b":             "group:loop-a",
+	})
+
+	t.Run("Members of a group", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{"cascader.tkb.ch/deployment": "group:payments-consumers"})
+		reconciler := createGroupReconciler(source, groups)
+
+		resolved, err := reconciler.extractTargets(t.Context(), source)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"Deployment/default/ledger",
+			"Deployment/default/billing",
+			"Deployment/jobs/settlement",
+		}, targetIDs(resolved))
+	})
+
+	t.Run("Members have the kind of the annotation", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{"cascader.tkb.ch/statefulset": "group:payments-consumers"})
+		reconciler := createGroupReconciler(source, groups)
+
+		resolved, err := reconciler.extractTargets(t.Context(), source)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"StatefulSet/default/ledger",
+			"StatefulSet/default/billing",
+			"StatefulSet/jobs/settlement",
+		}, targetIDs(resolved))
+	})
+
+	t.Run("Nested groups and direct references are resolved once", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{"cascader.tkb.ch/deployment": "billing, group:all-payments"})
+		reconciler := createGroupReconciler(source, groups)
+
+		resolved, err := reconciler.extractTargets(t.Context(), source)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"Deployment/default/billing",
+			"Deployment/default/ledger",
+			"Deployment/jobs/settlement",
+			"Deployment/default/fraud",
+		}, targetIDs(resolved))
+	})
+
+	t.Run("Patterns in groups", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{"cascader.tkb.ch/deployment": "group:workers"})
+		reconciler := createGroupReconciler(
+			source,
+			newStableDeployment("worker-1", nil),
+			newStableDeployment("worker-2", nil),
+			newTargetGroups(map[string]string{"workers": "worker-*"}),
+		)
+
+		resolved, patterns, err := reconciler.resolveTargets(t.Context(), source)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Deployment/default/worker-1", "Deployment/default/worker-2"}, targetIDs(resolved))
+		require.Len(t, patterns, 1)
+		assert.Equal(t, "worker-*", patterns[0].Ref)
+	})
+
+	t.Run("Cycle between groups", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{"cascader.tkb.ch/deployment": "group:loop-a"})
+		reconciler := createGroupReconciler(source, groups)
+
+		_, err := reconciler.extractTargets(t.Context(), source)
+		assert.EqualError(t, err, `cannot resolve targets "group:loop-a": target group cycle: loop-a -> loop-b -> loop-a`)
+	})
+
+	t.Run("Unknown group", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{"cascader.tkb.ch/deployment": "group:unknown"})
+		reconciler := createGroupReconciler(source, groups)
+
+		_, err := reconciler.extractTargets(t.Context(), source)
+		assert.EqualError(t, err, `cannot resolve targets "group:unknown": target group "unknown" not found`)
+	})
+
+	t.Run("Missing ConfigMap", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{"cascader.tkb.ch/deployment": "group:payments-consumers"})
+		reconciler := createGroupReconciler(source)
+
+		_, err := reconciler.extractTargets(t.Context(), source)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get target groups cascader-system/target-groups")
+	})
+
+	t.Run("Groups not configured", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{"cascader.tkb.ch/deployment": "group:payments-consumers"})
+		reconciler := createBaseReconciler(source, groups)
+
+		_, err := reconciler.extractTargets(t.Context(), source)
+		assert.EqualError(t, err, `cannot resolve targets "group:payments-consumers": target groups are not configured`)
+	})
+
+	t.Run("Changed groups are picked up", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{"cascader.tkb.ch/deployment": "group:workers"})
+		cm := newTargetGroups(map[string]string{"workers": "worker-1"})
+		reconciler := createGroupReconciler(source, cm)
+
+		resolved, err := reconciler.extractTargets(t.Context(), source)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Deployment/default/worker-1"}, targetIDs(resolved))
+
+		cm.Data["workers"] = "worker-1, worker-2"
+		require.NoError(t, reconciler.KubeClient.Update(t.Context(), cm))
+
+		resolved, err = reconciler.extractTargets(t.Context(), source)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Deployment/default/worker-1", "Deployment/default/worker-2"}, targetIDs(resolved))
+	})
+
+	t.Run("Cycle between workloads through a group", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{"cascader.tkb.ch/deployment": "group:consumers"})
+		consumer := newStableDeployment("consumer", map[string]string{"cascader.tkb.ch/deployment": "source"})
+		reconciler := createGroupReconciler(source, consumer, newTargetGroups(map[string]string{"consumers": "consumer"}))
+
+		resolved, err := reconciler.extractTargets(t.Context(), source)
+		require.NoError(t, err)
+
+		err = reconciler.checkCycle(t.Context(), "Deployment/default/source", resolved)
+		var cycleErr *CycleError
+		require.ErrorAs(t, err, &cycleErr)
+		assert.Equal(t, "Deployment/default/source -> Deployment/default/consumer -> Deployment/default/source", cycleErr.Path)
+	})
+}
+
+func TestTargetSpecRejectsGroups(t *testing.T) {
+	t.Parallel()
+
+	spec := targetSpec{Kind: "Deployment", Name: "group:payments-consumers"}
+	assert.EqualError(t, spec.validate(), "target groups are only supported in the per-kind annotations")
+}
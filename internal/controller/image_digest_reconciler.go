@@ -0,0 +1,129 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/internal/predicates"
+	"github.com/thurgauerkb/cascader/internal/utils"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ImageDigestReconciler records the resolved image digests of the Pods of source workloads of one kind.
+// A changed digest without a changed pod template updates the annotation on the source, which is picked
+// up by the workload reconciler as a restart.
+type ImageDigestReconciler struct {
+	KubeClient        client.Client           // KubeClient is the Kubernetes API client.
+	Logger            *logr.Logger            // Logger is used for logging reconciliation events.
+	Kind              kinds.Kind              // Kind is the kind of the workloads owning the watched Pods.
+	AnnotationKindMap kinds.AnnotationKindMap // AnnotationKindMap maps annotation keys to workload kinds.
+}
+
+// Reconcile records the image digests of the workload once all its Pods run the same images.
+func (r *ImageDigestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	obj, err := newWorkloadObject(r.Kind)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.KubeClient.Get(ctx, req.NamespacedName, obj); err != nil {
+		if kerrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to fetch %s: %w", r.Kind, err)
+	}
+
+	// Only source workloads cascade restarts.
+	if !hasTargetAnnotation(obj, r.AnnotationKindMap) {
+		return ctrl.Result{}, nil
+	}
+
+	workload, err := workloads.FromObject(obj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	log := r.Logger.WithValues("workloadID", workload.ID())
+
+	// Wait for the rollout to settle; Pod updates trigger the next reconcile.
+	if stable, _ := workload.Stable(); !stable {
+		return ctrl.Result{}, nil
+	}
+
+	pods, err := workloads.ListPods(ctx, r.KubeClient, obj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	digests, uniform := workloads.ImageDigests(pods)
+	if !uniform {
+		return ctrl.Result{}, nil
+	}
+
+	templateHash, err := predicates.HashTemplate(*workload.PodTemplateSpec())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	value := templateHash + "/" + digests
+	current := obj.GetAnnotations()[flag.ImageDigestsAnnotation]
+	if current == value {
+		return ctrl.Result{}, nil
+	}
+
+	if currentTemplate, _, ok := strings.Cut(current, "/"); ok && currentTemplate == templateHash {
+		log.Info("Image digests changed without a pod template change", "digests", digests)
+	}
+
+	if err := utils.PatchWorkloadAnnotation(ctx, r.KubeClient, obj, flag.ImageDigestsAnnotation, value); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to record image digests: %w", err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// ownerOf maps a Pod to the workload of the reconciled kind owning it.
+func (r *ImageDigestReconciler) ownerOf(_ context.Context, obj client.Object) []reconcile.Request {
+	req, ok := podOwnerRequest(r.Kind, obj)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{req}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ImageDigestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(strings.ToLower(r.Kind.String())+"-image-digests").
+		Watches(
+			&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.ownerOf),
+			builder.WithPredicates(predicates.PodImagesChanged()),
+		).
+		Complete(r)
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/internal/predicates"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestImageDigestReconciler_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	newSource := func(annotations map[string]string) *appsv1.Deployment {
+		dep := newStableDeployment("source", annotations)
+		dep.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "source"}}
+		return dep
+	}
+	newPod := func(name, imageID string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "source"}},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "app", ImageID: imageID},
+			}},
+		}
+	}
+	newReconciler := func(objs ...client.Object) *ImageDigestReconciler {
+		return &ImageDigestReconciler{
+			KubeClient:        fake.NewClientBuilder().WithObjects(objs...).Build(),
+			Logger:            &logr.Logger{},
+			Kind:              kinds.DeploymentKind,
+			AnnotationKindMap: kinds.AnnotationKindMap{"cascader.tkb.ch/deployment": kinds.DeploymentKind},
+		}
+	}
+	digestsOf := func(t *testing.T, r *ImageDigestReconciler) string {
+		t.Helper()
+		dep := &appsv1.Deployment{}
+		require.NoError(t, r.KubeClient.Get(t.Context(), types.NamespacedName{Namespace: "default", Name: "source"}, dep))
+		return dep.Annotations[flag.ImageDigestsAnnotation]
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "source"}}
+
+	t.Run("Records digests of uniform pods", func(t *testing.T) {
+		t.Parallel()
+
+		source := newSource(map[string]string{"cascader.tkb.ch/deployment": "target"})
+		r := newReconciler(source, newPod("a", "app@sha256:1"), newPod("b", "app@sha256:1"))
+
+		result, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+
+		templateHash, err := predicates.HashTemplate(source.Spec.Template)
+		require.NoError(t, err)
+		assert.Regexp(t, "^"+templateHash+"/[0-9a-f]+$", digestsOf(t, r))
+	})
+
+	t.Run("Updates digests without template change", func(t *testing.T) {
+		t.Parallel()
+
+		templateHash, err := predicates.HashTemplate(newSource(nil).Spec.Template)
+		require.NoError(t, err)
+		recorded := templateHash + "/stale"
+
+		source := newSource(map[string]string{
+			"cascader.tkb.ch/deployment": "target",
+			flag.ImageDigestsAnnotation:  recorded,
+		})
+		r := newReconciler(source, newPod("a", "app@sha256:2"))
+
+		_, err = r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+
+		updated := digestsOf(t, r)
+		assert.NotEqual(t, recorded, updated)
+		assert.True(t, predicates.ImageDigestsChanged(
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{flag.ImageDigestsAnnotation: recorded}}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{flag.ImageDigestsAnnotation: updated}}},
+		))
+	})
+
+	t.Run("Waits while pods run different images", func(t *testing.T) {
+		t.Parallel()
+
+		source := newSource(map[string]string{"cascader.tkb.ch/deployment": "target"})
+		r := newReconciler(source, newPod("a", "app@sha256:1"), newPod("b", "app@sha256:2"))
+
+		_, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Empty(t, digestsOf(t, r))
+	})
+
+	t.Run("Ignores workloads without targets", func(t *testing.T) {
+		t.Parallel()
+
+		r := newReconciler(newSource(nil), newPod("a", "app@sha256:1"))
+
+		_, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Empty(t, digestsOf(t, r))
+	})
+
+	t.Run("Workload not found", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := newReconciler().Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+	})
+}
+
+func TestImageDigestReconciler_OwnerOf(t *testing.T) {
+	t.Parallel()
+
+	newPod := func(ownerKind, ownerName string, labels map[string]string) *corev1.Pod {
+		isController := true
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod",
+			Namespace: "default",
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: ownerKind, Name: ownerName, Controller: &isController},
+			},
+		}}
+	}
+	expected := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "default", Name: "web"}}}
+
+	t.Run("Deployment pod", func(t *testing.T) {
+		t.Parallel()
+
+		r := &ImageDigestReconciler{Kind: kinds.DeploymentKind}
+		pod := newPod("ReplicaSet", "web-5d8f9c", map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: "5d8f9c"})
+
+		assert.Equal(t, expected, r.ownerOf(t.Context(), pod))
+	})
+
+	t.Run("ReplicaSet pod without hash label", func(t *testing.T) {
+		t.Parallel()
+
+		r := &ImageDigestReconciler{Kind: kinds.DeploymentKind}
+
+		assert.Empty(t, r.ownerOf(t.Context(), newPod("ReplicaSet", "web", nil)))
+	})
+
+	t.Run("StatefulSet pod", func(t *testing.T) {
+		t.Parallel()
+
+		r := &ImageDigestReconciler{Kind: kinds.StatefulSetKind}
+
+		assert.Equal(t, expected, r.ownerOf(t.Context(), newPod("StatefulSet", "web", nil)))
+		assert.Empty(t, r.ownerOf(t.Context(), newPod("DaemonSet", "web", nil)))
+	})
+
+	t.Run("Pod without owner", func(t *testing.T) {
+		t.Parallel()
+
+		r := &ImageDigestReconciler{Kind: kinds.DaemonSetKind}
+
+		assert.Empty(t, r.ownerOf(t.Context(), &corev1.Pod{}))
+	})
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/predicates"
+	"github.com/thurgauerkb/cascader/internal/utils"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// JobReconciler reconciles Jobs to restart their targets once they completed, e.g. schema
+// migrations which must finish before their dependents restart. A Job re-created with the same
+// name, e.g. by a Helm hook, is a new object and restarts its targets again once it completed.
+// Failures of Jobs triggered by Cascader are reported on the source which triggered them.
+type JobReconciler struct {
+	BaseReconciler
+}
+
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;patch;create;delete
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+
+// Reconcile handles the reconciliation logic when a Job completed or failed.
+func (r *JobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	// Fetch the Job instance
+	job := &batchv1.Job{}
+	if err := r.KubeClient.Get(ctx, req.NamespacedName, job); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Info("Job not found; ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, errors.New("failed to fetch Job")
+	}
+
+	failed, reason := workloads.JobFailed(job)
+	if failed {
+		r.reportTriggeredJobFailure(ctx, job, reason)
+	}
+
+	// A failed Job does not restart its targets, unless a cascade of it is already in flight.
+	if failed && !hasAnnotation(job, r.LastObservedRestartAnnotation) {
+		if hasTargetAnnotation(job, r.AnnotationKindMap) {
+			logger.Info("Job failed; skipping restart of targets", "reason", reason)
+			r.Recorder.Eventf(
+				job,
+				nil,
+				corev1.EventTypeWarning,
+				"JobFailed",
+				"ReconcileJob",
+				"Cascader skipped restart of targets: %s",
+				reason,
+			)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	return r.ReconcileWorkload(ctx, &workloads.JobWorkload{Job: job})
+}
+
+// reportTriggeredJobFailure reports the failure of a Job triggered by Cascader on the source which triggered it.
+func (r *JobReconciler) reportTriggeredJobFailure(ctx context.Context, job *batchv1.Job, reason string) {
+	id, ok := job.GetAnnotations()[flag.TriggeredByAnnotation]
+	if !ok {
+		return
+	}
+	logger := log.FromContext(ctx).WithValues("sourceID", id)
+
+	kind, namespace, name, err := utils.ParseID(id)
+	if err != nil {
+		logger.Info("Cannot report failure of triggered Job", "error", err.Error())
+		return
+	}
+	src, err := newWorkloadObject(kind)
+	if err != nil {
+		logger.Info("Cannot report failure of triggered Job", "error", err.Error())
+		return
+	}
+	if err := r.KubeClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, src); err != nil {
+		logger.Info("Cannot report failure of triggered Job; source not found", "error", err.Error())
+		return
+	}
+
+	logger.Info("Triggered Job failed", "reason", reason)
+	r.Recorder.Eventf(
+		src,
+		job,
+		corev1.EventTypeWarning,
+		"TargetJobFailed",
+		"ReconcileJob",
+		"Job %s/%s triggered by Cascader failed: %s",
+		job.Namespace,
+		job.Name,
+		reason,
+	)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *JobReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Jobs are reconciled once they finished. Create events are filtered, so that Jobs which finished
+	// before the operator started do not restart their targets again.
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&batchv1.Job{}, builder.WithPredicates(
+			predicate.Or(
+				predicates.NewPredicate(
+					r.AnnotationKindMap,
+					predicates.JobFinished,
+					predicates.CascadePending(r.LastObservedRestartAnnotation),
+				),
+				predicates.TriggeredJobFailed(),
+			),
+		))
+
+	// Resumed workloads bypass the predicates, as their cascade is already in flight.
+	if r.Resume != nil {
+		b = b.WatchesRawSource(source.Channel(r.Resume, &handler.EnqueueRequestForObject{}))
+	}
+
+	return b.Complete(r)
+}
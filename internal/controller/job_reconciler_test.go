@@ -0,0 +1,306 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	internalmetrics "github.com/thurgauerkb/cascader/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// newJob returns a Job in the default namespace with the given conditions set to true.
+func newJob(name string, annotations map[string]string, conditions ...batchv1.JobCondition) *batchv1.Job {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Generation:  1,
+			Annotations: annotations,
+		},
+	}
+	for _, cond := range conditions {
+		cond.Status = corev1.ConditionTrue
+		job.Status.Conditions = append(job.Status.Conditions, cond)
+	}
+	return job
+}
+
+// reconcileJob runs the Job reconciler for the Job with the given name.
+func reconcileJob(t *testing.T, reconciler *BaseReconciler, name string) ctrl.Result {
+	t.Helper()
+
+	r := &JobReconciler{BaseReconciler: *reconciler}
+	result, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}})
+	require.NoError(t, err)
+	return result
+}
+
+func TestJobReconciler_SetupWithManager(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := manager.New(ctrl.GetConfigOrDie(), manager.Options{})
+	assert.NoError(t, err, "Failed to create manager")
+
+	reconciler := &JobReconciler{
+		BaseReconciler: BaseReconciler{
+			KubeClient: fake.NewClientBuilder().WithScheme(mgr.GetScheme()).Build(),
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			AnnotationKindMap: kinds.AnnotationKindMap{
+				"cascader.tkb.ch/deployment": kinds.DeploymentKind,
+			},
+		},
+	}
+
+	err = reconciler.SetupWithManager(mgr)
+	assert.NoError(t, err, "SetupWithManager should not return an error")
+}
+
+func TestJobReconciler_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	annotations := map[string]string{"cascader.tkb.ch/deployment": "app"}
+	complete := batchv1.JobCondition{Type: batchv1.JobComplete}
+	failed := batchv1.JobCondition{Type: batchv1.JobFailed, Reason: "BackoffLimitExceeded", Message: "Job has reached the specified backoff limit"}
+
+	t.Run("Job not found", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler()
+
+		result := reconcileJob(t, reconciler, "migrate")
+		assert.Equal(t, ctrl.Result{}, result)
+	})
+
+	t.Run("Completed Job restarts its targets", func(t *testing.T) {
+		t.Parallel()
+
+		app := newStableDeployment("app", nil)
+		reconciler := createBaseReconciler(newJob("migrate", annotations, complete), app)
+
+		result := reconcileJob(t, reconciler, "migrate")
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.True(t, restarted(t, reconciler.KubeClient, app))
+
+		job := &batchv1.Job{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "migrate"}, job))
+		assert.NotContains(t, job.Annotations, reconciler.LastObservedRestartAnnotation)
+	})
+
+	t.Run("Failed Job does not restart its targets", func(t *testing.T) {
+		t.Parallel()
+
+		app := newStableDeployment("app", nil)
+		recorder := events.NewFakeRecorder(10)
+		reconciler := createBaseReconciler(newJob("migrate", annotations, failed), app)
+		reconciler.Recorder = recorder
+
+		result := reconcileJob(t, reconciler, "migrate")
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.False(t, restarted(t, reconciler.KubeClient, app))
+		assert.Equal(t, "Warning JobFailed Cascader skipped restart of targets: job failed: BackoffLimitExceeded: Job has reached the specified backoff limit", <-recorder.Events)
+	})
+
+	t.Run("Running Job with an in-flight cascade waits for completion", func(t *testing.T) {
+		t.Parallel()
+
+		app := newStableDeployment("app", nil)
+		job := newJob("migrate", map[string]string{
+			"cascader.tkb.ch/deployment":            "app",
+			"cascader.tkb.ch/last-observed-restart": "2026-01-01T00:00:00Z",
+		})
+		reconciler := createBaseReconciler(job, app)
+
+		result := reconcileJob(t, reconciler, "migrate")
+		assert.Equal(t, defaultRequeuAfter, result.RequeueAfter)
+		assert.False(t, restarted(t, reconciler.KubeClient, app))
+	})
+
+	t.Run("Re-created Job restarts its targets again", func(t *testing.T) {
+		t.Parallel()
+
+		app := newStableDeployment("app", nil)
+		reconciler := createBaseReconciler(newJob("migrate", annotations, complete), app)
+
+		reconcileJob(t, reconciler, "migrate")
+		assert.True(t, restarted(t, reconciler.KubeClient, app))
+		resetRestart(t, reconciler.KubeClient, "app")
+
+		// A Helm hook deletes the Job before creating it again with the same name.
+		require.NoError(t, reconciler.KubeClient.Delete(t.Context(), newJob("migrate", nil)))
+		require.NoError(t, reconciler.KubeClient.Create(t.Context(), newJob("migrate", annotations)))
+
+		job := &batchv1.Job{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "migrate"}, job))
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+		require.NoError(t, reconciler.KubeClient.Status().Update(t.Context(), job))
+
+		reconcileJob(t, reconciler, "migrate")
+		assert.True(t, restarted(t, reconciler.KubeClient, app))
+	})
+}
+
+func TestJobReconciler_TriggeredJobFailure(t *testing.T) {
+	t.Parallel()
+
+	failed := batchv1.JobCondition{Type: batchv1.JobFailed, Reason: "BackoffLimitExceeded"}
+
+	t.Run("Failure is reported on the source", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := events.NewFakeRecorder(10)
+		reconciler := createBaseReconciler(
+			newStableDeployment("cache", nil),
+			newJob("warm-cache", map[string]string{flag.TriggeredByAnnotation: "Deployment/default/cache"}, failed),
+		)
+		reconciler.Recorder = recorder
+
+		result := reconcileJob(t, reconciler, "warm-cache")
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.Equal(t, "Warning TargetJobFailed Job default/warm-cache triggered by Cascader failed: job failed: BackoffLimitExceeded", <-recorder.Events)
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("Failure of a Job with targets is reported on both", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := events.NewFakeRecorder(10)
+		reconciler := createBaseReconciler(
+			newStableDeployment("cache", nil),
+			newStableDeployment("app", nil),
+			newJob("warm-cache", map[string]string{
+				flag.TriggeredByAnnotation:   "Deployment/default/cache",
+				"cascader.tkb.ch/deployment": "app",
+			}, failed),
+		)
+		reconciler.Recorder = recorder
+
+		reconcileJob(t, reconciler, "warm-cache")
+		assert.Equal(t, "Warning TargetJobFailed Job default/warm-cache triggered by Cascader failed: job failed: BackoffLimitExceeded", <-recorder.Events)
+		assert.Equal(t, "Warning JobFailed Cascader skipped restart of targets: job failed: BackoffLimitExceeded", <-recorder.Events)
+	})
+
+	t.Run("Missing source is ignored", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := events.NewFakeRecorder(10)
+		reconciler := createBaseReconciler(
+			newJob("warm-cache", map[string]string{flag.TriggeredByAnnotation: "Deployment/default/cache"}, failed),
+		)
+		reconciler.Recorder = recorder
+
+		result := reconcileJob(t, reconciler, "warm-cache")
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("Invalid source is ignored", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := events.NewFakeRecorder(10)
+		reconciler := createBaseReconciler(
+			newJob("warm-cache", map[string]string{flag.TriggeredByAnnotation: "cache"}, failed),
+		)
+		reconciler.Recorder = recorder
+
+		result := reconcileJob(t, reconciler, "warm-cache")
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.Empty(t, recorder.Events)
+	})
+}
+
+func TestReconcileWorkload_BatchTargets(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CronJob target runs a Job", func(t *testing.T) {
+		t.Parallel()
+
+		cronJob := &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "cache-warmer", Namespace: "default"},
+			Spec: batchv1.CronJobSpec{
+				Schedule: "0 * * * *",
+				JobTemplate: batchv1.JobTemplateSpec{
+					Spec: batchv1.JobSpec{BackoffLimit: ptr.To[int32](2)},
+				},
+			},
+		}
+		reconciler := createBaseReconciler(
+			newStableDeployment("cache", map[string]string{"cascader.tkb.ch/cronjob": "cache-warmer"}),
+			cronJob,
+		)
+		reconciler.AnnotationKindMap["cascader.tkb.ch/cronjob"] = kinds.CronJobKind
+
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "cache"))
+
+		jobs := &batchv1.JobList{}
+		require.NoError(t, reconciler.KubeClient.List(t.Context(), jobs, client.InNamespace("default")))
+		require.Len(t, jobs.Items, 1)
+		assert.Equal(t, "Deployment/default/cache", jobs.Items[0].Annotations[flag.TriggeredByAnnotation])
+		assert.Equal(t, ptr.To[int32](2), jobs.Items[0].Spec.BackoffLimit)
+	})
+
+	t.Run("Job target is re-run", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler(
+			newStableDeployment("cache", map[string]string{"cascader.tkb.ch/job": "warm-cache"}),
+			newJob("warm-cache", nil, batchv1.JobCondition{Type: batchv1.JobComplete}),
+		)
+		reconciler.AnnotationKindMap["cascader.tkb.ch/job"] = kinds.JobKind
+
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "cache"))
+
+		job := &batchv1.Job{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "warm-cache"}, job))
+		assert.Empty(t, job.Status.Conditions)
+		assert.Equal(t, "Deployment/default/cache", job.Annotations[flag.TriggeredByAnnotation])
+	})
+
+	t.Run("Running Job target is deferred", func(t *testing.T) {
+		t.Parallel()
+
+		running := newJob("warm-cache", nil)
+		running.Status.Active = 1
+		reconciler := createBaseReconciler(
+			newStableDeployment("cache", map[string]string{"cascader.tkb.ch/job": "warm-cache"}),
+			running,
+		)
+		reconciler.AnnotationKindMap["cascader.tkb.ch/job"] = kinds.JobKind
+
+		result := reconcileDeployment(t, reconciler, "cache")
+		assert.Equal(t, defaultRequeuAfter, result.RequeueAfter)
+
+		job := &batchv1.Job{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "warm-cache"}, job))
+		assert.NotContains(t, job.Annotations, flag.TriggeredByAnnotation)
+	})
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MissingTargetsMode defines how a source handles targets which do not exist.
+type MissingTargetsMode string
+
+const (
+	// MissingTargetsSkip restarts the existing targets and ignores the missing ones.
+	MissingTargetsSkip MissingTargetsMode = "skip"
+
+	// MissingTargetsWait waits for the missing targets to appear, then skips them once the timeout expired.
+	MissingTargetsWait MissingTargetsMode = "wait"
+
+	// MissingTargetsFail aborts the cascade without restarting any target.
+	MissingTargetsFail MissingTargetsMode = "fail"
+)
+
+// ParseMissingTargetsMode parses a missing-targets mode. The value is case-insensitive.
+func ParseMissingTargetsMode(value string) (MissingTargetsMode, error) {
+	switch mode := MissingTargetsMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case MissingTargetsSkip, MissingTargetsWait, MissingTargetsFail:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unsupported missing targets mode: %q", value)
+	}
+}
+
+// missingTargetsPolicy returns the missing-targets mode and wait timeout for the given source.
+// Annotations on the source override the defaults; invalid annotations fall back to the defaults.
+func (b *BaseReconciler) missingTargetsPolicy(obj client.Object) (MissingTargetsMode, time.Duration, error) {
+	mode, timeout := b.MissingTargets, b.MissingTargetsTimeout
+	if mode == "" {
+		mode = MissingTargetsFail
+	}
+
+	var errs []error
+	annotations := obj.GetAnnotations()
+	if val := strings.TrimSpace(annotations[flag.MissingTargetsAnnotation]); val != "" {
+		m, err := ParseMissingTargetsMode(val)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid annotation %q: %w", flag.MissingTargetsAnnotation, err))
+		} else {
+			mode = m
+		}
+	}
+	if val := strings.TrimSpace(annotations[flag.MissingTargetsTimeoutAnnotation]); val != "" {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid annotation %q: %w", flag.MissingTargetsTimeoutAnnotation, err))
+		} else {
+			timeout = d
+		}
+	}
+
+	return mode, timeout, errors.Join(errs...)
+}
+
+// partitionTargets splits the targets into those which exist and those which do not.
+// Only NotFound errors mark a target as missing; other errors are left to the subsequent checks.
+func (b *BaseReconciler) partitionTargets(ctx context.Context, targetList []targets.Target) (existing, missing []targets.Target) {
+	for _, t := range targetList {
+		err := b.KubeClient.Get(ctx, client.ObjectKey{Namespace: t.Namespace(), Name: t.Name()}, t.Resource())
+		if kerrors.IsNotFound(err) {
+			missing = append(missing, t)
+			continue
+		}
+		existing = append(existing, t)
+	}
+	return existing, missing
+}
+
+// observedFor returns how long ago the current restart of the source was first observed.
+func (b *BaseReconciler) observedFor(obj client.Object) time.Duration {
+	observedAt, err := time.Parse(time.RFC3339, obj.GetAnnotations()[b.LastObservedRestartAnnotation])
+	if err != nil {
+		return 0
+	}
+	return time.Since(observedAt)
+}
+
+// handleMissingTargets applies the missing-targets mode of the source to its targets.
+// It returns the targets to continue the cascade with, or a result if the cascade must not continue.
+func (b *BaseReconciler) handleMissingTargets(
+	ctx context.Context,
+	workload workloads.Workload,
+	targetList []targets.Target,
+	observed bool,
+	requeueAfter time.Duration,
+) ([]targets.Target, *ctrl.Result) {
+	res := workload.Resource()
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	existing, missing := b.partitionTargets(ctx, targetList)
+	b.Metrics.SetMissingTargets(workload.GetNamespace(), workload.GetName(), workload.Kind().String(), float64(len(missing)))
+
+	// Optional targets are skipped regardless of the missing-targets mode.
+	specs := edgeSpecs(res)
+	missing = slices.DeleteFunc(missing, func(t targets.Target) bool {
+		if !specs.lookup(t).Optional {
+			return false
+		}
+		if !observed {
+			log.Info("Skipping optional target which does not exist", "targetID", t.ID())
+		}
+		return true
+	})
+	if len(missing) == 0 {
+		return existing, nil
+	}
+
+	mode, timeout, err := b.missingTargetsPolicy(res)
+	if err != nil {
+		log.Error(err, "Invalid missing targets annotation, using defaults", "mode", mode, "timeout", timeout)
+	}
+	missingIDs := strings.Join(targetIDs(missing), ", ")
+
+	switch mode {
+	case MissingTargetsSkip:
+		if !observed {
+			// Report missing targets only once per cascade.
+			b.recordTargetMissing(res, "Skipping targets which do not exist: %s", missingIDs)
+			log.Info("Skipping missing targets", "missing", missingIDs)
+		}
+		return existing, nil
+
+	case MissingTargetsWait:
+		waited := b.observedFor(res)
+		if waited < timeout {
+			if !observed {
+				b.recordTargetMissing(res, "Waiting up to %s for targets to appear: %s", timeout, missingIDs)
+			}
+			dur := min(requeueAfter, timeout-waited)
+			log.Info(fmt.Sprintf("Waiting for missing targets. Requeuing after %s.", dur), "missing", missingIDs)
+			return nil, &ctrl.Result{RequeueAfter: dur}
+		}
+		b.recordTargetMissing(res, "Targets did not appear within %s, skipping them: %s", timeout, missingIDs)
+		log.Info("Missing targets did not appear in time; skipping them", "missing", missingIDs, "timeout", timeout)
+		return existing, nil
+
+	default:
+		b.recordTargetMissing(res, "Cascade aborted, targets do not exist: %s", missingIDs)
+		log.Error(fmt.Errorf("targets not found: %s", missingIDs), "Missing targets; skipping reload")
+		// The cascade is finished, so the next restart of the source is detected as a new one.
+		b.forgetRecreation(res)
+		b.restoreQuiesced(ctx, workload, existing, time.Now(), true)
+		if err := b.clearLastObservedRestartAnnotation(ctx, workload); err != nil {
+			log.Error(err, "Failed to delete restartedAt annotation")
+		}
+		return nil, &ctrl.Result{}
+	}
+}
+
+// recordTargetMissing emits a TargetMissing warning event on the source.
+func (b *BaseReconciler) recordTargetMissing(source client.Object, note string, args ...any) {
+	b.Recorder.Eventf(source, nil, corev1.EventTypeWarning, "TargetMissing", "ResolveTargets", note, args...)
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestParseMissingTargetsMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value    string
+		expected MissingTargetsMode
+		wantErr  bool
+	}{
+		{value: "skip", expected: MissingTargetsSkip},
+		{value: "Wait", expected: MissingTargetsWait},
+		{value: " fail ", expected: MissingTargetsFail},
+		{value: "ignore", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			mode, err := ParseMissingTargetsMode(tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.EqualError(t, err, "unsupported missing targets mode: \""+tt.value+"\"")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, mode)
+		})
+	}
+}
+
+func TestMissingTargetsPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Defaults", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler()
+		reconciler.MissingTargets = MissingTargetsSkip
+		reconciler.MissingTargetsTimeout = time.Minute
+
+		mode, timeout, err := reconciler.missingTargetsPolicy(newStableDeployment("source", nil))
+		require.NoError(t, err)
+		assert.Equal(t, MissingTargetsSkip, mode)
+		assert.Equal(t, time.Minute, timeout)
+	})
+
+	t.Run("Unset default fails", func(t *testing.T) {
+		t.Parallel()
+
+		mode, _, err := createBaseReconciler().missingTargetsPolicy(newStableDeployment("source", nil))
+		require.NoError(t, err)
+		assert.Equal(t, MissingTargetsFail, mode)
+	})
+
+	t.Run("Annotations override defaults", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler()
+		reconciler.MissingTargets = MissingTargetsFail
+		reconciler.MissingTargetsTimeout = time.Minute
+
+		mode, timeout, err := reconciler.missingTargetsPolicy(newStableDeployment("source", map[string]string{
+			flag.MissingTargetsAnnotation:        "wait",
+			flag.MissingTargetsTimeoutAnnotation: "30s",
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, MissingTargetsWait, mode)
+		assert.Equal(t, 30*time.Second, timeout)
+	})
+
+	t.Run("Invalid annotations fall back to defaults", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler()
+		reconciler.MissingTargets = MissingTargetsSkip
+		reconciler.MissingTargetsTimeout = time.Minute
+
+		mode, timeout, err := reconciler.missingTargetsPolicy(newStableDeployment("source", map[string]string{
+			flag.MissingTargetsAnnotation:        "ignore",
+			flag.MissingTargetsTimeoutAnnotation: "soon",
+		}))
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "invalid annotation \"cascader.tkb.ch/missing-targets\"")
+		assert.ErrorContains(t, err, "invalid annotation \"cascader.tkb.ch/missing-targets-timeout\"")
+		assert.Equal(t, MissingTargetsSkip, mode)
+		assert.Equal(t, time.Minute, timeout)
+	})
+}
+
+func TestReconcileWorkload_MissingTargets(t *testing.T) {
+	t.Parallel()
+
+	const targetsAnnotation = "cascader.tkb.ch/deployment"
+
+	// restarted reports whether the target was restarted by the reconciler.
+	restarted := func(t *testing.T, c client.Client, name string) bool {
+		t.Helper()
+		dep := &appsv1.Deployment{}
+		require.NoError(t, c.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: name}, dep))
+		_, ok := dep.Spec.Template.Annotations[flag.LastObservedRestartAnnotation]
+		return ok
+	}
+
+	t.Run("Skip restarts existing targets", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{
+			targetsAnnotation:             "existing, missing",
+			flag.MissingTargetsAnnotation: "skip",
+		})
+		existing := newStableDeployment("existing", nil)
+
+		reconciler := createBaseReconciler(source, existing)
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.True(t, restarted(t, reconciler.KubeClient, "existing"))
+
+		require.Len(t, recorder.Events, 2)
+		assert.Contains(t, <-recorder.Events, "TargetMissing Skipping targets which do not exist: Deployment/default/missing")
+		assert.Contains(t, <-recorder.Events, "ReloadSucceeded")
+	})
+
+	t.Run("Wait requeues until timeout", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{
+			targetsAnnotation:                    "existing, missing",
+			flag.MissingTargetsAnnotation:        "wait",
+			flag.MissingTargetsTimeoutAnnotation: "1h",
+		})
+		existing := newStableDeployment("existing", nil)
+
+		reconciler := createBaseReconciler(source, existing)
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: defaultRequeuAfter}, result)
+		assert.False(t, restarted(t, reconciler.KubeClient, "existing"))
+
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "TargetMissing Waiting up to 1h0m0s for targets to appear: Deployment/default/missing")
+	})
+
+	t.Run("Wait skips missing targets after timeout", func(t *testing.T) {
+		t.Parallel()
+
+		observedAt := time.Now().Add(-2 * time.Minute).Format(time.RFC3339)
+		source := newStableDeployment("source", map[string]string{
+			targetsAnnotation:                       "existing, missing",
+			flag.MissingTargetsAnnotation:           "wait",
+			flag.MissingTargetsTimeoutAnnotation:    "1m",
+			"cascader.tkb.ch/last-observed-restart": observedAt,
+		})
+		existing := newStableDeployment("existing", nil)
+
+		reconciler := createBaseReconciler(source, existing)
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.True(t, restarted(t, reconciler.KubeClient, "existing"))
+
+		require.Len(t, recorder.Events, 2)
+		assert.Contains(t, <-recorder.Events, "TargetMissing Targets did not appear within 1m0s, skipping them: Deployment/default/missing")
+		assert.Contains(t, <-recorder.Events, "ReloadSucceeded")
+	})
+
+	t.Run("Fail aborts the cascade", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{
+			targetsAnnotation: "existing, missing",
+		})
+		existing := newStableDeployment("existing", nil)
+
+		reconciler := createBaseReconciler(source, existing)
+		reconciler.MissingTargets = MissingTargetsFail
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.False(t, restarted(t, reconciler.KubeClient, "existing"))
+
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "TargetMissing Cascade aborted, targets do not exist: Deployment/default/missing")
+
+		updated := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(source), updated))
+		assert.NotContains(t, updated.Annotations, "cascader.tkb.ch/last-observed-restart")
+	})
+}
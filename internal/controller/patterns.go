@@ -0,0 +1,159 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/utils"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// resolvedPattern is a target reference with patterns and the IDs of the workloads it matched.
+type resolvedPattern struct {
+	Kind    kinds.Kind // Kind of the matched workloads.
+	Ref     string     // Reference as written in the annotation.
+	Targets []string   // IDs of the matched workloads.
+}
+
+// isPattern reports whether the namespace or name of a target reference is a pattern. Globs use the
+// syntax of path.Match, e.g. "worker-*", while a leading "~" marks a regular expression, e.g. "~worker-[0-9]+".
+func isPattern(s string) bool {
+	return strings.HasPrefix(s, "~") || strings.ContainsAny(s, "*?[")
+}
+
+// validatePattern checks that a namespace or name pattern can be compiled.
+func validatePattern(pattern string) error {
+	if expr, ok := strings.CutPrefix(pattern, "~"); ok {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid regular expression %q: %w", expr, err)
+		}
+		return nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return nil
+}
+
+// matchPattern reports whether value matches the namespace or name pattern. Regular expressions must
+// match the whole value. Invalid patterns match nothing.
+func matchPattern(pattern, value string) bool {
+	if expr, ok := strings.CutPrefix(pattern, "~"); ok {
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		return err == nil && re.MatchString(value)
+	}
+	if !isPattern(pattern) {
+		return pattern == value
+	}
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
+
+// resolvePattern lists the workloads of the given kind from the cache and returns those matching the
+// namespace and name patterns, sorted by ID. The source itself is never matched.
+func (b *BaseReconciler) resolvePattern(
+	ctx context.Context,
+	source client.Object,
+	kind kinds.Kind,
+	namespace, name string,
+) ([]targets.Target, error) {
+	for _, pattern := range []string{namespace, name} {
+		if err := validatePattern(pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	list, err := newWorkloadList(kind)
+	if err != nil {
+		return nil, err
+	}
+	var opts []client.ListOption
+	if !isPattern(namespace) {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+	if err := b.KubeClient.List(ctx, list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list %s workloads: %w", kind, err)
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract %s workloads: %w", kind, err)
+	}
+
+	sourceID := ""
+	if workload, err := workloads.FromObject(source); err == nil {
+		sourceID = workload.ID()
+	}
+
+	var matched []targets.Target
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok || !matchPattern(namespace, obj.GetNamespace()) || !matchPattern(name, obj.GetName()) {
+			continue
+		}
+		if utils.GenerateID(kind, obj.GetNamespace(), obj.GetName()) == sourceID {
+			continue
+		}
+
+		t, err := targets.NewTarget(ctx, b.KubeClient, kind, obj.GetNamespace()+"/"+obj.GetName(), source)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create target for workload: %w", err)
+		}
+		matched = append(matched, t)
+	}
+
+	slices.SortFunc(matched, func(a, b targets.Target) int {
+		return strings.Compare(a.ID(), b.ID())
+	})
+	return matched, nil
+}
+
+// reportPatterns logs the workloads matched by the patterns of the source and warns about patterns
+// which matched no workload, since they most likely contain a typo.
+func (b *BaseReconciler) reportPatterns(workload workloads.Workload, patterns []resolvedPattern) {
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	for _, p := range patterns {
+		if len(p.Targets) > 0 {
+			log.Info("Target pattern resolved", "kind", p.Kind, "pattern", p.Ref, "targets", p.Targets)
+			continue
+		}
+
+		log.Info("Target pattern matched no workloads", "kind", p.Kind, "pattern", p.Ref)
+		b.Recorder.Eventf(
+			workload.Resource(),
+			nil,
+			corev1.EventTypeWarning,
+			"NoTargetsMatched",
+			"ResolveTargets",
+			"Cascader found no %s matching %q",
+			p.Kind,
+			p.Ref,
+		)
+	}
+}
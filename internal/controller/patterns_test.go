@@ -0,0 +1,207 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// newNamespacedDeployment returns a stable Deployment in the given namespace.
+func newNamespacedDeployment(namespace, name string, annotations map[string]string) *appsv1.Deployment {
+	dep := newStableDeployment(name, annotations)
+	dep.Namespace = namespace
+	return dep
+}
+
+func TestMatchPattern(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		pattern string
+		value   string
+		matched bool
+	}{
+		{name: "Literal", pattern: "worker", value: "worker", matched: true},
+		{name: "Literal mismatch", pattern: "worker", value: "worker-1", matched: false},
+		{name: "Glob", pattern: "worker-*", value: "worker-12", matched: true},
+		{name: "Glob mismatch", pattern: "worker-*", value: "api", matched: false},
+		{name: "Single character", pattern: "worker-?", value: "worker-1", matched: true},
+		{name: "Character class", pattern: "worker-[0-3]", value: "worker-4", matched: false},
+		{name: "Regular expression", pattern: "~worker-[0-9]+", value: "worker-15", matched: true},
+		{name: "Regular expression matches whole value", pattern: "~worker-[0-9]+", value: "worker-15-canary", matched: false},
+		{name: "Alternation", pattern: "~api|web", value: "web", matched: true},
+		{name: "Invalid glob", pattern: "worker-[", value: "worker-[", matched: false},
+		{name: "Invalid regular expression", pattern: "~worker-(", value: "worker-(", matched: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.matched, matchPattern(tt.pattern, tt.value))
+		})
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, validatePattern("worker-*"))
+	assert.NoError(t, validatePattern("~worker-[0-9]+"))
+	assert.EqualError(t, validatePattern("worker-["), `invalid pattern "worker-[": syntax error in pattern`)
+	assert.EqualError(t, validatePattern("~worker-("), "invalid regular expression \"worker-(\": error parsing regexp: missing closing ): `worker-(`")
+}
+
+func TestResolveTargets(t *testing.T) {
+	t.Parallel()
+
+	workloads := []client.Object{
+		newStableDeployment("worker-1", nil),
+		newStableDeployment("worker-2", nil),
+		newStableDeployment("api", nil),
+		newNamespacedDeployment("jobs", "worker-3", nil),
+		newNamespacedDeployment("jobs", "shard-1", nil),
+		newNamespacedDeployment("jobs", "shard-x", nil),
+		newNamespacedDeployment("mesh", "sidecar-injector", nil),
+		newNamespacedDeployment("ingress", "sidecar-injector", nil),
+	}
+
+	tests := []struct {
+		name     string
+		ref      string
+		expected []string
+	}{
+		{name: "Glob in source namespace", ref: "worker-*", expected: []string{"Deployment/default/worker-1", "Deployment/default/worker-2"}},
+		{name: "Glob in other namespace", ref: "jobs/worker-*", expected: []string{"Deployment/jobs/worker-3"}},
+		{name: "Regular expression", ref: "jobs/~shard-[0-9]+", expected: []string{"Deployment/jobs/shard-1"}},
+		{name: "Namespace pattern", ref: "*/sidecar-injector", expected: []string{"Deployment/ingress/sidecar-injector", "Deployment/mesh/sidecar-injector"}},
+		{name: "Source is not matched", ref: "*", expected: []string{"Deployment/default/api", "Deployment/default/worker-1", "Deployment/default/worker-2"}},
+		{name: "No match", ref: "db-*", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := newStableDeployment("source", map[string]string{"cascader.tkb.ch/deployment": tt.ref})
+			reconciler := createBaseReconciler(append(workloads, source)...)
+
+			resolved, patterns, err := reconciler.resolveTargets(t.Context(), source)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, nilIfEmpty(targetIDs(resolved)))
+			assert.Equal(t, []resolvedPattern{{Kind: kinds.DeploymentKind, Ref: tt.ref, Targets: tt.expected}}, patterns)
+		})
+	}
+
+	t.Run("Targets matched several times are resolved once", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{
+			flag.TargetsAnnotation:       `{"version":"v1","targets":[{"kind":"Deployment","name":"~worker-[0-9]"}]}`,
+			"cascader.tkb.ch/deployment": "worker-*",
+		})
+		reconciler := createBaseReconciler(append(workloads, source)...)
+
+		resolved, patterns, err := reconciler.resolveTargets(t.Context(), source)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Deployment/default/worker-1", "Deployment/default/worker-2"}, targetIDs(resolved))
+		assert.Len(t, patterns, 2)
+	})
+
+	t.Run("Invalid pattern", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{"cascader.tkb.ch/deployment": "worker-["})
+		reconciler := createBaseReconciler(source)
+
+		_, _, err := reconciler.resolveTargets(t.Context(), source)
+		assert.EqualError(t, err, `cannot resolve targets "worker-[": invalid pattern "worker-[": syntax error in pattern`)
+	})
+}
+
+// nilIfEmpty returns nil for an empty slice, so that it compares equal to an unset expectation.
+func nilIfEmpty(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
+
+func TestReconcileWorkload_Patterns(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Matched workloads are restarted", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler(
+			newStableDeployment("a", map[string]string{"cascader.tkb.ch/deployment": "worker-*"}),
+			newStableDeployment("worker-1", nil),
+			newStableDeployment("worker-2", nil),
+			newStableDeployment("api", nil),
+		)
+
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "a"))
+		assert.True(t, restarted(t, reconciler.KubeClient, newStableDeployment("worker-1", nil)))
+		assert.True(t, restarted(t, reconciler.KubeClient, newStableDeployment("worker-2", nil)))
+		assert.False(t, restarted(t, reconciler.KubeClient, newStableDeployment("api", nil)))
+	})
+
+	t.Run("Pattern matching nothing is reported", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := events.NewFakeRecorder(10)
+		reconciler := createBaseReconciler(
+			newStableDeployment("a", map[string]string{"cascader.tkb.ch/deployment": "wroker-*"}),
+			newStableDeployment("worker-1", nil),
+		)
+		reconciler.Recorder = recorder
+
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "a"))
+		assert.Equal(t, `Warning NoTargetsMatched Cascader found no Deployment matching "wroker-*"`, <-recorder.Events)
+		assert.False(t, restarted(t, reconciler.KubeClient, newStableDeployment("worker-1", nil)))
+	})
+
+	t.Run("Cycle through pattern is detected", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := events.NewFakeRecorder(10)
+		reconciler := createBaseReconciler(
+			newStableDeployment("a", map[string]string{"cascader.tkb.ch/deployment": "worker-*"}),
+			newStableDeployment("worker-1", map[string]string{"cascader.tkb.ch/deployment": "a"}),
+		)
+		reconciler.Recorder = recorder
+
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "a"))
+		assert.Equal(
+			t,
+			"Warning CycleDetected Dependency cycle detected: Deployment/default/a -> Deployment/default/worker-1 -> Deployment/default/a",
+			<-recorder.Events,
+		)
+		assert.False(t, restarted(t, reconciler.KubeClient, newStableDeployment("worker-1", nil)))
+	})
+}
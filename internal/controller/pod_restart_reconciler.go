@@ -0,0 +1,200 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/internal/predicates"
+	"github.com/thurgauerkb/cascader/internal/utils"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// PodRestartReconciler records the container restarts of the Pods of source workloads of one kind.
+// Once enough Pods of a source were replaced or restarted a container without a change of the pod template,
+// and the source is stable again, the annotation on the source is updated, which is picked up by the
+// workload reconciler as a restart.
+type PodRestartReconciler struct {
+	KubeClient        client.Client           // KubeClient is the Kubernetes API client.
+	Logger            *logr.Logger            // Logger is used for logging reconciliation events.
+	Kind              kinds.Kind              // Kind is the kind of the workloads owning the watched Pods.
+	AnnotationKindMap kinds.AnnotationKindMap // AnnotationKindMap maps annotation keys to workload kinds.
+	Threshold         int                     // Threshold is the default number of replaced Pods which cascade a restart.
+}
+
+// Reconcile compares the Pods of the workload with the recorded ones once all of them are ready.
+func (r *PodRestartReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	obj, err := newWorkloadObject(r.Kind)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.KubeClient.Get(ctx, req.NamespacedName, obj); err != nil {
+		if kerrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to fetch %s: %w", r.Kind, err)
+	}
+
+	// Only source workloads cascade restarts.
+	if !hasTargetAnnotation(obj, r.AnnotationKindMap) {
+		return ctrl.Result{}, nil
+	}
+
+	workload, err := workloads.FromObject(obj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	log := r.Logger.WithValues("workloadID", workload.ID())
+
+	// Wait until replaced Pods are back; Pod updates trigger the next reconcile.
+	if stable, _ := workload.Stable(); !stable {
+		return ctrl.Result{}, nil
+	}
+	pods, err := workloads.ListPods(ctx, r.KubeClient, obj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !podsSettled(obj, pods) {
+		return ctrl.Result{}, nil
+	}
+
+	templateHash, err := predicates.HashTemplate(*workload.PodTemplateSpec())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	recorded, err := workloads.ParsePodRestarts(obj.GetAnnotations()[flag.PodRestartsAnnotation])
+	if err != nil {
+		log.Error(err, "Discarding invalid pod restarts annotation")
+	}
+
+	current := &workloads.PodRestarts{Template: templateHash, Pods: workloads.RecordPodRestarts(pods)}
+	if recorded != nil {
+		current.Cascades = recorded.Cascades
+
+		// Pods replaced by a rollout cascade through the changed pod template, and a single-replica
+		// workload cascades once its Pod is not ready anymore.
+		if recorded.Template == templateHash && !singleReplica(obj) {
+			if replaced := workloads.ReplacedPods(recorded.Pods, current.Pods); replaced > 0 {
+				threshold, err := r.thresholdFor(obj)
+				if err != nil {
+					log.Error(err, fmt.Sprintf("Invalid pod restart threshold annotation, using default: %d", threshold))
+				}
+				if replaced >= threshold {
+					log.Info("Pods were replaced or restarted; cascading restart", "replaced", replaced, "threshold", threshold)
+					current.Cascades++
+				} else {
+					log.Info("Pods were replaced or restarted below the threshold", "replaced", replaced, "threshold", threshold)
+				}
+			}
+		}
+
+		if recorded.Template == current.Template && recorded.Cascades == current.Cascades && maps.Equal(recorded.Pods, current.Pods) {
+			return ctrl.Result{}, nil
+		}
+	}
+
+	value, err := json.Marshal(current)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to serialize pod restarts: %w", err)
+	}
+	if err := utils.PatchWorkloadAnnotation(ctx, r.KubeClient, obj, flag.PodRestartsAnnotation, string(value)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to record pod restarts: %w", err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// thresholdFor returns the number of replaced Pods of the source which cascade a restart.
+// The annotation on the source overrides the default; an invalid annotation falls back to the default.
+func (r *PodRestartReconciler) thresholdFor(obj client.Object) (int, error) {
+	threshold := max(r.Threshold, 1)
+
+	val := strings.TrimSpace(obj.GetAnnotations()[flag.PodRestartThresholdAnnotation])
+	if val == "" {
+		return threshold, nil
+	}
+
+	n, err := strconv.Atoi(val)
+	if err != nil || n < 1 {
+		return threshold, fmt.Errorf("invalid annotation %q: must be a positive number, got %q", flag.PodRestartThresholdAnnotation, val)
+	}
+	return n, nil
+}
+
+// podsSettled reports whether the workload runs all its desired Pods and all of them are ready,
+// so that a Pod which is gone is only compared once its replacement exists.
+func podsSettled(obj client.Object, pods []corev1.Pod) bool {
+	desired, ok := specReplicas(obj)
+	if ds, isDaemonSet := obj.(*appsv1.DaemonSet); isDaemonSet {
+		desired, ok = ds.Status.DesiredNumberScheduled, true
+	}
+	if !ok || int32(len(pods)) != desired {
+		return false
+	}
+
+	for i := range pods {
+		if !workloads.PodReady(&pods[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// singleReplica reports whether the workload is a Deployment or StatefulSet with a single replica.
+func singleReplica(obj client.Object) bool {
+	replicas, ok := specReplicas(obj)
+	return ok && replicas == 1
+}
+
+// ownerOf maps a Pod to the workload of the reconciled kind owning it.
+func (r *PodRestartReconciler) ownerOf(_ context.Context, obj client.Object) []reconcile.Request {
+	req, ok := podOwnerRequest(r.Kind, obj)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{req}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PodRestartReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(strings.ToLower(r.Kind.String())+"-pod-restarts").
+		Watches(
+			&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.ownerOf),
+			builder.WithPredicates(predicates.PodRestarted()),
+		).
+		Complete(r)
+}
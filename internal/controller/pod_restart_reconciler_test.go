@@ -0,0 +1,260 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/internal/predicates"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+	"github.com/thurgauerkb/cascader/test/testutils"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPodRestartReconciler_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	newSource := func(replicas int32, annotations map[string]string) *appsv1.Deployment {
+		dep := newStableDeployment("source", annotations)
+		dep.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "source"}}
+		dep.Spec.Replicas = testutils.Int32Ptr(replicas)
+		dep.Status.Replicas = replicas
+		dep.Status.ReadyReplicas = replicas
+		dep.Status.UpdatedReplicas = replicas
+		dep.Status.AvailableReplicas = replicas
+		return dep
+	}
+	newPod := func(uid string, restarts int32, ready bool) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: uid, Namespace: "default", UID: types.UID(uid), Labels: map[string]string{"app": "source"}},
+			Status: corev1.PodStatus{
+				Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "app", RestartCount: restarts}},
+			},
+		}
+	}
+	newReconciler := func(objs ...client.Object) *PodRestartReconciler {
+		return &PodRestartReconciler{
+			KubeClient:        fake.NewClientBuilder().WithObjects(objs...).Build(),
+			Logger:            &logr.Logger{},
+			Kind:              kinds.DeploymentKind,
+			AnnotationKindMap: kinds.AnnotationKindMap{"cascader.tkb.ch/deployment": kinds.DeploymentKind},
+			Threshold:         1,
+		}
+	}
+	recordedOf := func(t *testing.T, r *PodRestartReconciler) *workloads.PodRestarts {
+		t.Helper()
+		dep := &appsv1.Deployment{}
+		require.NoError(t, r.KubeClient.Get(t.Context(), types.NamespacedName{Namespace: "default", Name: "source"}, dep))
+		restarts, err := workloads.ParsePodRestarts(dep.Annotations[flag.PodRestartsAnnotation])
+		require.NoError(t, err)
+		return restarts
+	}
+	// baseline returns the source annotations recording Pods a, b and c without restarts.
+	baseline := func(t *testing.T, extra map[string]string) map[string]string {
+		t.Helper()
+		templateHash, err := predicates.HashTemplate(newSource(3, nil).Spec.Template)
+		require.NoError(t, err)
+		value, err := json.Marshal(workloads.PodRestarts{Template: templateHash, Pods: map[string]int32{"a": 0, "b": 0, "c": 0}})
+		require.NoError(t, err)
+		annotations := map[string]string{"cascader.tkb.ch/deployment": "target", flag.PodRestartsAnnotation: string(value)}
+		for k, v := range extra {
+			annotations[k] = v
+		}
+		return annotations
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "source"}}
+
+	t.Run("Records baseline", func(t *testing.T) {
+		t.Parallel()
+
+		source := newSource(2, map[string]string{"cascader.tkb.ch/deployment": "target"})
+		r := newReconciler(source, newPod("a", 1, true), newPod("b", 0, true))
+
+		result, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+
+		recorded := recordedOf(t, r)
+		require.NotNil(t, recorded)
+		assert.Equal(t, map[string]int32{"a": 1, "b": 0}, recorded.Pods)
+		assert.Zero(t, recorded.Cascades)
+	})
+
+	t.Run("Cascades replaced pod", func(t *testing.T) {
+		t.Parallel()
+
+		r := newReconciler(newSource(3, baseline(t, nil)), newPod("a", 0, true), newPod("b", 0, true), newPod("d", 0, true))
+
+		_, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+
+		recorded := recordedOf(t, r)
+		assert.Equal(t, 1, recorded.Cascades)
+		assert.Equal(t, map[string]int32{"a": 0, "b": 0, "d": 0}, recorded.Pods)
+	})
+
+	t.Run("Cascades restarted container", func(t *testing.T) {
+		t.Parallel()
+
+		r := newReconciler(newSource(3, baseline(t, nil)), newPod("a", 1, true), newPod("b", 0, true), newPod("c", 0, true))
+
+		_, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, 1, recordedOf(t, r).Cascades)
+	})
+
+	t.Run("Updates baseline below threshold", func(t *testing.T) {
+		t.Parallel()
+
+		source := newSource(3, baseline(t, map[string]string{flag.PodRestartThresholdAnnotation: "2"}))
+		r := newReconciler(source, newPod("a", 1, true), newPod("b", 0, true), newPod("c", 0, true))
+
+		_, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+
+		recorded := recordedOf(t, r)
+		assert.Zero(t, recorded.Cascades)
+		assert.Equal(t, map[string]int32{"a": 1, "b": 0, "c": 0}, recorded.Pods)
+	})
+
+	t.Run("Rollout updates baseline", func(t *testing.T) {
+		t.Parallel()
+
+		source := newSource(3, baseline(t, nil))
+		source.Spec.Template.Annotations = map[string]string{"kubectl.kubernetes.io/restartedAt": "now"}
+		r := newReconciler(source, newPod("d", 0, true), newPod("e", 0, true), newPod("f", 0, true))
+
+		_, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+
+		recorded := recordedOf(t, r)
+		assert.Zero(t, recorded.Cascades)
+		assert.Equal(t, map[string]int32{"d": 0, "e": 0, "f": 0}, recorded.Pods)
+	})
+
+	t.Run("Waits for replacement pod", func(t *testing.T) {
+		t.Parallel()
+
+		r := newReconciler(newSource(3, baseline(t, nil)), newPod("a", 0, true), newPod("b", 0, true))
+
+		_, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int32{"a": 0, "b": 0, "c": 0}, recordedOf(t, r).Pods)
+	})
+
+	t.Run("Waits for ready pods", func(t *testing.T) {
+		t.Parallel()
+
+		r := newReconciler(newSource(3, baseline(t, nil)), newPod("a", 0, true), newPod("b", 0, true), newPod("d", 0, false))
+
+		_, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int32{"a": 0, "b": 0, "c": 0}, recordedOf(t, r).Pods)
+	})
+
+	t.Run("Single replica is left to the workload predicates", func(t *testing.T) {
+		t.Parallel()
+
+		templateHash, err := predicates.HashTemplate(newSource(1, nil).Spec.Template)
+		require.NoError(t, err)
+		value, err := json.Marshal(workloads.PodRestarts{Template: templateHash, Pods: map[string]int32{"a": 0}})
+		require.NoError(t, err)
+		source := newSource(1, map[string]string{"cascader.tkb.ch/deployment": "target", flag.PodRestartsAnnotation: string(value)})
+		r := newReconciler(source, newPod("b", 0, true))
+
+		_, err = r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+
+		recorded := recordedOf(t, r)
+		assert.Zero(t, recorded.Cascades)
+		assert.Equal(t, map[string]int32{"b": 0}, recorded.Pods)
+	})
+
+	t.Run("Ignores workloads without targets", func(t *testing.T) {
+		t.Parallel()
+
+		r := newReconciler(newSource(1, nil), newPod("a", 0, true))
+
+		_, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Nil(t, recordedOf(t, r))
+	})
+
+	t.Run("Workload not found", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := newReconciler().Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+	})
+}
+
+func TestPodRestartReconciler_ThresholdFor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		defaultVal int
+		annotation string
+		expected   int
+		wantErr    bool
+	}{
+		{name: "Unset default", expected: 1},
+		{name: "Default", defaultVal: 2, expected: 2},
+		{name: "Annotation overrides default", defaultVal: 2, annotation: "3", expected: 3},
+		{name: "Invalid annotation", defaultVal: 2, annotation: "all", expected: 2, wantErr: true},
+		{name: "Zero annotation", defaultVal: 2, annotation: "0", expected: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &PodRestartReconciler{Threshold: tt.defaultVal}
+			var ann map[string]string
+			if tt.annotation != "" {
+				ann = map[string]string{flag.PodRestartThresholdAnnotation: tt.annotation}
+			}
+
+			threshold, err := r.thresholdFor(newStableDeployment("source", ann))
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, threshold)
+		})
+	}
+}
@@ -0,0 +1,445 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/utils"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// preflightDecision is the outcome of checking a target before restarting it.
+type preflightDecision string
+
+const (
+	preflightRestart    preflightDecision = "restart"     // The target is restarted.
+	preflightPaused     preflightDecision = "paused"      // The target is paused and skipped.
+	preflightScaledDown preflightDecision = "scaled-down" // The target is scaled to zero replicas and skipped.
+	preflightDeferred   preflightDecision = "deferred"    // The target is rolling out and restarted once it finished.
+	preflightDelayed    preflightDecision = "delayed"     // The target is restarted once the delay of its edge passed.
+	preflightJoining    preflightDecision = "joining"     // The target waits for other upstreams of the cascade.
+	preflightJoined     preflightDecision = "joined"      // The target was restarted by another upstream of the cascade.
+
+	preflightAwaitingApproval preflightDecision = "awaiting-approval" // The target is restarted once the restart was approved.
+	preflightApprovalExpired  preflightDecision = "approval-expired"  // The restart was not approved in time and is skipped.
+)
+
+// deferredState tracks the targets of a source whose restart was deferred until their rollout finished.
+// It is persisted as JSON in an annotation on the source, so that every target is restarted only once.
+type deferredState struct {
+	Generation int64     `json:"generation"` // Generation of the source the cascade belongs to.
+	Since      time.Time `json:"since"`      // Time the first target was deferred.
+	Targets    []string  `json:"targets"`    // IDs of the targets pending a restart.
+}
+
+// loadDeferredState reads the deferred state from the source annotations.
+// Returns nil if no restart is deferred.
+func loadDeferredState(obj client.Object) (*deferredState, error) {
+	val, ok := obj.GetAnnotations()[flag.DeferredTargetsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	state := &deferredState{}
+	if err := json.Unmarshal([]byte(val), state); err != nil {
+		return nil, fmt.Errorf("invalid annotation %q: %w", flag.DeferredTargetsAnnotation, err)
+	}
+	return state, nil
+}
+
+// saveDeferredState persists the deferred state on the source workload.
+func (b *BaseReconciler) saveDeferredState(ctx context.Context, workload workloads.Workload, state *deferredState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to serialize deferred state: %w", err)
+	}
+	return utils.PatchWorkloadAnnotation(ctx, b.KubeClient, workload.Resource(), flag.DeferredTargetsAnnotation, string(data))
+}
+
+// clearDeferredState removes the deferred state from the source workload, if present.
+func (b *BaseReconciler) clearDeferredState(ctx context.Context, workload workloads.Workload) error {
+	if !hasAnnotation(workload.Resource(), flag.DeferredTargetsAnnotation) {
+		return nil
+	}
+	return utils.DeleteWorkloadAnnotation(ctx, b.KubeClient, workload.Resource(), flag.DeferredTargetsAnnotation)
+}
+
+// preflight decides whether a target can be restarted right now. Targets which cannot be fetched
+// are restarted, so that the failure is reported and retried like any other failed restart.
+// Targets reached through several paths of the cascade are only restarted once all upstreams
+// arrived, unless force is set. Targets requiring approval are only restarted once approved. Targets which
+// are skipped for good are recorded as skipped in the cascade, see skipJoin.
+func (b *BaseReconciler) preflight(
+	ctx context.Context,
+	workload workloads.Workload,
+	graph *dependencyGraph,
+	t targets.Target,
+	force bool,
+) (preflightDecision, string) {
+	obj := t.Resource()
+	if err := b.KubeClient.Get(ctx, client.ObjectKey{Namespace: t.Namespace(), Name: t.Name()}, obj); err != nil {
+		return preflightRestart, ""
+	}
+
+	if paused, reason := pausedTarget(obj); paused {
+		return b.skipTarget(ctx, workload, graph, obj, t.ID(), preflightPaused, reason)
+	}
+
+	if b.SkipScaledDownTargets && scaledToZero(obj) {
+		return b.skipTarget(ctx, workload, graph, obj, t.ID(), preflightScaledDown, "scaled to zero replicas")
+	}
+
+	// CronJobs are no workloads, but may forbid concurrent runs.
+	if cj, ok := obj.(*batchv1.CronJob); ok {
+		if cj.Spec.ConcurrencyPolicy == batchv1.ForbidConcurrent && len(cj.Status.Active) > 0 {
+			return preflightDeferred, fmt.Sprintf("cronjob forbids concurrent runs: active=%d", len(cj.Status.Active))
+		}
+	} else {
+		target, err := workloads.FromObject(obj)
+		if err != nil {
+			return preflightRestart, ""
+		}
+		if rolling, reason := target.RollingOut(); rolling {
+			return preflightDeferred, reason
+		}
+	}
+
+	decision, reason, err := b.join(ctx, workload, graph, obj, t.ID(), force)
+	if err != nil {
+		// The arrival is recorded again once the source is requeued.
+		return preflightJoining, fmt.Sprintf("failed to record arrival: %v", err)
+	}
+	if decision != preflightRestart {
+		return decision, reason
+	}
+	if decision, reason = b.approve(ctx, workload, obj, t); decision == preflightApprovalExpired {
+		return b.skipTarget(ctx, workload, graph, obj, t.ID(), decision, reason)
+	}
+	return decision, reason
+}
+
+// skipTarget records that the target is dropped from the cascade of the source and returns the decision.
+// If the record fails, the target is skipped anyway and its downstream joins wait for the join timeout.
+func (b *BaseReconciler) skipTarget(
+	ctx context.Context,
+	workload workloads.Workload,
+	graph *dependencyGraph,
+	obj client.Object,
+	targetID string,
+	decision preflightDecision,
+	reason string,
+) (preflightDecision, string) {
+	if err := b.skipJoin(ctx, workload, graph, obj, targetID, reason); err != nil {
+		b.Logger.Error(err, "Failed to record skipped target", "workloadID", workload.ID(), "targetID", targetID)
+	}
+	return decision, reason
+}
+
+// pausedTarget reports whether the target is paused, along with the reason.
+func pausedTarget(obj client.Object) (paused bool, reason string) {
+	switch res := obj.(type) {
+	case *appsv1.Deployment:
+		if res.Spec.Paused {
+			return true, "rollout is paused"
+		}
+	case *batchv1.Job:
+		if workloads.JobSuspended(res) {
+			return true, "job is suspended"
+		}
+	case *batchv1.CronJob:
+		if res.Spec.Suspend != nil && *res.Spec.Suspend {
+			return true, "cronjob is suspended"
+		}
+	}
+	return false, ""
+}
+
+// scaledToZero reports whether the target is explicitly scaled to zero replicas.
+func scaledToZero(obj client.Object) bool {
+	switch res := obj.(type) {
+	case *appsv1.Deployment:
+		return res.Spec.Replicas != nil && *res.Spec.Replicas == 0
+	case *appsv1.StatefulSet:
+		return res.Spec.Replicas != nil && *res.Spec.Replicas == 0
+	default:
+		return false
+	}
+}
+
+// preflightTargets checks every target before it is restarted. Paused, scaled-down, already joined and
+// unapproved targets are skipped, targets which are rolling out are returned as deferred, targets waiting
+// for other upstreams of the cascade as joining and targets waiting for their delay to pass since the start
+// of the cascade or for approval as held. Targets in alreadyDeferred were deferred by a previous
+// reconciliation and are neither counted nor reported again while they keep waiting.
+func (b *BaseReconciler) preflightTargets(
+	ctx context.Context,
+	workload workloads.Workload,
+	graph *dependencyGraph,
+	targetList []targets.Target,
+	alreadyDeferred []string,
+	since time.Time,
+) (ready, deferred, joining, held []targets.Target) {
+	res := workload.Resource()
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	specs := edgeSpecs(res)
+	for _, t := range targetList {
+		decision, reason := preflightDelayed, ""
+		if delay := specs.lookup(t).Delay; delay != nil && time.Since(since) < delay.Duration {
+			reason = fmt.Sprintf("delayed by %s", delay.Duration)
+		} else {
+			decision, reason = b.preflight(ctx, workload, graph, t, false)
+		}
+		if slices.Contains(alreadyDeferred, t.ID()) {
+			switch decision {
+			case preflightDeferred:
+				deferred = append(deferred, t)
+				continue
+			case preflightJoining:
+				joining = append(joining, t)
+				continue
+			case preflightDelayed, preflightAwaitingApproval:
+				held = append(held, t)
+				continue
+			}
+		}
+		b.Metrics.IncTargetPreflight(t.Namespace(), t.Name(), t.Kind().String(), string(decision))
+
+		switch decision {
+		case preflightPaused:
+			log.Info("Skipping restart of paused target", "targetID", t.ID())
+			b.Recorder.Eventf(
+				res,
+				nil,
+				corev1.EventTypeWarning,
+				"RestartSkipped",
+				"PreflightTarget",
+				"Cascader skipped restart of %s: %s",
+				t.ID(),
+				reason,
+			)
+		case preflightScaledDown:
+			log.Info("Skipping restart of scaled-down target", "targetID", t.ID())
+			b.Recorder.Eventf(
+				res,
+				nil,
+				corev1.EventTypeNormal,
+				"RestartSkipped",
+				"PreflightTarget",
+				"Cascader skipped restart of %s: %s",
+				t.ID(),
+				reason,
+			)
+		case preflightDeferred:
+			log.Info("Deferring restart of target until its rollout finished", "targetID", t.ID(), "reason", reason)
+			b.Recorder.Eventf(
+				res,
+				nil,
+				corev1.EventTypeNormal,
+				"RestartDeferred",
+				"PreflightTarget",
+				"Cascader deferred restart of %s until its rollout finished: %s",
+				t.ID(),
+				reason,
+			)
+			deferred = append(deferred, t)
+		case preflightDelayed:
+			log.Info("Deferring restart of target until its delay passed", "targetID", t.ID(), "reason", reason)
+			b.Recorder.Eventf(
+				res,
+				nil,
+				corev1.EventTypeNormal,
+				"RestartDeferred",
+				"PreflightTarget",
+				"Cascader deferred restart of %s: %s",
+				t.ID(),
+				reason,
+			)
+			held = append(held, t)
+		case preflightJoining:
+			log.Info("Deferring restart of target until all upstreams of the cascade are stable", "targetID", t.ID(), "reason", reason)
+			b.Recorder.Eventf(
+				res,
+				nil,
+				corev1.EventTypeNormal,
+				"RestartDeferred",
+				"PreflightTarget",
+				"Cascader deferred restart of %s until all upstreams of the cascade are stable: %s",
+				t.ID(),
+				reason,
+			)
+			joining = append(joining, t)
+		case preflightJoined:
+			log.Info("Skipping restart of target already restarted in this cascade", "targetID", t.ID(), "reason", reason)
+		case preflightAwaitingApproval:
+			// The approval request is reported once it is recorded, see approve.
+			log.Info("Deferring restart of target until it is approved", "targetID", t.ID(), "reason", reason)
+			held = append(held, t)
+		case preflightApprovalExpired:
+			log.Info("Skipping restart of target which was not approved in time", "targetID", t.ID(), "reason", reason)
+			b.Recorder.Eventf(
+				res,
+				nil,
+				corev1.EventTypeWarning,
+				"ApprovalExpired",
+				"PreflightTarget",
+				"Cascader skipped restart of %s: %s",
+				t.ID(),
+				reason,
+			)
+		default:
+			ready = append(ready, t)
+		}
+	}
+
+	return ready, deferred, joining, held
+}
+
+// scheduleDeferral records the deferred targets of a cascade on the source. Targets already deferred
+// for the same cascade are kept, so that every target is restarted only once.
+func (b *BaseReconciler) scheduleDeferral(ctx context.Context, workload workloads.Workload, deferred []targets.Target) {
+	res := workload.Resource()
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	state, err := loadDeferredState(res)
+	if err != nil || state == nil || state.Generation != res.GetGeneration() {
+		state = &deferredState{Generation: res.GetGeneration(), Since: time.Now()}
+	}
+	for _, t := range deferred {
+		if !slices.Contains(state.Targets, t.ID()) {
+			state.Targets = append(state.Targets, t.ID())
+		}
+	}
+
+	if err := b.saveDeferredState(ctx, workload, state); err != nil {
+		log.Error(err, "Failed to persist deferred state; deferred targets will not be restarted", "targets", state.Targets)
+	}
+}
+
+// restartDeferred restarts the deferred targets of the source whose rollout finished or whose upstreams
+// all arrived or whose restart was approved in the meantime. After the stability timeout, targets which are
+// still rolling out are given up on. After the join timeout, targets still waiting for other upstreams are
+// restarted without them. Delayed targets keep waiting until their delay passed and targets waiting for approval until
+// their approval request expires.
+func (b *BaseReconciler) restartDeferred(
+	ctx context.Context,
+	workload workloads.Workload,
+	graph *dependencyGraph,
+	state *deferredState,
+) (ctrl.Result, error) {
+	res := workload.Resource()
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	all, err := b.extractTargets(ctx, res)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to create targets: %w", err)
+	}
+
+	// Only restart targets which are still referenced by the source.
+	pending := make([]targets.Target, 0, len(state.Targets))
+	for _, t := range all {
+		if slices.Contains(state.Targets, t.ID()) {
+			pending = append(pending, t)
+		}
+	}
+
+	ready, rolling, joining, held := b.preflightTargets(ctx, workload, graph, pending, state.Targets, state.Since)
+	if len(joining) > 0 && time.Since(state.Since) >= b.JoinTimeout {
+		log.Info("Upstreams of the cascade did not arrive in time, restarting without them", "targets", targetIDs(joining))
+		for _, t := range joining {
+			switch decision, _ := b.preflight(ctx, workload, graph, t, true); decision {
+			case preflightRestart:
+				ready = append(ready, t)
+			case preflightAwaitingApproval:
+				held = append(held, t)
+			}
+		}
+		joining = nil
+	}
+	if len(rolling) > 0 && b.StabilityTimeout > 0 && time.Since(state.Since) >= b.StabilityTimeout {
+		b.Recorder.Eventf(
+			res,
+			nil,
+			corev1.EventTypeWarning,
+			"RestartAbandoned",
+			"PreflightTarget",
+			"Cascader gave up restarting %v, rollout did not finish within %s",
+			targetIDs(rolling),
+			b.StabilityTimeout,
+		)
+		log.Error(errors.New("deferred restart timed out"), "Giving up on deferred targets", "targets", targetIDs(rolling))
+		rolling = nil
+	}
+
+	if waiting := slices.Concat(rolling, joining, held); len(waiting) == 0 {
+		if err := b.clearDeferredState(ctx, workload); err != nil {
+			log.Error(err, "Failed to delete deferred annotation")
+		}
+	} else {
+		state.Targets = targetIDs(waiting)
+		if err := b.saveDeferredState(ctx, workload, state); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to patch deferred state: %w", err)
+		}
+	}
+
+	dur, err := b.requeueDurationFor(res)
+	if err != nil {
+		log.Error(err, fmt.Sprintf("Invalid requeue annotation, using default: %s", b.RequeueAfterDefault))
+	}
+
+	if len(ready) == 0 {
+		return requeuePending(res, ctrl.Result{}, dur), nil
+	}
+
+	succ, failed := b.triggerReloads(ctx, workload, ready)
+	if len(failed) > 0 {
+		log.Error(errors.New("partial target reload failure"), "Some targets failed to reload", "succeeded", succ, "failed", len(failed))
+		_, _ = b.scheduleVerification(ctx, workload, restartedTargets(ready, failed), dur)
+		result, err := b.scheduleRetry(ctx, workload, 0, failed)
+		return requeuePending(res, result, dur), err
+	}
+
+	log.Info("Finished handling deferred targets", "succeeded", succ, "failed", 0)
+	result, err := b.scheduleVerification(ctx, workload, ready, dur)
+	return requeuePending(res, result, dur), err
+}
+
+// requeuePending requeues the source after requeueAfter while deferred restarts or post-restart
+// checks are pending, unless the result already requeues it.
+func requeuePending(obj client.Object, result ctrl.Result, requeueAfter time.Duration) ctrl.Result {
+	if result.RequeueAfter > 0 {
+		return result
+	}
+	if hasAnnotation(obj, flag.DeferredTargetsAnnotation) || hasAnnotation(obj, flag.PendingVerificationAnnotation) {
+		result.RequeueAfter = requeueAfter
+	}
+	return result
+}
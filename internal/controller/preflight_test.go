@@ -0,0 +1,307 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	internalmetrics "github.com/thurgauerkb/cascader/internal/metrics"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+	"github.com/thurgauerkb/cascader/test/testutils"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// newRollingDeployment returns a Deployment which still replaces Pods of a previous revision.
+func newRollingDeployment(name string) *appsv1.Deployment {
+	dep := newStableDeployment(name, nil)
+	dep.Spec.Replicas = testutils.Int32Ptr(2)
+	dep.Status.Replicas = 3
+	return dep
+}
+
+// restarted reports whether Cascader restarted the given target.
+func restarted(t *testing.T, c client.Client, target client.Object) bool {
+	t.Helper()
+
+	updated := &appsv1.Deployment{}
+	require.NoError(t, c.Get(t.Context(), client.ObjectKeyFromObject(target), updated))
+	_, ok := updated.Spec.Template.Annotations[flag.LastObservedRestartAnnotation]
+	return ok
+}
+
+func TestPreflight(t *testing.T) {
+	t.Parallel()
+
+	paused := newStableDeployment("paused", nil)
+	paused.Spec.Paused = true
+	scaledDown := newStableDeployment("scaled-down", nil)
+	scaledDown.Spec.Replicas = testutils.Int32Ptr(0)
+
+	tests := []struct {
+		name           string
+		target         string
+		skipScaledDown bool
+		decision       preflightDecision
+		reason         string
+	}{
+		{name: "Stable target", target: "stable", decision: preflightRestart},
+		{name: "Paused target", target: "paused", decision: preflightPaused, reason: "rollout is paused"},
+		{name: "Rolling target", target: "rolling", decision: preflightDeferred, reason: "old replicas pending: replicas=3, updated=1"},
+		{name: "Scaled-down target", target: "scaled-down", decision: preflightRestart},
+		{name: "Scaled-down target skipped", target: "scaled-down", skipScaledDown: true, decision: preflightScaledDown, reason: "scaled to zero replicas"},
+		{name: "Missing target", target: "missing", decision: preflightRestart},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := newStableDeployment("source", nil)
+			reconciler := createBaseReconciler(source, newStableDeployment("stable", nil), paused.DeepCopy(), newRollingDeployment("rolling"), scaledDown.DeepCopy())
+			reconciler.SkipScaledDownTargets = tt.skipScaledDown
+
+			decision, reason := reconciler.preflight(
+				t.Context(),
+				&workloads.DeploymentWorkload{Deployment: source},
+				reconciler.newDependencyGraph(),
+				targets.NewDeployment("default", tt.target, reconciler.KubeClient),
+				false,
+			)
+			assert.Equal(t, tt.decision, decision)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestPreflight_BatchTargets(t *testing.T) {
+	t.Parallel()
+
+	suspendedJob := newJob("suspended", nil)
+	suspendedJob.Spec.Suspend = ptr.To(true)
+	runningJob := newJob("running", nil)
+	runningJob.Status.Active = 1
+	newCronJob := func(name string, suspend bool, policy batchv1.ConcurrencyPolicy, active int) *batchv1.CronJob {
+		cj := &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       batchv1.CronJobSpec{Suspend: ptr.To(suspend), ConcurrencyPolicy: policy},
+		}
+		for range active {
+			cj.Status.Active = append(cj.Status.Active, corev1.ObjectReference{Kind: "Job"})
+		}
+		return cj
+	}
+
+	tests := []struct {
+		name     string
+		target   targets.Target
+		decision preflightDecision
+		reason   string
+	}{
+		{name: "Completed Job", target: targets.NewJob("default", "completed", "", nil), decision: preflightRestart},
+		{name: "Running Job", target: targets.NewJob("default", "running", "", nil), decision: preflightDeferred, reason: "job is running: active=1"},
+		{name: "Suspended Job", target: targets.NewJob("default", "suspended", "", nil), decision: preflightPaused, reason: "job is suspended"},
+		{name: "CronJob", target: targets.NewCronJob("default", "allow", "", nil), decision: preflightRestart},
+		{name: "Suspended CronJob", target: targets.NewCronJob("default", "suspended", "", nil), decision: preflightPaused, reason: "cronjob is suspended"},
+		{name: "CronJob forbidding concurrent runs", target: targets.NewCronJob("default", "forbid", "", nil), decision: preflightDeferred, reason: "cronjob forbids concurrent runs: active=1"},
+		{name: "Idle CronJob forbidding concurrent runs", target: targets.NewCronJob("default", "idle", "", nil), decision: preflightRestart},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := newStableDeployment("source", nil)
+			reconciler := createBaseReconciler(
+				source,
+				newJob("completed", nil, batchv1.JobCondition{Type: batchv1.JobComplete}),
+				runningJob.DeepCopy(),
+				suspendedJob.DeepCopy(),
+				newCronJob("allow", false, batchv1.AllowConcurrent, 1),
+				newCronJob("suspended", true, batchv1.AllowConcurrent, 0),
+				newCronJob("forbid", false, batchv1.ForbidConcurrent, 1),
+				newCronJob("idle", false, batchv1.ForbidConcurrent, 0),
+			)
+
+			decision, reason := reconciler.preflight(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, reconciler.newDependencyGraph(), tt.target, false)
+			assert.Equal(t, tt.decision, decision)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestReconcileWorkload_Preflight(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Paused target is skipped", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{
+			"cascader.tkb.ch/deployment": "paused,stable",
+		})
+		paused := newStableDeployment("paused", nil)
+		paused.Spec.Paused = true
+		stable := newStableDeployment("stable", nil)
+		reconciler := createBaseReconciler(source, paused, stable)
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+		reg := prometheus.NewRegistry()
+		reconciler.Metrics = internalmetrics.NewRegistry(reg)
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.Equal(t, "Warning RestartSkipped Cascader skipped restart of Deployment/default/paused: rollout is paused", <-recorder.Events)
+		assert.False(t, restarted(t, reconciler.KubeClient, paused))
+		assert.True(t, restarted(t, reconciler.KubeClient, stable))
+
+		expected := `
+# HELP cascader_target_preflight_total Total number of preflight decisions taken before restarting a target, by decision.
+# TYPE cascader_target_preflight_total counter
+cascader_target_preflight_total{decision="paused",name="paused",namespace="default",resource_kind="Deployment"} 1
+cascader_target_preflight_total{decision="restart",name="stable",namespace="default",resource_kind="Deployment"} 1
+`
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "cascader_target_preflight_total"))
+	})
+
+	t.Run("Scaled-down target is skipped", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{
+			"cascader.tkb.ch/deployment": "scaled-down",
+		})
+		scaledDown := newStableDeployment("scaled-down", nil)
+		scaledDown.Spec.Replicas = testutils.Int32Ptr(0)
+		reconciler := createBaseReconciler(source, scaledDown)
+		reconciler.SkipScaledDownTargets = true
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.False(t, restarted(t, reconciler.KubeClient, scaledDown))
+	})
+
+	t.Run("Rolling target is restarted once its rollout finished", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{
+			"cascader.tkb.ch/deployment": "rolling,stable",
+		})
+		rolling := newRollingDeployment("rolling")
+		stable := newStableDeployment("stable", nil)
+		reconciler := createBaseReconciler(source, rolling, stable)
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+		reg := prometheus.NewRegistry()
+		reconciler.Metrics = internalmetrics.NewRegistry(reg)
+		workload := &workloads.DeploymentWorkload{Deployment: source}
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), workload)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: defaultRequeuAfter}, result)
+		assert.Equal(t, "Normal RestartDeferred Cascader deferred restart of Deployment/default/rolling until its rollout finished: old replicas pending: replicas=3, updated=1", <-recorder.Events)
+		assert.False(t, restarted(t, reconciler.KubeClient, rolling))
+		assert.True(t, restarted(t, reconciler.KubeClient, stable))
+
+		state, err := loadDeferredState(source)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Deployment/default/rolling"}, state.Targets)
+
+		// While the target keeps rolling out, the deferral is neither reported nor counted again.
+		assert.Equal(t, `Normal ReloadSucceeded Cascader triggered reload due to change in "Deployment/default/source"`, <-recorder.Events)
+		result, err = reconciler.ReconcileWorkload(t.Context(), workload)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: defaultRequeuAfter}, result)
+		assert.Empty(t, recorder.Events)
+		assert.False(t, restarted(t, reconciler.KubeClient, rolling))
+
+		// Once the rollout finished, the target is restarted and the deferral is cleared.
+		settled := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(rolling), settled))
+		settled.Status.Replicas = 2
+		settled.Status.UpdatedReplicas = 2
+		require.NoError(t, reconciler.KubeClient.Status().Update(t.Context(), settled))
+
+		result, err = reconciler.ReconcileWorkload(t.Context(), workload)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.True(t, restarted(t, reconciler.KubeClient, rolling))
+		assert.NotContains(t, source.Annotations, flag.DeferredTargetsAnnotation)
+
+		expected := `
+# HELP cascader_target_preflight_total Total number of preflight decisions taken before restarting a target, by decision.
+# TYPE cascader_target_preflight_total counter
+cascader_target_preflight_total{decision="deferred",name="rolling",namespace="default",resource_kind="Deployment"} 1
+cascader_target_preflight_total{decision="restart",name="rolling",namespace="default",resource_kind="Deployment"} 1
+cascader_target_preflight_total{decision="restart",name="stable",namespace="default",resource_kind="Deployment"} 1
+`
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "cascader_target_preflight_total"))
+	})
+
+	t.Run("Rolling target is given up on after timeout", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{
+			"cascader.tkb.ch/deployment":   "rolling",
+			flag.DeferredTargetsAnnotation: `{"generation":1,"since":"` + time.Now().Add(-time.Hour).Format(time.RFC3339) + `","targets":["Deployment/default/rolling"]}`,
+		})
+		rolling := newRollingDeployment("rolling")
+		reconciler := createBaseReconciler(source, rolling)
+		reconciler.StabilityTimeout = 30 * time.Minute
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.Equal(t, "Warning RestartAbandoned Cascader gave up restarting [Deployment/default/rolling], rollout did not finish within 30m0s", <-recorder.Events)
+		assert.False(t, restarted(t, reconciler.KubeClient, rolling))
+		assert.NotContains(t, source.Annotations, flag.DeferredTargetsAnnotation)
+	})
+
+	t.Run("Deferral is superseded by a new restart", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{
+			"cascader.tkb.ch/deployment":   "stable",
+			flag.DeferredTargetsAnnotation: `{"generation":0,"since":"` + time.Now().Format(time.RFC3339) + `","targets":["Deployment/default/stable"]}`,
+		})
+		stable := newStableDeployment("stable", nil)
+		reconciler := createBaseReconciler(source, stable)
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.True(t, restarted(t, reconciler.KubeClient, stable))
+		assert.NotContains(t, source.Annotations, flag.DeferredTargetsAnnotation)
+	})
+}
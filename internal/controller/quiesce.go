@@ -0,0 +1,236 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Actions of targets quiesced while their source is updated, as reported by the quiesce metric.
+const (
+	quiesceStop    = "quiesce"
+	quiesceRestore = "restore"
+	quiesceTimeout = "timeout"
+)
+
+// quiesceState is persisted as JSON in an annotation on a target stopped while its source is updated,
+// so that the target is restored once the source is stable or the deadline passed.
+type quiesceState struct {
+	Source   string    `json:"source"`             // ID of the source being updated.
+	Replicas *int32    `json:"replicas,omitempty"` // Replicas of a Deployment or StatefulSet before it was scaled to zero.
+	Deadline time.Time `json:"deadline"`           // Time the target is restored, even if the source is not stable.
+}
+
+// loadQuiesceState reads the quiesce state from the target annotations.
+// Returns nil if the target is not quiesced.
+func loadQuiesceState(obj client.Object) (*quiesceState, error) {
+	val, ok := obj.GetAnnotations()[flag.QuiescedAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	state := &quiesceState{}
+	if err := json.Unmarshal([]byte(val), state); err != nil {
+		return nil, fmt.Errorf("invalid annotation %q: %w", flag.QuiescedAnnotation, err)
+	}
+	return state, nil
+}
+
+// quiescedTargets returns the targets whose edge quiesces them while the source is updated.
+func quiescedTargets(source client.Object, targetList []targets.Target) []targets.Target {
+	specs := edgeSpecs(source)
+
+	var quiesced []targets.Target
+	for _, t := range targetList {
+		if specs.lookup(t).Quiesce {
+			quiesced = append(quiesced, t)
+		}
+	}
+	return quiesced
+}
+
+// quiesceTimeoutFor returns the maximum duration the targets of the source stay quiesced.
+// The annotation on the source overrides the default; an invalid annotation falls back to the default.
+func (b *BaseReconciler) quiesceTimeoutFor(obj client.Object) (time.Duration, error) {
+	timeout := b.QuiesceTimeout
+	if timeout <= 0 {
+		timeout = 30 * time.Minute
+	}
+
+	val := strings.TrimSpace(obj.GetAnnotations()[flag.QuiesceTimeoutAnnotation])
+	if val == "" {
+		return timeout, nil
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil || d <= 0 {
+		return timeout, fmt.Errorf("invalid annotation %q: must be a positive duration, got %q", flag.QuiesceTimeoutAnnotation, val)
+	}
+	return d, nil
+}
+
+// quiesceTargets stops the targets before the source is updated: Deployments and StatefulSets are scaled to
+// zero, CronJobs are suspended. Targets which are already stopped or quiesced by another source are left untouched.
+func (b *BaseReconciler) quiesceTargets(ctx context.Context, workload workloads.Workload, quiesced []targets.Target, now time.Time) {
+	res := workload.Resource()
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	timeout, err := b.quiesceTimeoutFor(res)
+	if err != nil {
+		log.Error(err, fmt.Sprintf("Invalid quiesce timeout annotation, using default: %s", timeout))
+	}
+
+	for _, t := range quiesced {
+		obj := t.Resource()
+		if err := b.KubeClient.Get(ctx, client.ObjectKey{Namespace: t.Namespace(), Name: t.Name()}, obj); err != nil {
+			if !kerrors.IsNotFound(err) {
+				log.Error(err, "Failed to fetch target to quiesce", "targetID", t.ID())
+			}
+			continue
+		}
+		if hasAnnotation(obj, flag.QuiescedAnnotation) {
+			continue
+		}
+
+		state := &quiesceState{Source: workload.ID(), Deadline: now.Add(timeout)}
+		var change func() error
+		switch target := obj.(type) {
+		case *batchv1.CronJob:
+			if target.Spec.Suspend != nil && *target.Spec.Suspend {
+				continue
+			}
+			change = func() error { target.Spec.Suspend = ptr.To(true); return nil }
+		default:
+			replicas, ok := specReplicas(obj)
+			if !ok || replicas == 0 {
+				continue
+			}
+			state.Replicas = &replicas
+			change = func() error { return setReplicas(obj, 0) }
+		}
+
+		if err := b.patchTargetState(ctx, obj, flag.QuiescedAnnotation, state, change); err != nil {
+			log.Error(err, "Failed to quiesce target", "targetID", t.ID())
+			b.Recorder.Eventf(res, nil, corev1.EventTypeWarning, "QuiesceFailed", "Quiesce", "Cascader failed to quiesce %s: %v", t.ID(), err)
+			continue
+		}
+		log.Info("Quiesced target while the source is updated", "targetID", t.ID(), "deadline", state.Deadline)
+		b.Metrics.IncQuiesce(t.Namespace(), t.Name(), t.Kind().String(), quiesceStop)
+		b.Recorder.Eventf(
+			res,
+			nil,
+			corev1.EventTypeNormal,
+			"TargetQuiesced",
+			"Quiesce",
+			"Cascader stopped %s until the update finished, at the latest until %s",
+			t.ID(),
+			state.Deadline.Format(time.RFC3339),
+		)
+	}
+}
+
+// restoreQuiesced restores the targets quiesced by the source: Deployments and StatefulSets are scaled back to
+// their replicas, CronJobs are resumed. Unless all is set, only targets whose deadline passed are restored.
+// Returns the restored targets and the duration until the next deadline of a target which is still quiesced.
+func (b *BaseReconciler) restoreQuiesced(
+	ctx context.Context,
+	workload workloads.Workload,
+	targetList []targets.Target,
+	now time.Time,
+	all bool,
+) (restored []targets.Target, next time.Duration) {
+	res := workload.Resource()
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	for _, t := range targetList {
+		obj := t.Resource()
+		if err := b.KubeClient.Get(ctx, client.ObjectKey{Namespace: t.Namespace(), Name: t.Name()}, obj); err != nil {
+			if !kerrors.IsNotFound(err) {
+				log.Error(err, "Failed to fetch quiesced target", "targetID", t.ID())
+			}
+			continue
+		}
+		state, err := loadQuiesceState(obj)
+		if err != nil {
+			log.Error(err, "Ignoring invalid quiesce state", "targetID", t.ID())
+			continue
+		}
+		if state == nil || state.Source != workload.ID() {
+			continue
+		}
+		expired := !now.Before(state.Deadline)
+		if !all && !expired {
+			if wait := state.Deadline.Sub(now); next == 0 || wait < next {
+				next = wait
+			}
+			continue
+		}
+
+		// Targets started by someone else in the meantime are left as they are.
+		change := func() error {
+			switch target := obj.(type) {
+			case *batchv1.CronJob:
+				target.Spec.Suspend = ptr.To(false)
+			default:
+				if replicas, _ := specReplicas(obj); replicas == 0 && state.Replicas != nil {
+					return setReplicas(obj, *state.Replicas)
+				}
+			}
+			return nil
+		}
+		if err := b.patchTargetState(ctx, obj, flag.QuiescedAnnotation, nil, change); err != nil {
+			log.Error(err, "Failed to restore quiesced target", "targetID", t.ID())
+			b.Recorder.Eventf(res, nil, corev1.EventTypeWarning, "QuiesceFailed", "Quiesce", "Cascader failed to restore %s: %v", t.ID(), err)
+			continue
+		}
+		restored = append(restored, t)
+
+		if !all {
+			log.Info("Restored quiesced target before the source became stable", "targetID", t.ID())
+			b.Metrics.IncQuiesce(t.Namespace(), t.Name(), t.Kind().String(), quiesceTimeout)
+			b.Recorder.Eventf(
+				res,
+				nil,
+				corev1.EventTypeWarning,
+				"QuiesceTimeout",
+				"Quiesce",
+				"Cascader restored %s before the update finished: quiesced until %s",
+				t.ID(),
+				state.Deadline.Format(time.RFC3339),
+			)
+			continue
+		}
+		log.Info("Restored quiesced target", "targetID", t.ID())
+		b.Metrics.IncQuiesce(t.Namespace(), t.Name(), t.Kind().String(), quiesceRestore)
+		b.Recorder.Eventf(res, nil, corev1.EventTypeNormal, "QuiesceRestored", "Quiesce", "Cascader restored %s after the update", t.ID())
+	}
+	return restored, next
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/test/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const quiesceTargetsAnnotation = `version: v1
+targets:
+  - kind: Deployment
+    name: consumer
+    quiesce: true
+  - kind: CronJob
+    name: report
+    quiesce: true
+  - kind: Deployment
+    name: other
+`
+
+// newUpdatingDeployment returns a source whose rollout is still in progress.
+func newUpdatingDeployment(name string, annotations map[string]string) *appsv1.Deployment {
+	dep := newStableDeployment(name, annotations)
+	dep.Generation = 2
+	return dep
+}
+
+// finishRollout simulates the Deployment controller observing the latest generation of a Deployment.
+func finishRollout(t *testing.T, c client.Client, name string) {
+	t.Helper()
+
+	dep := getDeployment(t, c, name)
+	dep.Status.ObservedGeneration = dep.Generation
+	require.NoError(t, c.Status().Update(t.Context(), dep))
+}
+
+// quiesceFixtures returns a consumer with three replicas, a CronJob and an unrelated target.
+func quiesceFixtures(annotations map[string]string) []client.Object {
+	consumer := newStableDeployment("consumer", annotations)
+	consumer.Spec.Replicas = testutils.Int32Ptr(3)
+	return []client.Object{
+		consumer,
+		&batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "default", Annotations: annotations},
+			Spec:       batchv1.CronJobSpec{Schedule: "0 * * * *"},
+		},
+		newStableDeployment("other", nil),
+	}
+}
+
+func getCronJob(t *testing.T, c client.Client, name string) *batchv1.CronJob {
+	t.Helper()
+
+	cj := &batchv1.CronJob{}
+	require.NoError(t, c.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: name}, cj))
+	return cj
+}
+
+func TestQuiesceTimeoutFor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		defaultVal time.Duration
+		annotation string
+		expected   time.Duration
+		wantErr    bool
+	}{
+		{name: "Unset default", expected: 30 * time.Minute},
+		{name: "Default", defaultVal: time.Hour, expected: time.Hour},
+		{name: "Annotation overrides default", defaultVal: time.Hour, annotation: "2h", expected: 2 * time.Hour},
+		{name: "Invalid annotation", defaultVal: time.Hour, annotation: "soon", expected: time.Hour, wantErr: true},
+		{name: "Zero annotation", defaultVal: time.Hour, annotation: "0s", expected: time.Hour, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reconciler := createBaseReconciler()
+			reconciler.QuiesceTimeout = tt.defaultVal
+
+			var ann map[string]string
+			if tt.annotation != "" {
+				ann = map[string]string{flag.QuiesceTimeoutAnnotation: tt.annotation}
+			}
+			timeout, err := reconciler.quiesceTimeoutFor(newStableDeployment("source", ann))
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, timeout)
+		})
+	}
+}
+
+func TestQuiescedTargets(t *testing.T) {
+	t.Parallel()
+
+	source := newStableDeployment("source", map[string]string{flag.TargetsAnnotation: quiesceTargetsAnnotation})
+	all := []targets.Target{
+		targets.NewDeployment("default", "consumer", nil),
+		targets.NewCronJob("default", "report", "", nil),
+		targets.NewDeployment("default", "other", nil),
+	}
+
+	assert.Equal(t, []string{"Deployment/default/consumer", "CronJob/default/report"}, targetIDs(quiescedTargets(source, all)))
+}
+
+func TestQuiesce(t *testing.T) {
+	t.Parallel()
+
+	sourceAnnotations := map[string]string{flag.TargetsAnnotation: quiesceTargetsAnnotation}
+
+	t.Run("Quiesce during update and restore once stable", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler(append(quiesceFixtures(nil), newUpdatingDeployment("db", sourceAnnotations))...)
+		c := reconciler.KubeClient
+
+		result := reconcileDeployment(t, reconciler, "db")
+		assert.Positive(t, result.RequeueAfter)
+
+		consumer := getDeployment(t, c, "consumer")
+		assert.Equal(t, int32(0), *consumer.Spec.Replicas)
+		state, err := loadQuiesceState(consumer)
+		require.NoError(t, err)
+		assert.Equal(t, "Deployment/default/db", state.Source)
+		assert.Equal(t, ptr.To[int32](3), state.Replicas)
+		assert.True(t, *getCronJob(t, c, "report").Spec.Suspend)
+		assert.Equal(t, int32(1), *getDeployment(t, c, "other").Spec.Replicas)
+
+		finishRollout(t, c, "db")
+		reconcileDeployment(t, reconciler, "db")
+
+		consumer = getDeployment(t, c, "consumer")
+		assert.Equal(t, int32(3), *consumer.Spec.Replicas)
+		assert.NotContains(t, consumer.Annotations, flag.QuiescedAnnotation)
+		assert.False(t, restarted(t, c, consumer), "quiesced target must not be restarted")
+		report := getCronJob(t, c, "report")
+		assert.False(t, *report.Spec.Suspend)
+		assert.NotContains(t, report.Annotations, flag.QuiescedAnnotation)
+		assert.True(t, restarted(t, c, getDeployment(t, c, "other")))
+	})
+
+	t.Run("Requeue at the deadline", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler(append(quiesceFixtures(nil), newUpdatingDeployment("db", sourceAnnotations))...)
+		reconciler.QuiesceTimeout = 3 * time.Second
+
+		result := reconcileDeployment(t, reconciler, "db")
+		assert.Positive(t, result.RequeueAfter)
+		assert.LessOrEqual(t, result.RequeueAfter, 3*time.Second)
+	})
+
+	t.Run("Restore once the timeout expired", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler(append(quiesceFixtures(nil), newUpdatingDeployment("db", sourceAnnotations))...)
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+		c := reconciler.KubeClient
+
+		reconcileDeployment(t, reconciler, "db")
+		assert.Contains(t, <-recorder.Events, "Normal TargetQuiesced Cascader stopped Deployment/default/consumer until the update finished")
+		assert.Contains(t, <-recorder.Events, "Normal TargetQuiesced Cascader stopped CronJob/default/report until the update finished")
+
+		// Move the deadline of the consumer into the past.
+		consumer := getDeployment(t, c, "consumer")
+		state, err := loadQuiesceState(consumer)
+		require.NoError(t, err)
+		state.Deadline = time.Now().Add(-time.Second)
+		data, err := json.Marshal(state)
+		require.NoError(t, err)
+		consumer.Annotations[flag.QuiescedAnnotation] = string(data)
+		require.NoError(t, c.Update(t.Context(), consumer))
+
+		reconcileDeployment(t, reconciler, "db")
+
+		consumer = getDeployment(t, c, "consumer")
+		assert.Equal(t, int32(3), *consumer.Spec.Replicas)
+		assert.NotContains(t, consumer.Annotations, flag.QuiescedAnnotation)
+		assert.Contains(t, <-recorder.Events, "Warning QuiesceTimeout Cascader restored Deployment/default/consumer before the update finished")
+		assert.True(t, *getCronJob(t, c, "report").Spec.Suspend, "target before its deadline stays quiesced")
+	})
+
+	t.Run("Restore when the cascade is aborted", func(t *testing.T) {
+		t.Parallel()
+
+		quiesced := map[string]string{flag.QuiescedAnnotation: `{"source":"Deployment/default/db","replicas":3,"deadline":"2999-01-01T00:00:00Z"}`}
+		objects := quiesceFixtures(quiesced)
+		objects[0].(*appsv1.Deployment).Spec.Replicas = testutils.Int32Ptr(0)
+		objects[1].(*batchv1.CronJob).Spec.Suspend = ptr.To(true)
+		db := newUpdatingDeployment("db", map[string]string{
+			flag.TargetsAnnotation:             quiesceTargetsAnnotation,
+			flag.LastObservedRestartAnnotation: time.Now().Add(-2 * time.Hour).Format(time.RFC3339),
+		})
+		reconciler := createBaseReconciler(append(objects, db)...)
+		reconciler.StabilityTimeout = time.Hour
+		c := reconciler.KubeClient
+
+		reconcileDeployment(t, reconciler, "db")
+
+		assert.Equal(t, int32(3), *getDeployment(t, c, "consumer").Spec.Replicas)
+		assert.False(t, *getCronJob(t, c, "report").Spec.Suspend)
+		assert.NotContains(t, getDeployment(t, c, "db").Annotations, flag.LastObservedRestartAnnotation)
+	})
+
+	t.Run("Targets quiesced by another source or started meanwhile", func(t *testing.T) {
+		t.Parallel()
+
+		objects := quiesceFixtures(nil)
+		consumer := objects[0].(*appsv1.Deployment)
+		consumer.Annotations = map[string]string{flag.QuiescedAnnotation: `{"source":"Deployment/default/queue","replicas":5,"deadline":"2999-01-01T00:00:00Z"}`}
+		consumer.Spec.Replicas = testutils.Int32Ptr(0)
+		reconciler := createBaseReconciler(append(objects, newUpdatingDeployment("db", sourceAnnotations))...)
+		c := reconciler.KubeClient
+
+		reconcileDeployment(t, reconciler, "db")
+		assert.JSONEq(t,
+			`{"source":"Deployment/default/queue","replicas":5,"deadline":"2999-01-01T00:00:00Z"}`,
+			getDeployment(t, c, "consumer").Annotations[flag.QuiescedAnnotation],
+		)
+
+		// The CronJob is resumed by hand during the update and must not be suspended again.
+		report := getCronJob(t, c, "report")
+		report.Spec.Suspend = ptr.To(false)
+		require.NoError(t, c.Update(t.Context(), report))
+
+		finishRollout(t, c, "db")
+		reconcileDeployment(t, reconciler, "db")
+
+		assert.Equal(t, int32(0), *getDeployment(t, c, "consumer").Spec.Replicas)
+		report = getCronJob(t, c, "report")
+		assert.False(t, *report.Spec.Suspend)
+		assert.NotContains(t, report.Annotations, flag.QuiescedAnnotation)
+	})
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/internal/metrics"
+	"github.com/thurgauerkb/cascader/internal/utils"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// recoveryRetryInterval is the interval between attempts to list workloads with in-flight cascades.
+const recoveryRetryInterval = 5 * time.Second
+
+// CascadeRecovery re-enqueues workloads with an in-flight cascade once the operator started or
+// acquired leadership. Create events of the initial informer list are filtered by the predicates,
+// so without it a cascade interrupted by a restart or failover would never finish.
+type CascadeRecovery struct {
+	KubeClient                    client.Client                          // KubeClient is the Kubernetes API client.
+	Logger                        *logr.Logger                           // Logger is used for logging recovery events.
+	Metrics                       *metrics.Registry                      // Metrics is used for recording metrics.
+	LastObservedRestartAnnotation string                                 // LastObservedRestartAnnotation is the annotation key for last observed restarts.
+	Queues                        map[kinds.Kind]chan event.GenericEvent // Queues enqueue workloads into the reconciler of their kind.
+}
+
+// NeedLeaderElection ensures only the leader resumes cascades.
+func (r *CascadeRecovery) NeedLeaderElection() bool {
+	return true
+}
+
+// Start lists all workloads with a pending cascade and enqueues them into their reconcilers.
+// Failures to list workloads are retried until the context is canceled, since returning an
+// error would stop the manager.
+func (r *CascadeRecovery) Start(ctx context.Context) error {
+	resumed := 0
+	for kind, queue := range r.Queues {
+		var objs []client.Object
+		err := wait.PollUntilContextCancel(ctx, recoveryRetryInterval, true, func(ctx context.Context) (bool, error) {
+			var err error
+			objs, err = r.listPending(ctx, kind)
+			if err != nil {
+				r.Logger.Error(err, "Failed to list workloads with in-flight cascades; retrying", "kind", kind)
+				return false, nil
+			}
+			return true, nil
+		})
+		if err != nil {
+			return nil // Context canceled.
+		}
+
+		for _, obj := range objs {
+			select {
+			case queue <- event.GenericEvent{Object: obj}:
+			case <-ctx.Done():
+				return nil
+			}
+
+			r.Logger.Info("Resuming in-flight cascade", "workloadID", utils.GenerateID(kind, obj.GetNamespace(), obj.GetName()))
+			r.Metrics.IncCascadesResumed(obj.GetNamespace(), obj.GetName(), kind.String())
+			resumed++
+		}
+	}
+
+	r.Logger.Info("Resumed in-flight cascades", "count", resumed)
+	return nil
+}
+
+// listPending lists the workloads of the given kind with an in-flight cascade.
+func (r *CascadeRecovery) listPending(ctx context.Context, kind kinds.Kind) ([]client.Object, error) {
+	list, err := newWorkloadList(kind)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.KubeClient.List(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to list %s workloads: %w", kind, err)
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract %s workloads: %w", kind, err)
+	}
+
+	var pending []client.Object
+	for _, item := range items {
+		if obj, ok := item.(client.Object); ok && r.pending(obj) {
+			pending = append(pending, obj)
+		}
+	}
+	return pending, nil
+}
+
+// pending reports whether a cascade of the workload was detected but did not finish.
+func (r *CascadeRecovery) pending(obj client.Object) bool {
+	return hasAnnotation(obj, r.LastObservedRestartAnnotation) ||
+		hasAnnotation(obj, flag.PendingRetryAnnotation) ||
+		hasAnnotation(obj, flag.PendingEvictionsAnnotation) ||
+		hasAnnotation(obj, flag.PendingVerificationAnnotation) ||
+		hasAnnotation(obj, flag.DeferredTargetsAnnotation)
+}
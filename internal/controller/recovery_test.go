@@ -0,0 +1,104 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	internalmetrics "github.com/thurgauerkb/cascader/internal/metrics"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestCascadeRecovery_Start(t *testing.T) {
+	t.Parallel()
+
+	inFlight := newStableDeployment("in-flight", map[string]string{
+		"cascader.tkb.ch/last-observed-restart": "2026-01-01T00:00:00Z",
+	})
+	retrying := newStableDeployment("retrying", map[string]string{
+		flag.PendingRetryAnnotation: `{"generation":1,"attempt":0,"targets":["Deployment/default/target"]}`,
+	})
+	idle := newStableDeployment("idle", map[string]string{"cascader.tkb.ch/deployment": "target"})
+	sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+		Name:        "database",
+		Namespace:   "default",
+		Annotations: map[string]string{"cascader.tkb.ch/last-observed-restart": "2026-01-01T00:00:00Z"},
+	}}
+
+	deployments := make(chan event.GenericEvent, 5)
+	statefulSets := make(chan event.GenericEvent, 5)
+	daemonSets := make(chan event.GenericEvent, 5)
+
+	recovery := &CascadeRecovery{
+		KubeClient:                    fake.NewClientBuilder().WithObjects(inFlight, retrying, idle, sts).Build(),
+		Logger:                        &logr.Logger{},
+		Metrics:                       internalmetrics.NewRegistry(prometheus.NewRegistry()),
+		LastObservedRestartAnnotation: "cascader.tkb.ch/last-observed-restart",
+		Queues: map[kinds.Kind]chan event.GenericEvent{
+			kinds.DeploymentKind:  deployments,
+			kinds.StatefulSetKind: statefulSets,
+			kinds.DaemonSetKind:   daemonSets,
+		},
+	}
+
+	require.NoError(t, recovery.Start(t.Context()))
+	assert.True(t, recovery.NeedLeaderElection())
+
+	var resumed []string
+	for len(deployments) > 0 {
+		resumed = append(resumed, (<-deployments).Object.GetName())
+	}
+	assert.ElementsMatch(t, []string{"in-flight", "retrying"}, resumed)
+
+	require.Len(t, statefulSets, 1)
+	assert.Equal(t, "database", (<-statefulSets).Object.GetName())
+	assert.Empty(t, daemonSets)
+}
+
+func TestCascadeRecovery_Canceled(t *testing.T) {
+	t.Parallel()
+
+	inFlight := newStableDeployment("in-flight", map[string]string{
+		"cascader.tkb.ch/last-observed-restart": "2026-01-01T00:00:00Z",
+	})
+
+	recovery := &CascadeRecovery{
+		KubeClient:                    fake.NewClientBuilder().WithObjects(inFlight).Build(),
+		Logger:                        &logr.Logger{},
+		Metrics:                       internalmetrics.NewRegistry(prometheus.NewRegistry()),
+		LastObservedRestartAnnotation: "cascader.tkb.ch/last-observed-restart",
+		Queues: map[kinds.Kind]chan event.GenericEvent{
+			kinds.DeploymentKind: make(chan event.GenericEvent), // Never consumed.
+		},
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	assert.NoError(t, recovery.Start(ctx))
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/thurgauerkb/cascader/internal/flag"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// restoreRecreatedAnnotations applies the annotations remembered from the deleted predecessor of a
+// recreated workload, without overriding annotations already present on the workload. The annotations
+// are applied in memory only and are lost once the workload is patched.
+// Returns true if the workload is a recreation of a remembered deletion.
+func (b *BaseReconciler) restoreRecreatedAnnotations(obj client.Object) bool {
+	remembered, ok := b.Recreations.Lookup(client.ObjectKeyFromObject(obj))
+	if !ok {
+		return false
+	}
+
+	// The cascade state of the predecessor must not leak into the recreated workload.
+	delete(remembered, b.LastObservedRestartAnnotation)
+	delete(remembered, flag.PendingRetryAnnotation)
+	delete(remembered, flag.PendingEvictionsAnnotation)
+	delete(remembered, flag.StabilityGatesAnnotation)
+	delete(remembered, flag.PendingVerificationAnnotation)
+	delete(remembered, flag.VerificationHoldAnnotation)
+	delete(remembered, flag.DeferredTargetsAnnotation)
+	delete(remembered, flag.CascadeAnnotation)
+	delete(remembered, flag.CascadeArrivalsAnnotation)
+	delete(remembered, flag.TemplateHashesAnnotation)
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, len(remembered))
+	}
+	for key, val := range remembered {
+		if _, exists := annotations[key]; !exists {
+			annotations[key] = val
+		}
+	}
+	obj.SetAnnotations(annotations)
+
+	return true
+}
+
+// forgetRecreation drops the remembered deletion of the workload once its cascade finished.
+func (b *BaseReconciler) forgetRecreation(obj client.Object) {
+	b.Recreations.Forget(client.ObjectKeyFromObject(obj))
+}
+
+// finishRecreation drops the remembered deletion of the workload once its cascade finished, including
+// the retries, deferred restarts and post-restart checks which need the restored targets.
+func (b *BaseReconciler) finishRecreation(obj client.Object) {
+	for _, key := range []string{
+		b.LastObservedRestartAnnotation,
+		flag.PendingRetryAnnotation,
+		flag.DeferredTargetsAnnotation,
+		flag.PendingVerificationAnnotation,
+	} {
+		if hasAnnotation(obj, key) {
+			return
+		}
+	}
+	b.forgetRecreation(obj)
+}
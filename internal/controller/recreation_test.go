@@ -0,0 +1,160 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/recreation"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestReconcileWorkload_Recreated(t *testing.T) {
+	t.Parallel()
+
+	deleted := newStableDeployment("source", map[string]string{
+		flag.CascadeOnRecreateAnnotation:        "true",
+		"cascader.tkb.ch/deployment":            "target",
+		"cascader.tkb.ch/last-observed-restart": "2026-01-01T00:00:00Z",
+	})
+
+	t.Run("Restarts targets of the deleted workload", func(t *testing.T) {
+		t.Parallel()
+
+		// The recreated workload does not carry any annotations yet.
+		source := newStableDeployment("source", nil)
+		target := newStableDeployment("target", nil)
+
+		reconciler := createBaseReconciler(source, target)
+		reconciler.Recreations = recreation.NewTracker()
+		require.True(t, reconciler.Recreations.Remember(deleted))
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+
+		updatedTarget := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(target), updatedTarget))
+		assert.Contains(t, updatedTarget.Spec.Template.Annotations, flag.LastObservedRestartAnnotation)
+
+		// The recreated workload starts a cascade of its own.
+		updatedSource := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(source), updatedSource))
+		assert.Contains(t, updatedSource.Annotations, flag.CascadeAnnotation)
+
+		// The remembered annotations are not persisted on the recreated workload.
+		assert.NotContains(t, updatedSource.Annotations, "cascader.tkb.ch/deployment")
+		assert.NotContains(t, updatedSource.Annotations, flag.CascadeOnRecreateAnnotation)
+
+		_, ok := reconciler.Recreations.Lookup(client.ObjectKeyFromObject(source))
+		assert.False(t, ok, "Expected the deletion to be forgotten after the cascade")
+	})
+
+	t.Run("Waits until the recreated workload is stable", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", nil)
+		source.Status.ObservedGeneration = 0
+		target := newStableDeployment("target", nil)
+
+		reconciler := createBaseReconciler(source, target)
+		reconciler.Recreations = recreation.NewTracker()
+		require.True(t, reconciler.Recreations.Remember(deleted))
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: defaultRequeuAfter}, result)
+
+		updatedTarget := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(target), updatedTarget))
+		assert.NotContains(t, updatedTarget.Spec.Template.Annotations, flag.LastObservedRestartAnnotation)
+
+		_, ok := reconciler.Recreations.Lookup(client.ObjectKeyFromObject(source))
+		assert.True(t, ok, "Expected the deletion to be remembered until the cascade finished")
+	})
+
+	t.Run("Retries failed targets of the recreated workload", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", nil)
+		target := newStableDeployment("target", nil)
+
+		var failing atomic.Bool
+		failing.Store(true)
+		reconciler := createBaseReconciler()
+		reconciler.KubeClient = fake.NewClientBuilder().
+			WithObjects(source, target).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					if obj.GetName() == "target" && failing.Load() {
+						return errors.New("patch failed")
+					}
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+			}).
+			Build()
+		reconciler.MaxRetries = 3
+		reconciler.Recreations = recreation.NewTracker()
+		require.True(t, reconciler.Recreations.Remember(deleted))
+
+		// The restart of the target fails and is retried with the targets of the deleted workload.
+		_, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		_, ok := reconciler.Recreations.Lookup(client.ObjectKeyFromObject(source))
+		assert.True(t, ok, "Expected the deletion to be remembered until the retry finished")
+
+		failing.Store(false)
+		retried := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(source), retried))
+		require.Contains(t, retried.Annotations, flag.PendingRetryAnnotation)
+		_, err = reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: retried})
+		require.NoError(t, err)
+
+		updatedTarget := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(target), updatedTarget))
+		assert.Contains(t, updatedTarget.Spec.Template.Annotations, flag.LastObservedRestartAnnotation)
+		_, ok = reconciler.Recreations.Lookup(client.ObjectKeyFromObject(source))
+		assert.False(t, ok, "Expected the deletion to be forgotten after the retry")
+	})
+
+	t.Run("Annotations of the recreated workload take precedence", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{"cascader.tkb.ch/deployment": "other"})
+		reconciler := createBaseReconciler()
+		reconciler.Recreations = recreation.NewTracker()
+		require.True(t, reconciler.Recreations.Remember(deleted))
+
+		assert.True(t, reconciler.restoreRecreatedAnnotations(source))
+		assert.Equal(t, map[string]string{
+			flag.CascadeOnRecreateAnnotation: "true",
+			"cascader.tkb.ch/deployment":     "other",
+		}, source.Annotations)
+	})
+}
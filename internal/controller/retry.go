@@ -0,0 +1,212 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/utils"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// retryState tracks the targets of a source that failed to restart and are pending a retry.
+// It is persisted as JSON in an annotation on the source so that targets which already
+// restarted successfully are not restarted again.
+type retryState struct {
+	Generation int64    `json:"generation"` // Generation of the source the cascade belongs to.
+	Attempt    int      `json:"attempt"`    // Number of retries performed so far.
+	Targets    []string `json:"targets"`    // IDs of the targets pending a retry.
+
+	// Re-runs of Job targets which were deleted but not created again, keyed by target ID.
+	Reruns map[string]*batchv1.Job `json:"reruns,omitempty"`
+}
+
+// loadRetryState reads the retry state from the source annotations.
+// Returns nil if no retry is pending.
+func loadRetryState(obj client.Object) (*retryState, error) {
+	val, ok := obj.GetAnnotations()[flag.PendingRetryAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	state := &retryState{}
+	if err := json.Unmarshal([]byte(val), state); err != nil {
+		return nil, fmt.Errorf("invalid annotation %q: %w", flag.PendingRetryAnnotation, err)
+	}
+	return state, nil
+}
+
+// saveRetryState persists the retry state on the source workload.
+func (b *BaseReconciler) saveRetryState(ctx context.Context, workload workloads.Workload, state *retryState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to serialize retry state: %w", err)
+	}
+	return utils.PatchWorkloadAnnotation(ctx, b.KubeClient, workload.Resource(), flag.PendingRetryAnnotation, string(data))
+}
+
+// clearRetryState removes the retry state from the source workload, if present.
+func (b *BaseReconciler) clearRetryState(ctx context.Context, workload workloads.Workload) error {
+	if !hasAnnotation(workload.Resource(), flag.PendingRetryAnnotation) {
+		return nil
+	}
+	return utils.DeleteWorkloadAnnotation(ctx, b.KubeClient, workload.Resource(), flag.PendingRetryAnnotation)
+}
+
+// retryBackoff returns the exponential backoff for the given attempt, capped at RetryBackoffMax.
+func (b *BaseReconciler) retryBackoff(attempt int) time.Duration {
+	backoff := b.RetryBackoff
+	for range attempt {
+		backoff *= 2
+		if backoff >= b.RetryBackoffMax {
+			return b.RetryBackoffMax
+		}
+	}
+	return min(backoff, b.RetryBackoffMax)
+}
+
+// scheduleRetry persists the failed targets of a cascade and requeues the source after the backoff.
+// If retries are disabled or exhausted, the failed targets are given up on.
+func (b *BaseReconciler) scheduleRetry(
+	ctx context.Context,
+	workload workloads.Workload,
+	attempt int,
+	failed []targets.Target,
+) (ctrl.Result, error) {
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	if attempt >= b.MaxRetries {
+		if b.MaxRetries > 0 {
+			b.Recorder.Eventf(
+				workload.Resource(),
+				nil,
+				corev1.EventTypeWarning,
+				"RetriesExhausted",
+				"RetryReload",
+				"Cascader gave up restarting %v after %d retries",
+				targetIDs(failed),
+				attempt,
+			)
+			log.Error(errors.New("retries exhausted"), "Giving up on failed targets", "targets", targetIDs(failed), "attempts", attempt)
+		}
+		if err := b.clearRetryState(ctx, workload); err != nil {
+			log.Error(err, "Failed to delete retry annotation")
+		}
+		// Verify the targets which restarted during the previous attempts and restart deferred targets.
+		return requeuePending(workload.Resource(), ctrl.Result{}, b.RequeueAfterDefault), nil
+	}
+
+	state := &retryState{
+		Generation: workload.Resource().GetGeneration(),
+		Attempt:    attempt,
+		Targets:    targetIDs(failed),
+	}
+	for _, t := range failed {
+		if job, ok := t.(*targets.JobTarget); ok && job.PendingRerun() != nil {
+			if state.Reruns == nil {
+				state.Reruns = make(map[string]*batchv1.Job)
+			}
+			state.Reruns[t.ID()] = job.PendingRerun()
+		}
+	}
+	if err := b.saveRetryState(ctx, workload, state); err != nil {
+		// Without a persisted state the retry would restart all targets again, so do not requeue.
+		log.Error(err, "Failed to persist retry state; failed targets will not be retried")
+		return ctrl.Result{}, nil
+	}
+
+	backoff := b.retryBackoff(attempt)
+	log.Info(fmt.Sprintf("Retrying failed targets after %s.", backoff), "targets", state.Targets, "attempt", attempt+1)
+	return ctrl.Result{RequeueAfter: backoff}, nil
+}
+
+// retryTargets restarts the targets pending from a previous cascade of the source.
+func (b *BaseReconciler) retryTargets(
+	ctx context.Context,
+	workload workloads.Workload,
+	graph *dependencyGraph,
+	state *retryState,
+) (ctrl.Result, error) {
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	all, err := b.extractTargets(ctx, workload.Resource())
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to create targets: %w", err)
+	}
+
+	// Only retry targets which are still referenced by the source.
+	pending := make([]targets.Target, 0, len(state.Targets))
+	for _, t := range all {
+		if slices.Contains(state.Targets, t.ID()) {
+			// Deleted Jobs are created again from the spec persisted by the previous attempt.
+			if job, ok := t.(*targets.JobTarget); ok && state.Reruns[t.ID()] != nil {
+				job.ResumeRerun(state.Reruns[t.ID()])
+			}
+			pending = append(pending, t)
+		}
+	}
+
+	// Targets which started rolling out in the meantime are restarted once their rollout finished.
+	// Delays of the targets already passed when they failed to restart.
+	ready, rolling, joining, held := b.preflightTargets(ctx, workload, graph, pending, nil, time.Time{})
+	if waiting := slices.Concat(rolling, joining, held); len(waiting) > 0 {
+		b.scheduleDeferral(ctx, workload, waiting)
+	}
+
+	// Only targets which are restarted again count as retried, not those deferred by the preflight.
+	for _, t := range ready {
+		b.Metrics.IncRestartRetries(t.Namespace(), t.Name(), t.Kind().String())
+	}
+	succ, failed := b.triggerReloads(ctx, workload, ready)
+	if len(failed) > 0 {
+		log.Error(errors.New("partial target reload failure"), "Some targets failed to reload", "succeeded", succ, "failed", len(failed))
+		_, _ = b.scheduleVerification(ctx, workload, restartedTargets(ready, failed), b.RequeueAfterDefault)
+		return b.scheduleRetry(ctx, workload, state.Attempt+1, failed)
+	}
+
+	if err := b.clearRetryState(ctx, workload); err != nil {
+		log.Error(err, "Failed to delete retry annotation")
+	}
+	log.Info("Finished handling targets", "succeeded", succ, "failed", 0)
+
+	result, err := b.scheduleVerification(ctx, workload, ready, b.RequeueAfterDefault)
+	return requeuePending(workload.Resource(), result, b.RequeueAfterDefault), err
+}
+
+// createPendingReruns creates the deleted Jobs of a retry state which is discarded, since their spec
+// is lost otherwise. Jobs which cannot be created yet are given up on.
+func (b *BaseReconciler) createPendingReruns(ctx context.Context, workload workloads.Workload, state *retryState) {
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	for id, job := range state.Reruns {
+		if err := b.KubeClient.Create(ctx, job.DeepCopy()); err != nil {
+			log.Error(err, "Failed to re-run deleted Job", "targetID", id)
+		}
+	}
+}
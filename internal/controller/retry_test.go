@@ -0,0 +1,299 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	internalmetrics "github.com/thurgauerkb/cascader/internal/metrics"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+	"github.com/thurgauerkb/cascader/test/testutils"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// newStableDeployment returns a Deployment reporting a stable rollout.
+func newStableDeployment(name string, annotations map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Generation:  1,
+			Annotations: annotations,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: testutils.Int32Ptr(1),
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 1,
+			Replicas:           1,
+			ReadyReplicas:      1,
+			UpdatedReplicas:    1,
+			AvailableReplicas:  1,
+		},
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	t.Parallel()
+
+	reconciler := createBaseReconciler()
+	reconciler.RetryBackoff = 5 * time.Second
+	reconciler.RetryBackoffMax = time.Minute
+
+	assert.Equal(t, 5*time.Second, reconciler.retryBackoff(0))
+	assert.Equal(t, 10*time.Second, reconciler.retryBackoff(1))
+	assert.Equal(t, 20*time.Second, reconciler.retryBackoff(2))
+	assert.Equal(t, 40*time.Second, reconciler.retryBackoff(3))
+	assert.Equal(t, time.Minute, reconciler.retryBackoff(4))
+	assert.Equal(t, time.Minute, reconciler.retryBackoff(100))
+}
+
+func TestLoadRetryState(t *testing.T) {
+	t.Parallel()
+
+	t.Run("No retry pending", func(t *testing.T) {
+		t.Parallel()
+
+		state, err := loadRetryState(newStableDeployment("source", nil))
+		require.NoError(t, err)
+		assert.Nil(t, state)
+	})
+
+	t.Run("Invalid retry state", func(t *testing.T) {
+		t.Parallel()
+
+		obj := newStableDeployment("source", map[string]string{flag.PendingRetryAnnotation: "{"})
+
+		_, err := loadRetryState(obj)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "invalid annotation \"cascader.tkb.ch/pending-retry\"")
+	})
+
+	t.Run("Valid retry state", func(t *testing.T) {
+		t.Parallel()
+
+		obj := newStableDeployment("source", map[string]string{
+			flag.PendingRetryAnnotation: `{"generation":3,"attempt":1,"targets":["Deployment/default/a"]}`,
+		})
+
+		state, err := loadRetryState(obj)
+		require.NoError(t, err)
+		assert.Equal(t, &retryState{Generation: 3, Attempt: 1, Targets: []string{"Deployment/default/a"}}, state)
+	})
+}
+
+func TestReconcileWorkload_Retry(t *testing.T) {
+	t.Parallel()
+
+	const targetsAnnotation = "cascader.tkb.ch/deployment"
+
+	t.Run("Failed targets are persisted and requeued", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{targetsAnnotation: "ok, broken"})
+		okTarget := newStableDeployment("ok", nil)
+		brokenTarget := newStableDeployment("broken", nil)
+
+		reconciler := createBaseReconciler(source, okTarget, brokenTarget)
+		reconciler.KubeClient = &testutils.MockClientWithError{
+			Client:        reconciler.KubeClient,
+			PatchErrorFor: testutils.NamedError{Name: "broken", Namespace: "default"},
+		}
+		reconciler.MaxRetries = 3
+		reconciler.RetryBackoff = 5 * time.Second
+		reconciler.RetryBackoffMax = time.Minute
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: 5 * time.Second}, result)
+
+		updated := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(source), updated))
+
+		state, err := loadRetryState(updated)
+		require.NoError(t, err)
+		assert.Equal(t, &retryState{Generation: 1, Attempt: 0, Targets: []string{"Deployment/default/broken"}}, state)
+	})
+
+	t.Run("Only pending targets are retried", func(t *testing.T) {
+		t.Parallel()
+
+		data, _ := json.Marshal(retryState{Generation: 1, Attempt: 0, Targets: []string{"Deployment/default/broken"}})
+		source := newStableDeployment("source", map[string]string{
+			targetsAnnotation:           "ok, broken",
+			flag.PendingRetryAnnotation: string(data),
+		})
+		okTarget := newStableDeployment("ok", nil)
+		brokenTarget := newStableDeployment("broken", nil)
+
+		reconciler := createBaseReconciler(source, okTarget, brokenTarget)
+		reconciler.MaxRetries = 3
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+
+		restarted := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(brokenTarget), restarted))
+		assert.Contains(t, restarted.Spec.Template.Annotations, flag.LastObservedRestartAnnotation)
+
+		untouched := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(okTarget), untouched))
+		assert.NotContains(t, untouched.Spec.Template.Annotations, flag.LastObservedRestartAnnotation)
+
+		updated := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(source), updated))
+		assert.NotContains(t, updated.Annotations, flag.PendingRetryAnnotation)
+		assert.NotContains(t, updated.Annotations, reconciler.LastObservedRestartAnnotation)
+	})
+
+	t.Run("Deleted Jobs are re-run from the persisted spec", func(t *testing.T) {
+		t.Parallel()
+
+		rerun := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "warm", Namespace: "default"},
+			Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				RestartPolicy: corev1.RestartPolicyNever,
+				Containers:    []corev1.Container{{Name: "warm", Image: "warm:1.0"}},
+			}}},
+		}
+		data, _ := json.Marshal(retryState{
+			Generation: 1,
+			Targets:    []string{"Job/default/warm"},
+			Reruns:     map[string]*batchv1.Job{"Job/default/warm": rerun},
+		})
+		source := newStableDeployment("source", map[string]string{
+			"cascader.tkb.ch/job":       "warm",
+			flag.PendingRetryAnnotation: string(data),
+		})
+		reconciler := createBaseReconciler(source)
+		reconciler.AnnotationKindMap["cascader.tkb.ch/job"] = kinds.JobKind
+		reconciler.MaxRetries = 3
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+
+		job := &batchv1.Job{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(rerun), job))
+		assert.Equal(t, "warm:1.0", job.Spec.Template.Spec.Containers[0].Image)
+
+		updated := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(source), updated))
+		assert.NotContains(t, updated.Annotations, flag.PendingRetryAnnotation)
+	})
+
+	t.Run("Deferred targets are not counted as retried", func(t *testing.T) {
+		t.Parallel()
+
+		data, _ := json.Marshal(retryState{Generation: 1, Attempt: 0, Targets: []string{"Deployment/default/broken", "Deployment/default/rolling"}})
+		source := newStableDeployment("source", map[string]string{
+			targetsAnnotation:           "broken, rolling",
+			flag.PendingRetryAnnotation: string(data),
+		})
+		reconciler := createBaseReconciler(source, newStableDeployment("broken", nil), newRollingDeployment("rolling"))
+		reconciler.MaxRetries = 3
+		reg := prometheus.NewRegistry()
+		reconciler.Metrics = internalmetrics.NewRegistry(reg)
+
+		_, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+
+		expected := `
+# HELP cascader_restart_retries_total Total number of retried restarts of targets that previously failed to restart.
+# TYPE cascader_restart_retries_total counter
+cascader_restart_retries_total{name="broken",namespace="default",resource_kind="Deployment"} 1
+`
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "cascader_restart_retries_total"))
+	})
+
+	t.Run("Retries exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		data, _ := json.Marshal(retryState{Generation: 1, Attempt: 2, Targets: []string{"Deployment/default/broken"}})
+		source := newStableDeployment("source", map[string]string{
+			targetsAnnotation:           "broken",
+			flag.PendingRetryAnnotation: string(data),
+		})
+		brokenTarget := newStableDeployment("broken", nil)
+
+		reconciler := createBaseReconciler(source, brokenTarget)
+		reconciler.KubeClient = &testutils.MockClientWithError{
+			Client:        reconciler.KubeClient,
+			PatchErrorFor: testutils.NamedError{Name: "broken", Namespace: "default"},
+		}
+		reconciler.MaxRetries = 3
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+
+		require.Len(t, recorder.Events, 2)
+		assert.Contains(t, <-recorder.Events, "ReloadFailed")
+		assert.Contains(t, <-recorder.Events, "RetriesExhausted")
+
+		updated := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(source), updated))
+		assert.NotContains(t, updated.Annotations, flag.PendingRetryAnnotation)
+	})
+
+	t.Run("Pending retry superseded by new restart", func(t *testing.T) {
+		t.Parallel()
+
+		data, _ := json.Marshal(retryState{Generation: 1, Attempt: 0, Targets: []string{"Deployment/default/broken"}})
+		source := newStableDeployment("source", map[string]string{
+			targetsAnnotation:           "ok",
+			flag.PendingRetryAnnotation: string(data),
+		})
+		source.Generation = 2
+		source.Status.ObservedGeneration = 2
+		okTarget := newStableDeployment("ok", nil)
+
+		reconciler := createBaseReconciler(source, okTarget)
+		reconciler.MaxRetries = 3
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+
+		restarted := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(okTarget), restarted))
+		assert.Contains(t, restarted.Spec.Template.Annotations, flag.LastObservedRestartAnnotation)
+
+		updated := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(source), updated))
+		assert.NotContains(t, updated.Annotations, flag.PendingRetryAnnotation)
+	})
+}
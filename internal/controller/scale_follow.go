@@ -0,0 +1,322 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch
+
+// ScaleFollowHPAMode defines how targets managed by a HorizontalPodAutoscaler follow the scale of their source.
+type ScaleFollowHPAMode string
+
+const (
+	// ScaleFollowHPAPause scales the target to zero, which pauses its HorizontalPodAutoscaler until the replicas are restored.
+	ScaleFollowHPAPause ScaleFollowHPAMode = "pause"
+
+	// ScaleFollowHPASkip leaves the target to its HorizontalPodAutoscaler.
+	ScaleFollowHPASkip ScaleFollowHPAMode = "skip"
+)
+
+// Actions of targets following the scale of their source, as reported by the scale-follow metric.
+const (
+	scaleFollowDown    = "scale-down"
+	scaleFollowRestore = "restore"
+	scaleFollowSkip    = "skip"
+)
+
+// ParseScaleFollowHPAMode parses a scale-follow mode for targets managed by a HorizontalPodAutoscaler.
+// The value is case-insensitive.
+func ParseScaleFollowHPAMode(value string) (ScaleFollowHPAMode, error) {
+	switch mode := ScaleFollowHPAMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case ScaleFollowHPAPause, ScaleFollowHPASkip:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unsupported scale-follow HPA mode: %q", value)
+	}
+}
+
+// scaleFollowState is persisted as JSON in an annotation on a target scaled to zero with its source,
+// so that its replicas are restored once the source is scaled up again.
+type scaleFollowState struct {
+	Source   string `json:"source"`   // ID of the source the target followed.
+	Replicas int32  `json:"replicas"` // Replicas of the target before it was scaled to zero.
+}
+
+// loadScaleFollowState reads the scale-follow state from the target annotations.
+// Returns nil if the target was not scaled to zero by a source.
+func loadScaleFollowState(obj client.Object) (*scaleFollowState, error) {
+	val, ok := obj.GetAnnotations()[flag.ScaleFollowAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	state := &scaleFollowState{}
+	if err := json.Unmarshal([]byte(val), state); err != nil {
+		return nil, fmt.Errorf("invalid annotation %q: %w", flag.ScaleFollowAnnotation, err)
+	}
+	return state, nil
+}
+
+// scaleFollowers returns the targets whose edge follows the scale of the source.
+func scaleFollowers(source client.Object, targetList []targets.Target) []targets.Target {
+	specs := edgeSpecs(source)
+
+	var followers []targets.Target
+	for _, t := range targetList {
+		if specs.lookup(t).ScaleFollow {
+			followers = append(followers, t)
+		}
+	}
+	return followers
+}
+
+// specReplicas returns the desired replicas of a scalable workload.
+func specReplicas(obj client.Object) (int32, bool) {
+	var replicas *int32
+	switch res := obj.(type) {
+	case *appsv1.Deployment:
+		replicas = res.Spec.Replicas
+	case *appsv1.StatefulSet:
+		replicas = res.Spec.Replicas
+	default:
+		return 0, false
+	}
+	if replicas == nil {
+		return 1, true // The API server defaults unset replicas to one.
+	}
+	return *replicas, true
+}
+
+// followScaleDown scales the followers of a source scaled to zero to zero as well, remembering their replicas.
+// Followers which are already scaled to zero or follow another source are left untouched.
+func (b *BaseReconciler) followScaleDown(ctx context.Context, workload workloads.Workload, followers []targets.Target) {
+	res := workload.Resource()
+	if len(followers) == 0 || !scaledToZero(res) {
+		return
+	}
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	for _, t := range followers {
+		obj := t.Resource()
+		if err := b.KubeClient.Get(ctx, client.ObjectKey{Namespace: t.Namespace(), Name: t.Name()}, obj); err != nil {
+			if !kerrors.IsNotFound(err) {
+				log.Error(err, "Failed to fetch target following the scale of the source", "targetID", t.ID())
+			}
+			continue
+		}
+		if hasAnnotation(obj, flag.ScaleFollowAnnotation) {
+			continue
+		}
+		replicas, ok := specReplicas(obj)
+		if !ok || replicas == 0 {
+			continue
+		}
+
+		hpa, err := b.autoscalerOf(ctx, t)
+		if err != nil {
+			log.Error(err, "Failed to look up HorizontalPodAutoscaler of target", "targetID", t.ID())
+			continue
+		}
+		if hpa != "" && b.scaleFollowHPAMode(obj) == ScaleFollowHPASkip {
+			log.Info("Skipping scale-down of target managed by a HorizontalPodAutoscaler", "targetID", t.ID(), "hpa", hpa)
+			b.Metrics.IncScaleFollow(t.Namespace(), t.Name(), t.Kind().String(), scaleFollowSkip)
+			b.Recorder.Eventf(
+				res,
+				nil,
+				corev1.EventTypeNormal,
+				"ScaleFollowSkipped",
+				"FollowScale",
+				"Cascader skipped scaling %s to zero: managed by HorizontalPodAutoscaler %s",
+				t.ID(),
+				hpa,
+			)
+			continue
+		}
+
+		state := &scaleFollowState{Source: workload.ID(), Replicas: replicas}
+		if err := b.scaleTarget(ctx, obj, 0, state); err != nil {
+			log.Error(err, "Failed to scale target to zero", "targetID", t.ID())
+			b.Recorder.Eventf(res, nil, corev1.EventTypeWarning, "ScaleFollowFailed", "FollowScale", "Cascader failed to scale %s to zero: %v", t.ID(), err)
+			continue
+		}
+		log.Info("Scaled target to zero following the source", "targetID", t.ID(), "replicas", replicas)
+		b.Metrics.IncScaleFollow(t.Namespace(), t.Name(), t.Kind().String(), scaleFollowDown)
+		b.Recorder.Eventf(
+			res,
+			nil,
+			corev1.EventTypeNormal,
+			"ScaledToZero",
+			"FollowScale",
+			"Cascader scaled %s to zero, remembering %d replicas",
+			t.ID(),
+			replicas,
+		)
+	}
+}
+
+// restoreScale restores the replicas of the followers the source scaled to zero, once the source is
+// scaled up again and stable. Returns the restored targets, which need no restart.
+func (b *BaseReconciler) restoreScale(ctx context.Context, workload workloads.Workload, followers []targets.Target) []targets.Target {
+	res := workload.Resource()
+	if len(followers) == 0 || scaledToZero(res) {
+		return nil
+	}
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	var restored []targets.Target
+	for _, t := range followers {
+		obj := t.Resource()
+		if err := b.KubeClient.Get(ctx, client.ObjectKey{Namespace: t.Namespace(), Name: t.Name()}, obj); err != nil {
+			if !kerrors.IsNotFound(err) {
+				log.Error(err, "Failed to fetch target following the scale of the source", "targetID", t.ID())
+			}
+			continue
+		}
+		state, err := loadScaleFollowState(obj)
+		if err != nil {
+			log.Error(err, "Ignoring invalid scale-follow state", "targetID", t.ID())
+			continue
+		}
+		if state == nil || state.Source != workload.ID() {
+			continue
+		}
+
+		// Targets scaled up in the meantime keep their replicas.
+		replicas, _ := specReplicas(obj)
+		if replicas == 0 {
+			replicas = state.Replicas
+		}
+		if err := b.scaleTarget(ctx, obj, replicas, nil); err != nil {
+			log.Error(err, "Failed to restore replicas of target", "targetID", t.ID())
+			b.Recorder.Eventf(res, nil, corev1.EventTypeWarning, "ScaleFollowFailed", "FollowScale", "Cascader failed to restore %s: %v", t.ID(), err)
+			continue
+		}
+		log.Info("Restored replicas of target following the source", "targetID", t.ID(), "replicas", replicas)
+		b.Metrics.IncScaleFollow(t.Namespace(), t.Name(), t.Kind().String(), scaleFollowRestore)
+		b.Recorder.Eventf(
+			res,
+			nil,
+			corev1.EventTypeNormal,
+			"ScaleRestored",
+			"FollowScale",
+			"Cascader restored %s to %d replicas",
+			t.ID(),
+			replicas,
+		)
+		restored = append(restored, t)
+	}
+	return restored
+}
+
+// scaleTarget patches the replicas of the target along with its scale-follow state. A nil state removes it.
+func (b *BaseReconciler) scaleTarget(ctx context.Context, obj client.Object, replicas int32, state *scaleFollowState) error {
+	if state == nil {
+		return b.patchTargetState(ctx, obj, flag.ScaleFollowAnnotation, nil, func() error { return setReplicas(obj, replicas) })
+	}
+	return b.patchTargetState(ctx, obj, flag.ScaleFollowAnnotation, state, func() error { return setReplicas(obj, replicas) })
+}
+
+// setReplicas sets the desired replicas of a scalable workload.
+func setReplicas(obj client.Object, replicas int32) error {
+	switch res := obj.(type) {
+	case *appsv1.Deployment:
+		res.Spec.Replicas = &replicas
+	case *appsv1.StatefulSet:
+		res.Spec.Replicas = &replicas
+	default:
+		return fmt.Errorf("cannot scale %T", obj)
+	}
+	return nil
+}
+
+// patchTargetState applies change to the target and stores state as JSON in the annotation key, in a single patch.
+// A nil state removes the annotation.
+func (b *BaseReconciler) patchTargetState(ctx context.Context, obj client.Object, key string, state any, change func() error) error {
+	original := obj.DeepCopyObject().(client.Object)
+
+	if err := change(); err != nil {
+		return err
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if state == nil {
+		delete(annotations, key)
+	} else {
+		data, err := json.Marshal(state)
+		if err != nil {
+			return fmt.Errorf("failed to serialize annotation %q: %w", key, err)
+		}
+		annotations[key] = string(data)
+	}
+	obj.SetAnnotations(annotations)
+
+	return b.KubeClient.Patch(ctx, obj, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+}
+
+// autoscalerOf returns the name of the HorizontalPodAutoscaler scaling the target, or an empty string.
+func (b *BaseReconciler) autoscalerOf(ctx context.Context, t targets.Target) (string, error) {
+	hpas := &autoscalingv2.HorizontalPodAutoscalerList{}
+	if err := b.KubeClient.List(ctx, hpas, client.InNamespace(t.Namespace())); err != nil {
+		return "", fmt.Errorf("failed to list HorizontalPodAutoscalers: %w", err)
+	}
+	for _, hpa := range hpas.Items {
+		ref := hpa.Spec.ScaleTargetRef
+		if ref.Kind == t.Kind().String() && ref.Name == t.Name() {
+			return hpa.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// scaleFollowHPAMode returns the scale-follow mode for the target if it is managed by a HorizontalPodAutoscaler.
+// The annotation on the target overrides the default; an invalid annotation falls back to the default.
+func (b *BaseReconciler) scaleFollowHPAMode(obj client.Object) ScaleFollowHPAMode {
+	mode := b.ScaleFollowHPA
+	if mode == "" {
+		mode = ScaleFollowHPASkip
+	}
+
+	val := strings.TrimSpace(obj.GetAnnotations()[flag.ScaleFollowHPAAnnotation])
+	if val == "" {
+		return mode
+	}
+
+	m, err := ParseScaleFollowHPAMode(val)
+	if err != nil {
+		b.Logger.Error(err, "Invalid scale-follow HPA annotation, using default", "annotation", flag.ScaleFollowHPAAnnotation, "mode", mode)
+		return mode
+	}
+	return m
+}
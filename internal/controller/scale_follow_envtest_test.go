@@ -0,0 +1,139 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"os"
+	"testing"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	internalmetrics "github.com/thurgauerkb/cascader/internal/metrics"
+	"github.com/thurgauerkb/cascader/test/testutils"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+// startEnvtest starts a Kubernetes API server for the test and returns a client for it.
+// The test is skipped if the envtest binaries are not available, see "make test".
+func startEnvtest(t *testing.T) client.Client {
+	t.Helper()
+
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set, skipping envtest")
+	}
+
+	env := &envtest.Environment{}
+	cfg, err := env.Start()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, env.Stop()) })
+
+	c, err := client.New(cfg, client.Options{Scheme: scheme.Scheme})
+	require.NoError(t, err)
+	return c
+}
+
+// newEnvtestDeployment returns a Deployment the API server accepts, as the fake client does not validate objects.
+func newEnvtestDeployment(name string, replicas int32, annotations map[string]string) *appsv1.Deployment {
+	labels := map[string]string{"app": name}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: testutils.Int32Ptr(replicas),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "nginx"}}},
+			},
+		},
+	}
+}
+
+func TestScaleFollow_Envtest(t *testing.T) {
+	c := startEnvtest(t)
+
+	followTargets := `version: v1
+targets:
+  - kind: Deployment
+    name: worker
+    scaleFollow: true
+  - kind: Deployment
+    name: autoscaled
+    scaleFollow: true
+`
+	for _, obj := range []client.Object{
+		newEnvtestDeployment("source", 2, map[string]string{flag.TargetsAnnotation: followTargets}),
+		newEnvtestDeployment("worker", 3, nil),
+		newEnvtestDeployment("autoscaled", 2, nil),
+		newHPA("autoscaled", "autoscaled"),
+	} {
+		require.NoError(t, c.Create(t.Context(), obj))
+	}
+	// There is no Deployment controller in envtest, so the status is reported by the test.
+	for _, name := range []string{"source", "worker", "autoscaled"} {
+		scaleTo(t, c, name, *getDeployment(t, c, name).Spec.Replicas)
+	}
+
+	reconciler := &BaseReconciler{
+		Logger:                        &logr.Logger{},
+		KubeClient:                    c,
+		Recorder:                      events.NewFakeRecorder(10),
+		Metrics:                       internalmetrics.NewRegistry(prometheus.NewRegistry()),
+		LastObservedRestartAnnotation: flag.LastObservedRestartAnnotation,
+		RequeueAfterDefault:           defaultRequeuAfter,
+		AnnotationKindMap:             kinds.AnnotationKindMap{"cascader.tkb.ch/deployment": kinds.DeploymentKind},
+		ScaleFollowHPA:                ScaleFollowHPASkip,
+	}
+
+	// Scaling the source to zero scales the worker down, the target managed by an HPA is left alone.
+	scaleTo(t, c, "source", 0)
+	reconcileDeployment(t, reconciler, "source")
+
+	worker := getDeployment(t, c, "worker")
+	assert.Equal(t, int32(0), *worker.Spec.Replicas)
+	assert.JSONEq(t, `{"source":"Deployment/default/source","replicas":3}`, worker.Annotations[flag.ScaleFollowAnnotation])
+	assert.False(t, restarted(t, c, worker))
+	autoscaled := getDeployment(t, c, "autoscaled")
+	assert.Equal(t, int32(2), *autoscaled.Spec.Replicas)
+	assert.NotContains(t, autoscaled.Annotations, flag.ScaleFollowAnnotation)
+
+	// The source is not stable yet, so the worker stays scaled down.
+	dep := getDeployment(t, c, "source")
+	dep.Spec.Replicas = testutils.Int32Ptr(2)
+	require.NoError(t, c.Update(t.Context(), dep))
+	reconcileDeployment(t, reconciler, "source")
+	assert.Equal(t, int32(0), *getDeployment(t, c, "worker").Spec.Replicas)
+
+	// Once the source is stable, the worker is restored without being restarted.
+	scaleTo(t, c, "source", 2)
+	reconcileDeployment(t, reconciler, "source")
+
+	worker = getDeployment(t, c, "worker")
+	assert.Equal(t, int32(3), *worker.Spec.Replicas)
+	assert.NotContains(t, worker.Annotations, flag.ScaleFollowAnnotation)
+	assert.False(t, restarted(t, c, worker))
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+	"github.com/thurgauerkb/cascader/test/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const scaleFollowTargets = `version: v1
+targets:
+  - kind: Deployment
+    name: worker
+    scaleFollow: true
+  - kind: Deployment
+    name: other
+`
+
+// scaleTo simulates scaling a Deployment, including the status the Deployment controller reports once done.
+func scaleTo(t *testing.T, c client.Client, name string, replicas int32) {
+	t.Helper()
+
+	dep := &appsv1.Deployment{}
+	require.NoError(t, c.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: name}, dep))
+	dep.Spec.Replicas = &replicas
+	require.NoError(t, c.Update(t.Context(), dep))
+	dep.Status.ObservedGeneration = dep.Generation
+	dep.Status.Replicas = replicas
+	dep.Status.ReadyReplicas = replicas
+	dep.Status.UpdatedReplicas = replicas
+	dep.Status.AvailableReplicas = replicas
+	require.NoError(t, c.Status().Update(t.Context(), dep))
+}
+
+// getDeployment returns the current state of a Deployment.
+func getDeployment(t *testing.T, c client.Client, name string) *appsv1.Deployment {
+	t.Helper()
+
+	dep := &appsv1.Deployment{}
+	require.NoError(t, c.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: name}, dep))
+	return dep
+}
+
+func newHPA(name, target string) *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: target},
+			MaxReplicas:    5,
+		},
+	}
+}
+
+func TestParseScaleFollowHPAMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value    string
+		expected ScaleFollowHPAMode
+		wantErr  bool
+	}{
+		{value: "pause", expected: ScaleFollowHPAPause},
+		{value: " Skip ", expected: ScaleFollowHPASkip},
+		{value: "scale", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			mode, err := ParseScaleFollowHPAMode(tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.EqualError(t, err, "unsupported scale-follow HPA mode: \""+tt.value+"\"")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, mode)
+		})
+	}
+}
+
+func TestScaleFollowHPAModeFor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		defaultVal ScaleFollowHPAMode
+		annotation string
+		expected   ScaleFollowHPAMode
+	}{
+		{name: "Unset default", expected: ScaleFollowHPASkip},
+		{name: "Default", defaultVal: ScaleFollowHPAPause, expected: ScaleFollowHPAPause},
+		{name: "Annotation overrides default", defaultVal: ScaleFollowHPASkip, annotation: "pause", expected: ScaleFollowHPAPause},
+		{name: "Invalid annotation falls back to default", defaultVal: ScaleFollowHPAPause, annotation: "never", expected: ScaleFollowHPAPause},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reconciler := createBaseReconciler()
+			reconciler.ScaleFollowHPA = tt.defaultVal
+
+			var ann map[string]string
+			if tt.annotation != "" {
+				ann = map[string]string{flag.ScaleFollowHPAAnnotation: tt.annotation}
+			}
+			assert.Equal(t, tt.expected, reconciler.scaleFollowHPAMode(newStableDeployment("worker", ann)))
+		})
+	}
+}
+
+func TestScaleFollowers(t *testing.T) {
+	t.Parallel()
+
+	source := newStableDeployment("source", map[string]string{flag.TargetsAnnotation: scaleFollowTargets})
+	all := []targets.Target{
+		targets.NewDeployment("default", "worker", nil),
+		targets.NewDeployment("default", "other", nil),
+	}
+
+	followers := scaleFollowers(source, all)
+	assert.Equal(t, []string{"Deployment/default/worker"}, targetIDs(followers))
+}
+
+func TestScaleFollow(t *testing.T) {
+	t.Parallel()
+
+	newObjects := func() []client.Object {
+		worker := newStableDeployment("worker", nil)
+		worker.Spec.Replicas = testutils.Int32Ptr(3)
+		return []client.Object{
+			newStableDeployment("source", map[string]string{flag.TargetsAnnotation: scaleFollowTargets}),
+			worker,
+			newStableDeployment("other", nil),
+		}
+	}
+
+	t.Run("Scale down and restore", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler(newObjects()...)
+		c := reconciler.KubeClient
+
+		scaleTo(t, c, "source", 0)
+		reconcileDeployment(t, reconciler, "source")
+
+		worker := getDeployment(t, c, "worker")
+		assert.Equal(t, int32(0), *worker.Spec.Replicas)
+		assert.JSONEq(t, `{"source":"Deployment/default/source","replicas":3}`, worker.Annotations[flag.ScaleFollowAnnotation])
+		assert.False(t, restarted(t, c, worker), "scale-follow target must not be restarted")
+		assert.True(t, restarted(t, c, getDeployment(t, c, "other")))
+		resetRestart(t, c, "other")
+
+		scaleTo(t, c, "source", 2)
+		reconcileDeployment(t, reconciler, "source")
+
+		worker = getDeployment(t, c, "worker")
+		assert.Equal(t, int32(3), *worker.Spec.Replicas)
+		assert.NotContains(t, worker.Annotations, flag.ScaleFollowAnnotation)
+		assert.False(t, restarted(t, c, worker), "restored target must not be restarted")
+		assert.True(t, restarted(t, c, getDeployment(t, c, "other")))
+	})
+
+	t.Run("Target scaled up meanwhile keeps its replicas", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler(newObjects()...)
+		c := reconciler.KubeClient
+
+		scaleTo(t, c, "source", 0)
+		reconcileDeployment(t, reconciler, "source")
+		scaleTo(t, c, "worker", 1)
+
+		scaleTo(t, c, "source", 2)
+		reconcileDeployment(t, reconciler, "source")
+
+		worker := getDeployment(t, c, "worker")
+		assert.Equal(t, int32(1), *worker.Spec.Replicas)
+		assert.NotContains(t, worker.Annotations, flag.ScaleFollowAnnotation)
+	})
+
+	t.Run("Target scaled down by another source", func(t *testing.T) {
+		t.Parallel()
+
+		objects := newObjects()
+		worker := objects[1].(*appsv1.Deployment)
+		worker.Spec.Replicas = testutils.Int32Ptr(0)
+		worker.Annotations = map[string]string{flag.ScaleFollowAnnotation: `{"source":"Deployment/default/queue","replicas":4}`}
+		reconciler := createBaseReconciler(objects...)
+		c := reconciler.KubeClient
+
+		scaleTo(t, c, "source", 0)
+		reconcileDeployment(t, reconciler, "source")
+		scaleTo(t, c, "source", 2)
+		reconcileDeployment(t, reconciler, "source")
+
+		updated := getDeployment(t, c, "worker")
+		assert.Equal(t, int32(0), *updated.Spec.Replicas)
+		assert.JSONEq(t, `{"source":"Deployment/default/queue","replicas":4}`, updated.Annotations[flag.ScaleFollowAnnotation])
+	})
+
+	t.Run("Target managed by HPA is skipped", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler(append(newObjects(), newHPA("worker", "worker"))...)
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+		reconciler.ScaleFollowHPA = ScaleFollowHPASkip
+		c := reconciler.KubeClient
+
+		scaleTo(t, c, "source", 0)
+		reconcileDeployment(t, reconciler, "source")
+
+		worker := getDeployment(t, c, "worker")
+		assert.Equal(t, int32(3), *worker.Spec.Replicas)
+		assert.NotContains(t, worker.Annotations, flag.ScaleFollowAnnotation)
+		assert.Contains(t, <-recorder.Events, "Normal ScaleFollowSkipped Cascader skipped scaling Deployment/default/worker to zero: managed by HorizontalPodAutoscaler worker")
+	})
+
+	t.Run("Target managed by HPA is paused", func(t *testing.T) {
+		t.Parallel()
+
+		objects := newObjects()
+		objects[1].SetAnnotations(map[string]string{flag.ScaleFollowHPAAnnotation: "pause"})
+		reconciler := createBaseReconciler(append(objects, newHPA("worker", "worker"))...)
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+		c := reconciler.KubeClient
+
+		scaleTo(t, c, "source", 0)
+		reconcileDeployment(t, reconciler, "source")
+
+		worker := getDeployment(t, c, "worker")
+		assert.Equal(t, int32(0), *worker.Spec.Replicas)
+		assert.Contains(t, <-recorder.Events, "Normal ScaledToZero Cascader scaled Deployment/default/worker to zero, remembering 3 replicas")
+	})
+}
+
+func TestFollowScaleDown_AlreadyFollowing(t *testing.T) {
+	t.Parallel()
+
+	worker := newStableDeployment("worker", map[string]string{flag.ScaleFollowAnnotation: `{"source":"Deployment/default/queue","replicas":4}`})
+	source := newStableDeployment("source", map[string]string{flag.TargetsAnnotation: scaleFollowTargets})
+	source.Spec.Replicas = testutils.Int32Ptr(0)
+	reconciler := createBaseReconciler(source, worker)
+
+	followers := []targets.Target{targets.NewDeployment("default", "worker", nil)}
+	reconciler.followScaleDown(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, followers)
+
+	updated := getDeployment(t, reconciler.KubeClient, "worker")
+	assert.Equal(t, int32(1), *updated.Spec.Replicas)
+	assert.JSONEq(t, `{"source":"Deployment/default/queue","replicas":4}`, updated.Annotations[flag.ScaleFollowAnnotation])
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/internal/metrics"
+	"github.com/thurgauerkb/cascader/internal/predicates"
+	"github.com/thurgauerkb/cascader/internal/schedule"
+	"github.com/thurgauerkb/cascader/internal/utils"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	scheduleActionRestart = "restart" // scheduleActionRestart is the metric action of a scheduled restart.
+	scheduleActionSkip    = "skip"    // scheduleActionSkip is the metric action of a missed schedule which was skipped.
+)
+
+// ScheduleReconciler restarts workloads of one kind with a cron schedule in their annotations.
+// The restart changes the pod template like a manual rollout restart, which is picked up by the
+// workload reconciler and cascades to the targets of the workload as usual.
+type ScheduleReconciler struct {
+	KubeClient       client.Client        // KubeClient is the Kubernetes API client.
+	Logger           *logr.Logger         // Logger is used for logging reconciliation events.
+	Recorder         events.EventRecorder // Recorder records Kubernetes events.
+	Metrics          *metrics.Registry    // Metrics is used for recording metrics.
+	Clock            clock.PassiveClock   // Clock returns the current time, so that tests can replace it.
+	Kind             kinds.Kind           // Kind is the kind of the scheduled workloads.
+	TimeZone         *time.Location       // TimeZone is the default time zone of schedules.
+	StartingDeadline time.Duration        // StartingDeadline is the default duration after which missed schedules are skipped, 0 always runs them.
+}
+
+// Reconcile restarts the workload once its latest schedule is due and requeues it until the next one.
+func (r *ScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	obj, err := newWorkloadObject(r.Kind)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.KubeClient.Get(ctx, req.NamespacedName, obj); err != nil {
+		if kerrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to fetch %s: %w", r.Kind, err)
+	}
+
+	if obj.GetAnnotations()[flag.ScheduleAnnotation] == "" {
+		return ctrl.Result{}, nil
+	}
+
+	workload, err := workloads.FromObject(obj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	log := r.Logger.WithValues("workloadID", workload.ID())
+
+	sched, jitter, err := r.scheduleFor(obj)
+	if err != nil {
+		// Changing the annotations triggers the next reconcile.
+		log.Error(err, "Ignoring invalid restart schedule")
+		r.Recorder.Eventf(obj, nil, corev1.EventTypeWarning, "InvalidSchedule", "Schedule", "Cascader ignored the restart schedule: %v", err)
+		return ctrl.Result{}, nil
+	}
+	deadline, err := r.startingDeadlineFor(obj)
+	if err != nil {
+		log.Error(err, "Using default starting deadline", "deadline", deadline)
+	}
+
+	now := r.Clock.Now()
+
+	last, err := lastSchedule(obj)
+	if err != nil {
+		log.Error(err, "Ignoring invalid last schedule")
+	}
+	if last.IsZero() {
+		// A new schedule starts now and does not catch up on activations before it was added.
+		if err := utils.PatchWorkloadAnnotation(ctx, r.KubeClient, obj, flag.LastScheduleAnnotation, now.UTC().Format(time.RFC3339)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to record last schedule: %w", err)
+		}
+		return r.requeueUntilNext(obj, sched, jitter, now), nil
+	}
+
+	// Activations missed while Cascader was down are coalesced into the latest one.
+	scheduled, due := sched.Latest(last, now)
+	if !due {
+		return r.requeueUntilNext(obj, sched, jitter, now), nil
+	}
+	start := scheduled.Add(jitterOf(obj, scheduled, jitter))
+	if now.Before(start) {
+		return ctrl.Result{RequeueAfter: start.Sub(now)}, nil
+	}
+
+	kind := workload.Kind().String()
+	if deadline > 0 && now.Sub(start) > deadline {
+		log.Info("Skipping missed scheduled restart", "scheduled", scheduled, "startingDeadline", deadline)
+		if err := utils.PatchWorkloadAnnotation(ctx, r.KubeClient, obj, flag.LastScheduleAnnotation, scheduled.UTC().Format(time.RFC3339)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to record last schedule: %w", err)
+		}
+		r.Recorder.Eventf(
+			obj,
+			nil,
+			corev1.EventTypeWarning,
+			"ScheduleMissed",
+			"Schedule",
+			"Cascader skipped the restart scheduled for %s: missed by more than %s",
+			scheduled.Format(time.RFC3339),
+			deadline,
+		)
+		r.Metrics.IncScheduledRestart(obj.GetNamespace(), obj.GetName(), kind, scheduleActionSkip)
+		return r.requeueUntilNext(obj, sched, jitter, now), nil
+	}
+
+	if err := r.restart(ctx, workload, scheduled, now); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to restart %s as scheduled: %w", workload.ID(), err)
+	}
+	log.Info("Restarted as scheduled", "scheduled", scheduled)
+	r.Recorder.Eventf(
+		obj,
+		nil,
+		corev1.EventTypeNormal,
+		"ScheduledRestart",
+		"Restart",
+		"Cascader restarted %s as scheduled for %s",
+		workload.ID(),
+		scheduled.Format(time.RFC3339),
+	)
+	r.Metrics.IncScheduledRestart(obj.GetNamespace(), obj.GetName(), kind, scheduleActionRestart)
+
+	return r.requeueUntilNext(obj, sched, jitter, now), nil
+}
+
+// scheduleFor parses the schedule of the workload in its time zone and returns it with its jitter.
+func (r *ScheduleReconciler) scheduleFor(obj client.Object) (*schedule.Schedule, time.Duration, error) {
+	annotations := obj.GetAnnotations()
+
+	location := r.TimeZone
+	if zone := strings.TrimSpace(annotations[flag.ScheduleTimeZoneAnnotation]); zone != "" {
+		loaded, err := time.LoadLocation(zone)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid annotation %q: %w", flag.ScheduleTimeZoneAnnotation, err)
+		}
+		location = loaded
+	}
+
+	sched, err := schedule.Parse(annotations[flag.ScheduleAnnotation], location)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid annotation %q: %w", flag.ScheduleAnnotation, err)
+	}
+
+	var jitter time.Duration
+	if val := strings.TrimSpace(annotations[flag.ScheduleJitterAnnotation]); val != "" {
+		jitter, err = time.ParseDuration(val)
+		if err != nil || jitter < 0 {
+			return nil, 0, fmt.Errorf("invalid annotation %q: must be a non-negative duration, got %q", flag.ScheduleJitterAnnotation, val)
+		}
+	}
+
+	return sched, jitter, nil
+}
+
+// startingDeadlineFor returns the duration after which a missed schedule of the workload is skipped,
+// honoring the annotation override. Invalid overrides fall back to the default.
+func (r *ScheduleReconciler) startingDeadlineFor(obj client.Object) (time.Duration, error) {
+	deadline := max(r.StartingDeadline, 0)
+
+	val := strings.TrimSpace(obj.GetAnnotations()[flag.ScheduleDeadlineAnnotation])
+	if val == "" {
+		return deadline, nil
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil || d < 0 {
+		return deadline, fmt.Errorf("invalid annotation %q: must be a non-negative duration, got %q", flag.ScheduleDeadlineAnnotation, val)
+	}
+	return d, nil
+}
+
+// restart changes the pod template of the workload and records the handled schedule in a single patch,
+// so that a failed patch neither loses nor repeats the restart.
+func (r *ScheduleReconciler) restart(ctx context.Context, workload workloads.Workload, scheduled, now time.Time) error {
+	obj := workload.Resource()
+	original := obj.DeepCopyObject().(client.Object)
+
+	template := workload.PodTemplateSpec()
+	if template.Annotations == nil {
+		template.Annotations = make(map[string]string)
+	}
+	template.Annotations[flag.LastObservedRestartAnnotation] = now.Format(time.RFC3339)
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[flag.LastScheduleAnnotation] = scheduled.UTC().Format(time.RFC3339)
+	obj.SetAnnotations(annotations)
+
+	return r.KubeClient.Patch(ctx, obj, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+}
+
+// requeueUntilNext requeues the workload until its next schedule is due, or not at all if the
+// schedule never activates again.
+func (r *ScheduleReconciler) requeueUntilNext(obj client.Object, sched *schedule.Schedule, jitter time.Duration, now time.Time) ctrl.Result {
+	next := sched.Next(now)
+	if next.IsZero() {
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: next.Add(jitterOf(obj, next, jitter)).Sub(now)}
+}
+
+// lastSchedule returns the last handled schedule of the workload, or the zero time if none is recorded.
+func lastSchedule(obj client.Object) (time.Time, error) {
+	val := obj.GetAnnotations()[flag.LastScheduleAnnotation]
+	if val == "" {
+		return time.Time{}, nil
+	}
+
+	last, err := time.Parse(time.RFC3339, val)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid annotation %q: %w", flag.LastScheduleAnnotation, err)
+	}
+	return last, nil
+}
+
+// jitterOf returns the delay of the restart scheduled at the given time, in whole seconds below the jitter.
+// It is derived from the workload and the activation, so that it is stable across reconciles and restarts of
+// Cascader, but spreads workloads sharing a schedule.
+func jitterOf(obj client.Object, scheduled time.Time, jitter time.Duration) time.Duration {
+	seconds := uint64(jitter / time.Second)
+	if seconds == 0 {
+		return 0
+	}
+
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%s/%s/%d", obj.GetNamespace(), obj.GetName(), scheduled.Unix())
+	return time.Duration(h.Sum64()%seconds) * time.Second
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Clock == nil {
+		r.Clock = clock.RealClock{}
+	}
+	if r.TimeZone == nil {
+		r.TimeZone = time.UTC
+	}
+
+	obj, err := newWorkloadObject(r.Kind)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(strings.ToLower(r.Kind.String())+"-schedule").
+		For(obj, builder.WithPredicates(predicates.ScheduleChanged())).
+		Complete(r)
+}
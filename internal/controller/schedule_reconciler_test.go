@@ -0,0 +1,323 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	internalmetrics "github.com/thurgauerkb/cascader/internal/metrics"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestScheduleReconciler_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	at := func(t *testing.T, value string) time.Time {
+		t.Helper()
+		ts, err := time.Parse(time.RFC3339, value)
+		require.NoError(t, err)
+		return ts
+	}
+	newSource := func(annotations map[string]string) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{
+			Name:        "vault-agent",
+			Namespace:   "default",
+			Annotations: annotations,
+		}}
+	}
+	nightly := func(extra map[string]string) map[string]string {
+		annotations := map[string]string{
+			"cascader.tkb.ch/deployment": "consumer",
+			flag.ScheduleAnnotation:      "0 3 * * *",
+			flag.LastScheduleAnnotation:  "2026-10-17T03:00:00Z",
+		}
+		for k, v := range extra {
+			annotations[k] = v
+		}
+		return annotations
+	}
+	newReconciler := func(source *appsv1.DaemonSet, now time.Time) (*ScheduleReconciler, *events.FakeRecorder, *clocktesting.FakePassiveClock) {
+		recorder := events.NewFakeRecorder(10)
+		clock := clocktesting.NewFakePassiveClock(now)
+		return &ScheduleReconciler{
+			KubeClient: fake.NewClientBuilder().WithObjects(source).Build(),
+			Logger:     &logr.Logger{},
+			Recorder:   recorder,
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Clock:      clock,
+			Kind:       kinds.DaemonSetKind,
+		}, recorder, clock
+	}
+	getSource := func(t *testing.T, r *ScheduleReconciler) *appsv1.DaemonSet {
+		t.Helper()
+		ds := &appsv1.DaemonSet{}
+		require.NoError(t, r.KubeClient.Get(t.Context(), types.NamespacedName{Namespace: "default", Name: "vault-agent"}, ds))
+		return ds
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "vault-agent"}}
+
+	t.Run("Records start of new schedule", func(t *testing.T) {
+		t.Parallel()
+
+		source := newSource(map[string]string{flag.ScheduleAnnotation: "0 3 * * *"})
+		r, _, _ := newReconciler(source, at(t, "2026-10-18T10:00:00Z"))
+
+		result, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, 17*time.Hour, result.RequeueAfter)
+
+		ds := getSource(t, r)
+		assert.Equal(t, "2026-10-18T10:00:00Z", ds.Annotations[flag.LastScheduleAnnotation])
+		assert.Empty(t, ds.Spec.Template.Annotations)
+	})
+
+	t.Run("Waits until schedule is due", func(t *testing.T) {
+		t.Parallel()
+
+		r, _, _ := newReconciler(newSource(nightly(nil)), at(t, "2026-10-18T02:00:00Z"))
+
+		result, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, time.Hour, result.RequeueAfter)
+		assert.Empty(t, getSource(t, r).Spec.Template.Annotations)
+	})
+
+	t.Run("Restarts when due", func(t *testing.T) {
+		t.Parallel()
+
+		r, recorder, _ := newReconciler(newSource(nightly(nil)), at(t, "2026-10-18T03:00:05Z"))
+
+		result, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, 24*time.Hour-5*time.Second, result.RequeueAfter)
+
+		ds := getSource(t, r)
+		assert.Equal(t, "2026-10-18T03:00:05Z", ds.Spec.Template.Annotations[flag.LastObservedRestartAnnotation])
+		assert.Equal(t, "2026-10-18T03:00:00Z", ds.Annotations[flag.LastScheduleAnnotation])
+		assert.Contains(t, <-recorder.Events, "Normal ScheduledRestart Cascader restarted DaemonSet/default/vault-agent as scheduled for 2026-10-18T03:00:00Z")
+
+		// The handled schedule is not restarted again.
+		result, err = r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, 24*time.Hour-5*time.Second, result.RequeueAfter)
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("Evaluates schedule in time zone", func(t *testing.T) {
+		t.Parallel()
+
+		source := newSource(nightly(map[string]string{flag.ScheduleTimeZoneAnnotation: "Europe/Zurich"}))
+		r, _, clock := newReconciler(source, at(t, "2026-10-18T00:59:00Z"))
+
+		result, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, result.RequeueAfter)
+
+		clock.SetTime(at(t, "2026-10-18T01:00:00Z"))
+		_, err = r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, "2026-10-18T01:00:00Z", getSource(t, r).Annotations[flag.LastScheduleAnnotation])
+	})
+
+	t.Run("Delays restart by jitter", func(t *testing.T) {
+		t.Parallel()
+
+		source := newSource(nightly(map[string]string{flag.ScheduleJitterAnnotation: "10m"}))
+		scheduled := at(t, "2026-10-18T03:00:00Z")
+		delay := jitterOf(source, scheduled, 10*time.Minute)
+		r, _, clock := newReconciler(source, scheduled.Add(delay).Add(-time.Second))
+
+		result, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, time.Second, result.RequeueAfter)
+		assert.Empty(t, getSource(t, r).Spec.Template.Annotations)
+
+		clock.SetTime(scheduled.Add(delay))
+		_, err = r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.NotEmpty(t, getSource(t, r).Spec.Template.Annotations[flag.LastObservedRestartAnnotation])
+	})
+
+	t.Run("Runs latest missed schedule once", func(t *testing.T) {
+		t.Parallel()
+
+		source := newSource(nightly(map[string]string{flag.LastScheduleAnnotation: "2026-10-15T03:00:00Z"}))
+		r, recorder, _ := newReconciler(source, at(t, "2026-10-18T10:00:00Z"))
+
+		result, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, 17*time.Hour, result.RequeueAfter)
+
+		ds := getSource(t, r)
+		assert.Equal(t, "2026-10-18T10:00:00Z", ds.Spec.Template.Annotations[flag.LastObservedRestartAnnotation])
+		assert.Equal(t, "2026-10-18T03:00:00Z", ds.Annotations[flag.LastScheduleAnnotation])
+		assert.Len(t, recorder.Events, 1)
+	})
+
+	t.Run("Skips schedule missed beyond starting deadline", func(t *testing.T) {
+		t.Parallel()
+
+		source := newSource(nightly(map[string]string{flag.ScheduleDeadlineAnnotation: "1h"}))
+		r, recorder, _ := newReconciler(source, at(t, "2026-10-18T10:00:00Z"))
+		r.StartingDeadline = 12 * time.Hour
+
+		result, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, 17*time.Hour, result.RequeueAfter)
+
+		ds := getSource(t, r)
+		assert.Empty(t, ds.Spec.Template.Annotations)
+		assert.Equal(t, "2026-10-18T03:00:00Z", ds.Annotations[flag.LastScheduleAnnotation])
+		assert.Contains(t, <-recorder.Events, "Warning ScheduleMissed Cascader skipped the restart scheduled for 2026-10-18T03:00:00Z: missed by more than 1h0m0s")
+	})
+
+	t.Run("Ignores invalid schedule", func(t *testing.T) {
+		t.Parallel()
+
+		source := newSource(nightly(map[string]string{flag.ScheduleAnnotation: "0 25 * * *"}))
+		r, recorder, _ := newReconciler(source, at(t, "2026-10-18T10:00:00Z"))
+
+		result, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.Empty(t, getSource(t, r).Spec.Template.Annotations)
+		assert.Contains(t, <-recorder.Events, `Warning InvalidSchedule Cascader ignored the restart schedule: invalid annotation "cascader.tkb.ch/schedule": invalid hour "25"`)
+	})
+
+	t.Run("Ignores invalid time zone", func(t *testing.T) {
+		t.Parallel()
+
+		source := newSource(nightly(map[string]string{flag.ScheduleTimeZoneAnnotation: "Mars/Olympus_Mons"}))
+		r, recorder, _ := newReconciler(source, at(t, "2026-10-18T10:00:00Z"))
+
+		result, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.Contains(t, <-recorder.Events, `Warning InvalidSchedule Cascader ignored the restart schedule: invalid annotation "cascader.tkb.ch/schedule-timezone"`)
+	})
+
+	t.Run("Ignores workloads without schedule", func(t *testing.T) {
+		t.Parallel()
+
+		r, _, _ := newReconciler(newSource(map[string]string{"cascader.tkb.ch/deployment": "consumer"}), at(t, "2026-10-18T10:00:00Z"))
+
+		result, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.NotContains(t, getSource(t, r).Annotations, flag.LastScheduleAnnotation)
+	})
+
+	t.Run("Workload not found", func(t *testing.T) {
+		t.Parallel()
+
+		r, _, _ := newReconciler(newSource(nil), at(t, "2026-10-18T10:00:00Z"))
+
+		result, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "missing"}})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+	})
+}
+
+func TestScheduleReconciler_StartingDeadlineFor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		defaultVal time.Duration
+		annotation string
+		expected   time.Duration
+		wantErr    bool
+	}{
+		{name: "Unset default", expected: 0},
+		{name: "Default", defaultVal: time.Hour, expected: time.Hour},
+		{name: "Annotation overrides default", defaultVal: time.Hour, annotation: "15m", expected: 15 * time.Minute},
+		{name: "Annotation disables deadline", defaultVal: time.Hour, annotation: "0s", expected: 0},
+		{name: "Invalid annotation", defaultVal: time.Hour, annotation: "soon", expected: time.Hour, wantErr: true},
+		{name: "Negative annotation", defaultVal: time.Hour, annotation: "-1m", expected: time.Hour, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &ScheduleReconciler{StartingDeadline: tt.defaultVal}
+			var ann map[string]string
+			if tt.annotation != "" {
+				ann = map[string]string{flag.ScheduleDeadlineAnnotation: tt.annotation}
+			}
+
+			deadline, err := r.startingDeadlineFor(newStableDeployment("source", ann))
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, deadline)
+		})
+	}
+}
+
+func TestJitterOf(t *testing.T) {
+	t.Parallel()
+
+	scheduled := time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC)
+	first := newStableDeployment("first", nil)
+	second := newStableDeployment("second", nil)
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Zero(t, jitterOf(first, scheduled, 0))
+		assert.Zero(t, jitterOf(first, scheduled, 500*time.Millisecond))
+	})
+
+	t.Run("Stable and bounded", func(t *testing.T) {
+		t.Parallel()
+
+		delay := jitterOf(first, scheduled, 10*time.Minute)
+		assert.Equal(t, delay, jitterOf(first, scheduled, 10*time.Minute))
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.Less(t, delay, 10*time.Minute)
+		assert.Zero(t, delay%time.Second)
+	})
+
+	t.Run("Spreads workloads and activations", func(t *testing.T) {
+		t.Parallel()
+
+		delays := map[time.Duration]struct{}{}
+		for _, obj := range []*appsv1.Deployment{first, second} {
+			for day := range 5 {
+				delays[jitterOf(obj, scheduled.AddDate(0, 0, day), time.Hour)] = struct{}{}
+			}
+		}
+		assert.Greater(t, len(delays), 1)
+	})
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/kinds"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// stabilityRequeue returns the requeue interval while waiting for an unstable workload.
+// With event-driven stability tracking, status updates wake the workload up and the requeue only
+// serves as a safety net, unless the workload explicitly overrides the requeue interval.
+func (b *BaseReconciler) stabilityRequeue(obj client.Object, requeueAfter time.Duration) time.Duration {
+	if b.StabilityResync <= 0 {
+		return requeueAfter
+	}
+	if strings.TrimSpace(obj.GetAnnotations()[b.RequeueAfterAnnotation]) != "" {
+		return requeueAfter
+	}
+	return b.StabilityResync
+}
+
+// pendingOwnerOf returns a map function enqueuing the workload of the given kind controlling a Pod,
+// as long as the workload has a pending cascade.
+func (b *BaseReconciler) pendingOwnerOf(kind kinds.Kind) handler.MapFunc {
+	return func(ctx context.Context, pod client.Object) []reconcile.Request {
+		req, ok := podOwnerRequest(kind, pod)
+		if !ok {
+			return nil
+		}
+
+		obj, err := newWorkloadObject(kind)
+		if err != nil {
+			return nil
+		}
+		if err := b.KubeClient.Get(ctx, req.NamespacedName, obj); err != nil {
+			return nil
+		}
+		if !hasAnnotation(obj, b.LastObservedRestartAnnotation) {
+			return nil
+		}
+
+		return []reconcile.Request{req}
+	}
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/internal/predicates"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+	"github.com/thurgauerkb/cascader/test/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestStabilityRequeue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Polls without resync", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler()
+		dep := newStableDeployment("source", nil)
+
+		assert.Equal(t, 5*time.Second, reconciler.stabilityRequeue(dep, 5*time.Second))
+	})
+
+	t.Run("Uses resync when event-driven", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler()
+		reconciler.StabilityResync = time.Minute
+		dep := newStableDeployment("source", nil)
+
+		assert.Equal(t, time.Minute, reconciler.stabilityRequeue(dep, 5*time.Second))
+	})
+
+	t.Run("Keeps explicit requeue annotation", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler()
+		reconciler.StabilityResync = time.Minute
+		dep := newStableDeployment("source", map[string]string{
+			reconciler.RequeueAfterAnnotation: "10s",
+		})
+
+		assert.Equal(t, 10*time.Second, reconciler.stabilityRequeue(dep, 10*time.Second))
+	})
+}
+
+func TestReconcileWorkload_StabilityResync(t *testing.T) {
+	t.Parallel()
+
+	source := newStableDeployment("source", map[string]string{
+		"cascader.tkb.ch/deployment": "target",
+	})
+	source.Status.UpdatedReplicas = 0
+	target := newStableDeployment("target", nil)
+
+	reconciler := createBaseReconciler(source, target)
+	reconciler.StabilityResync = time.Minute
+
+	result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Minute}, result)
+}
+
+func TestPendingOwnerOf(t *testing.T) {
+	t.Parallel()
+
+	newPod := func(owner string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      owner + "-0",
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "apps/v1", Kind: "StatefulSet", Name: owner, Controller: ptr.To(true)},
+				},
+			},
+		}
+	}
+
+	newStatefulSet := func(name string, annotations map[string]string) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+		}
+	}
+
+	t.Run("Enqueues owner with pending cascade", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler(newStatefulSet("pending", map[string]string{
+			"cascader.tkb.ch/last-observed-restart": "2026-01-01T00:00:00Z",
+		}))
+
+		reqs := reconciler.pendingOwnerOf(kinds.StatefulSetKind)(t.Context(), newPod("pending"))
+		assert.Equal(t, []reconcile.Request{
+			{NamespacedName: client.ObjectKey{Namespace: "default", Name: "pending"}},
+		}, reqs)
+	})
+
+	t.Run("Ignores owner without pending cascade", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler(newStatefulSet("idle", nil))
+
+		reqs := reconciler.pendingOwnerOf(kinds.StatefulSetKind)(t.Context(), newPod("idle"))
+		assert.Empty(t, reqs)
+	})
+
+	t.Run("Ignores missing owner", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler()
+
+		reqs := reconciler.pendingOwnerOf(kinds.StatefulSetKind)(t.Context(), newPod("missing"))
+		assert.Empty(t, reqs)
+	})
+
+	t.Run("Ignores Pod of other kind", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler(newStatefulSet("pending", map[string]string{
+			"cascader.tkb.ch/last-observed-restart": "2026-01-01T00:00:00Z",
+		}))
+
+		reqs := reconciler.pendingOwnerOf(kinds.DaemonSetKind)(t.Context(), newPod("pending"))
+		assert.Empty(t, reqs)
+	})
+}
+
+// BenchmarkStabilityTracking compares the number of reconciles and API calls needed to wait for
+// many concurrent cascades when polling with the requeue interval versus being woken up by status updates.
+func BenchmarkStabilityTracking(b *testing.B) {
+	const (
+		cascades     = 50
+		steps        = 3
+		stepInterval = 30 * time.Second
+		requeueAfter = 5 * time.Second
+	)
+
+	modes := []struct {
+		name   string
+		resync time.Duration
+	}{
+		{name: "Polling", resync: 0},
+		{name: "EventDriven", resync: time.Minute},
+	}
+
+	for _, mode := range modes {
+		b.Run(mode.name, func(b *testing.B) {
+			var reconciles, apiCalls, iterations int
+
+			for b.Loop() {
+				r, calls := newStabilityBenchReconciler(cascades, steps)
+				r.RequeueAfterDefault = requeueAfter
+				r.StabilityResync = mode.resync
+
+				for i := range cascades {
+					n, err := simulateCascade(b.Context(), r, fmt.Sprintf("source-%d", i), steps, stepInterval, mode.resync > 0)
+					if err != nil {
+						b.Fatal(err)
+					}
+					reconciles += n
+				}
+				apiCalls += *calls
+				iterations++
+			}
+
+			b.ReportMetric(float64(reconciles)/float64(iterations), "reconciles/op")
+			b.ReportMetric(float64(apiCalls)/float64(iterations), "api-calls/op")
+		})
+	}
+}
+
+// newStabilityBenchReconciler returns a reconciler with unstable sources and their targets,
+// together with a counter of the API calls issued through its client.
+func newStabilityBenchReconciler(cascades, replicas int) (*DeploymentReconciler, *int) {
+	objects := make([]client.Object, 0, 2*cascades)
+	for i := range cascades {
+		source := newStableDeployment(fmt.Sprintf("source-%d", i), map[string]string{
+			"cascader.tkb.ch/deployment": fmt.Sprintf("target-%d", i),
+		})
+		source.Spec.Replicas = testutils.Int32Ptr(int32(replicas))
+		source.Status.Replicas = int32(replicas)
+		source.Status.ReadyReplicas = int32(replicas)
+		source.Status.AvailableReplicas = int32(replicas)
+		source.Status.UpdatedReplicas = 0
+		objects = append(objects, source, newStableDeployment(fmt.Sprintf("target-%d", i), nil))
+	}
+
+	calls := 0
+	count := func() { calls++ }
+	base := createBaseReconciler()
+	base.KubeClient = fake.NewClientBuilder().
+		WithObjects(objects...).
+		WithStatusSubresource(&appsv1.Deployment{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				count()
+				return c.Get(ctx, key, obj, opts...)
+			},
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				count()
+				return c.List(ctx, list, opts...)
+			},
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				count()
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				count()
+				return c.Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	// Events are discarded, since a cascade emits more events than a buffered fake recorder holds.
+	base.Recorder = &events.FakeRecorder{}
+
+	return &DeploymentReconciler{BaseReconciler: *base}, &calls
+}
+
+// simulateCascade drives the cascade of a single source in simulated time, where one more replica
+// becomes updated every step interval, and returns the number of reconciles until the cascade finished.
+// When event-driven, the source is reconciled on every status update admitted by the CascadePending check
+// and on the returned resync; otherwise only on the returned requeue interval.
+func simulateCascade(ctx context.Context, r *DeploymentReconciler, name string, steps int, stepInterval time.Duration, eventDriven bool) (int, error) {
+	key := client.ObjectKey{Namespace: "default", Name: name}
+	pending := predicates.CascadePending(r.LastObservedRestartAnnotation)
+
+	var (
+		now        time.Duration
+		updated    int
+		reconciles int
+	)
+	for {
+		result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		if err != nil {
+			return reconciles, err
+		}
+		reconciles++
+		if result.RequeueAfter == 0 {
+			return reconciles, nil
+		}
+
+		wake := now + result.RequeueAfter
+		nextStep := time.Duration(updated+1) * stepInterval
+		if updated < steps && nextStep <= wake {
+			// Advance the rollout; with event-driven tracking the status update itself wakes the source.
+			oldObj := &appsv1.Deployment{}
+			if err := r.KubeClient.Get(ctx, key, oldObj); err != nil {
+				return reconciles, err
+			}
+			newObj := oldObj.DeepCopy()
+			updated++
+			newObj.Status.UpdatedReplicas = int32(updated)
+			if err := r.KubeClient.Status().Update(ctx, newObj); err != nil {
+				return reconciles, err
+			}
+			if eventDriven && pending(oldObj, newObj) {
+				wake = nextStep
+			}
+		}
+		now = wake
+	}
+}
@@ -19,19 +19,29 @@ package controller
 import (
 	"context"
 	"errors"
+	"strings"
 
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
 	"github.com/thurgauerkb/cascader/internal/predicates"
+	"github.com/thurgauerkb/cascader/internal/recreation"
 	"github.com/thurgauerkb/cascader/internal/workloads"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // StatefulSetReconciler reconciles StatefulSets to detect restarts and target reloads.
 type StatefulSetReconciler struct {
 	BaseReconciler
+	OnDelete workloads.OnDeleteMode // OnDelete is the default handling of StatefulSets with the OnDelete update strategy.
 }
 
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;patch
@@ -45,25 +55,95 @@ func (r *StatefulSetReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	sts := &appsv1.StatefulSet{}
 	if err := r.KubeClient.Get(ctx, req.NamespacedName, sts); err != nil {
 		if kerrors.IsNotFound(err) {
+			if _, ok := r.Recreations.Lookup(req.NamespacedName); ok {
+				logger.Info("StatefulSet deleted; dependents will be restarted once it is recreated")
+				return ctrl.Result{}, nil
+			}
 			logger.Info("StatefulSet not found; ignoring since object must be deleted")
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, errors.New("failed to fetch StatefulSet")
 	}
 
-	return r.ReconcileWorkload(ctx, &workloads.StatefulSetWorkload{StatefulSet: sts})
+	workload := &workloads.StatefulSetWorkload{StatefulSet: sts, OnDelete: r.onDeleteMode(sts)}
+	if workload.OnDeleteStrategy() && workload.OnDelete == workloads.OnDeleteSkip {
+		return r.skipOnDelete(ctx, workload)
+	}
+
+	return r.ReconcileWorkload(ctx, workload)
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *StatefulSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&appsv1.StatefulSet{}).
-		WithEventFilter(predicates.NewPredicate(
-			r.AnnotationKindMap,
-			predicates.SpecChanged,
-			predicates.SingleReplicaPodDeleted,
-			predicates.ScaledToZero,
-			predicates.ScaledFromZero,
-		)).
-		Complete(r)
+	if r.Recreations == nil {
+		r.Recreations = recreation.NewTracker()
+	}
+
+	// Predicates are set per watch, since Pod events must not be filtered by the workload predicates.
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1.StatefulSet{}, builder.WithPredicates(
+			predicate.Or(
+				predicates.Recreated(r.Recreations),
+				predicates.NewPredicate(
+					r.AnnotationKindMap,
+					predicates.SpecChanged,
+					predicates.SingleReplicaPodDeleted,
+					predicates.ScaledToZero,
+					predicates.ScaledFromZero,
+					predicates.ImageDigestsChanged,
+					predicates.PodRestartsDetected,
+					predicates.CascadePending(r.LastObservedRestartAnnotation),
+				),
+			),
+		))
+
+	if r.WatchPods {
+		b = b.Watches(
+			&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.pendingOwnerOf(kinds.StatefulSetKind)),
+			builder.WithPredicates(predicates.PodReadinessChanged()),
+		)
+	}
+
+	// Resumed workloads bypass the predicates, as their cascade is already in flight.
+	if r.Resume != nil {
+		b = b.WatchesRawSource(source.Channel(r.Resume, &handler.EnqueueRequestForObject{}))
+	}
+
+	return b.Complete(r)
+}
+
+// onDeleteMode returns the OnDelete mode for the given StatefulSet.
+// The annotation on the StatefulSet overrides the default; an invalid annotation falls back to the default.
+func (r *StatefulSetReconciler) onDeleteMode(sts *appsv1.StatefulSet) workloads.OnDeleteMode {
+	mode := r.OnDelete
+	if mode == "" {
+		mode = workloads.OnDeleteWait
+	}
+
+	val := strings.TrimSpace(sts.GetAnnotations()[flag.OnDeleteAnnotation])
+	if val == "" {
+		return mode
+	}
+
+	m, err := workloads.ParseOnDeleteMode(val)
+	if err != nil {
+		r.Logger.Error(err, "Invalid on-delete annotation, using default", "annotation", flag.OnDeleteAnnotation, "mode", mode)
+		return mode
+	}
+	return m
+}
+
+// skipOnDelete ignores restarts of a StatefulSet with the OnDelete update strategy
+// and clears a restart which was observed before the StatefulSet was skipped.
+func (r *StatefulSetReconciler) skipOnDelete(ctx context.Context, workload *workloads.StatefulSetWorkload) (ctrl.Result, error) {
+	log := r.Logger.WithValues("workloadID", workload.ID())
+	log.Info("Skipping cascade of StatefulSet with OnDelete update strategy")
+
+	if hasAnnotation(workload.Resource(), r.LastObservedRestartAnnotation) {
+		if err := r.clearLastObservedRestartAnnotation(ctx, workload); err != nil {
+			log.Error(err, "Failed to delete restartedAt annotation")
+		}
+	}
+	return ctrl.Result{}, nil
 }
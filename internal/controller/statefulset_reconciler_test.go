@@ -20,17 +20,21 @@ import (
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/thurgauerkb/cascader/internal/flag"
 	"github.com/thurgauerkb/cascader/internal/kinds"
 	internalmetrics "github.com/thurgauerkb/cascader/internal/metrics"
+	"github.com/thurgauerkb/cascader/internal/workloads"
 	"github.com/thurgauerkb/cascader/test/testutils"
 
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
@@ -152,3 +156,114 @@ func TestStatefulSetReconciler_Reconcile(t *testing.T) {
 		assert.Equal(t, ctrl.Result{}, result, "Expected successful result")
 	})
 }
+
+func TestStatefulSetReconciler_OnDelete(t *testing.T) {
+	t.Parallel()
+
+	newOnDeleteStatefulSet := func(annotations map[string]string) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "source",
+				Namespace:   "default",
+				Generation:  1,
+				Annotations: annotations,
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas:       testutils.Int32Ptr(2),
+				Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "source"}},
+				UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType},
+			},
+			Status: appsv1.StatefulSetStatus{
+				ObservedGeneration: 1,
+				Replicas:           2,
+				ReadyReplicas:      2,
+				UpdatedReplicas:    0,
+			},
+		}
+	}
+
+	t.Run("Annotation overrides default mode", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := &StatefulSetReconciler{BaseReconciler: *createBaseReconciler(), OnDelete: workloads.OnDeleteImmediate}
+		sts := newOnDeleteStatefulSet(map[string]string{flag.OnDeleteAnnotation: "wait"})
+
+		assert.Equal(t, workloads.OnDeleteWait, reconciler.onDeleteMode(sts))
+	})
+
+	t.Run("Invalid annotation falls back to default mode", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := &StatefulSetReconciler{BaseReconciler: *createBaseReconciler(), OnDelete: workloads.OnDeleteSkip}
+		sts := newOnDeleteStatefulSet(map[string]string{flag.OnDeleteAnnotation: "never"})
+
+		assert.Equal(t, workloads.OnDeleteSkip, reconciler.onDeleteMode(sts))
+	})
+
+	t.Run("Empty default is wait", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := &StatefulSetReconciler{BaseReconciler: *createBaseReconciler()}
+
+		assert.Equal(t, workloads.OnDeleteWait, reconciler.onDeleteMode(newOnDeleteStatefulSet(nil)))
+	})
+
+	t.Run("Skip clears observed restart without restarting targets", func(t *testing.T) {
+		t.Parallel()
+
+		sts := newOnDeleteStatefulSet(map[string]string{
+			"cascader.tkb.ch/deployment":            "target",
+			"cascader.tkb.ch/last-observed-restart": "2026-01-01T00:00:00Z",
+		})
+		target := newStableDeployment("target", nil)
+		reconciler := &StatefulSetReconciler{BaseReconciler: *createBaseReconciler(sts, target), OnDelete: workloads.OnDeleteSkip}
+
+		result, err := reconciler.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(sts)})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+
+		updated := &appsv1.StatefulSet{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(sts), updated))
+		assert.NotContains(t, updated.Annotations, "cascader.tkb.ch/last-observed-restart")
+
+		updatedTarget := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(target), updatedTarget))
+		assert.NotContains(t, updatedTarget.Spec.Template.Annotations, flag.LastObservedRestartAnnotation)
+	})
+
+	t.Run("Wait keeps cascade pending until Pods are replaced", func(t *testing.T) {
+		t.Parallel()
+
+		sts := newOnDeleteStatefulSet(map[string]string{
+			"cascader.tkb.ch/deployment": "target",
+		})
+		target := newStableDeployment("target", nil)
+		reconciler := &StatefulSetReconciler{BaseReconciler: *createBaseReconciler(sts, target), OnDelete: workloads.OnDeleteWait}
+
+		result, err := reconciler.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(sts)})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: defaultRequeuAfter}, result)
+
+		updatedTarget := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(target), updatedTarget))
+		assert.NotContains(t, updatedTarget.Spec.Template.Annotations, flag.LastObservedRestartAnnotation)
+	})
+
+	t.Run("Immediate cascades before Pods are replaced", func(t *testing.T) {
+		t.Parallel()
+
+		sts := newOnDeleteStatefulSet(map[string]string{
+			"cascader.tkb.ch/deployment": "target",
+		})
+		target := newStableDeployment("target", nil)
+		reconciler := &StatefulSetReconciler{BaseReconciler: *createBaseReconciler(sts, target), OnDelete: workloads.OnDeleteImmediate}
+
+		result, err := reconciler.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(sts)})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+
+		updatedTarget := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(target), updatedTarget))
+		assert.Contains(t, updatedTarget.Spec.Template.Annotations, flag.LastObservedRestartAnnotation)
+	})
+}
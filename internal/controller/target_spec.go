@@ -0,0 +1,206 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/internal/targets"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// targetSpecVersion is the version of the structured targets annotation understood by Cascader.
+const targetSpecVersion = "v1"
+
+// targetSpecs is the content of the structured targets annotation. The targets are decoded one by one,
+// so that errors point at the failing entry.
+type targetSpecs struct {
+	Version string            `json:"version"`
+	Targets []json.RawMessage `json:"targets"`
+}
+
+// targetSpec describes a target of the structured targets annotation and the options of the edge to it.
+type targetSpec struct {
+	Kind        kinds.Kind       `json:"kind"`                  // Kind of the target.
+	Namespace   string           `json:"namespace,omitempty"`   // Namespace of the target, defaults to the namespace of the source.
+	Name        string           `json:"name"`                  // Name of the target.
+	Delay       *metav1.Duration `json:"delay,omitempty"`       // Delay before the target is restarted once the source is stable.
+	Strategy    targets.Strategy `json:"strategy,omitempty"`    // Restart strategy, overrides the strategy annotated on the target.
+	When        []string         `json:"when,omitempty"`        // Template fields of the source of which one must change.
+	Optional    bool             `json:"optional,omitempty"`    // Whether the target is skipped if it does not exist.
+	ScaleFollow bool             `json:"scaleFollow,omitempty"` // Whether the target is scaled to zero with the source and restored once it is back.
+	Quiesce     bool             `json:"quiesce,omitempty"`     // Whether the target is stopped while the source is updated instead of restarted afterwards.
+}
+
+// matches reports whether the spec describes the given target, either literally or by its patterns.
+func (s targetSpec) matches(t targets.Target) bool {
+	return s.Kind == t.Kind() && matchPattern(s.Namespace, t.Namespace()) && matchPattern(s.Name, t.Name())
+}
+
+// edgeSpecList holds the structured targets of a source.
+type edgeSpecList []targetSpec
+
+// lookup returns the first spec describing the given target, or an empty spec for targets
+// which are only referenced by the per-kind annotations.
+func (l edgeSpecList) lookup(t targets.Target) targetSpec {
+	for _, spec := range l {
+		if spec.matches(t) {
+			return spec
+		}
+	}
+	return targetSpec{}
+}
+
+// validate checks the spec and normalizes its strategy.
+func (s *targetSpec) validate() error {
+	switch s.Kind {
+	case kinds.DeploymentKind, kinds.StatefulSetKind, kinds.DaemonSetKind, kinds.JobKind, kinds.CronJobKind:
+	case "":
+		return errors.New("missing kind")
+	default:
+		return fmt.Errorf("unsupported kind %q", s.Kind)
+	}
+	if s.Name == "" {
+		return errors.New("missing name")
+	}
+	if strings.HasPrefix(s.Name, groupPrefix) {
+		return errors.New("target groups are only supported in the per-kind annotations")
+	}
+	for _, pattern := range []string{s.Namespace, s.Name} {
+		if isPattern(pattern) {
+			if err := validatePattern(pattern); err != nil {
+				return err
+			}
+		}
+	}
+	if s.ScaleFollow && s.Kind != kinds.DeploymentKind && s.Kind != kinds.StatefulSetKind {
+		return fmt.Errorf("scaleFollow is not supported for kind %q", s.Kind)
+	}
+	if s.Quiesce && s.Kind != kinds.DeploymentKind && s.Kind != kinds.StatefulSetKind && s.Kind != kinds.CronJobKind {
+		return fmt.Errorf("quiesce is not supported for kind %q", s.Kind)
+	}
+	if s.Delay != nil && s.Delay.Duration < 0 {
+		return fmt.Errorf("delay must not be negative, got %s", s.Delay.Duration)
+	}
+
+	if s.Strategy != "" {
+		strategy, err := targets.ParseStrategy(string(s.Strategy))
+		if err != nil {
+			return err
+		}
+		s.Strategy = strategy
+	}
+
+	for _, field := range s.When {
+		if !slices.Contains(templateFieldNames, field) {
+			return fmt.Errorf("unsupported condition %q, expected one of %s", field, strings.Join(templateFieldNames, ", "))
+		}
+	}
+	return nil
+}
+
+// parseTargetSpecs parses the structured targets annotation of the source, which holds YAML or JSON.
+// Targets without namespace default to the namespace of the source. Returns nil if the annotation is not set.
+func parseTargetSpecs(source client.Object) ([]targetSpec, error) {
+	val, ok := source.GetAnnotations()[flag.TargetsAnnotation]
+	if !ok || strings.TrimSpace(val) == "" {
+		return nil, nil
+	}
+
+	specs, err := decodeTargetSpecs(val, source.GetNamespace())
+	if err != nil {
+		return nil, fmt.Errorf("invalid annotation %q: %w", flag.TargetsAnnotation, err)
+	}
+	return specs, nil
+}
+
+// decodeTargetSpecs decodes and validates the structured targets.
+func decodeTargetSpecs(val, defaultNS string) ([]targetSpec, error) {
+	data, err := yaml.YAMLToJSON([]byte(val))
+	if err != nil {
+		return nil, err
+	}
+
+	var list targetSpecs
+	if err := decodeStrict(data, &list); err != nil {
+		return nil, err
+	}
+	switch list.Version {
+	case targetSpecVersion:
+	case "":
+		return nil, fmt.Errorf("missing version, expected %q", targetSpecVersion)
+	default:
+		return nil, fmt.Errorf("unsupported version %q, expected %q", list.Version, targetSpecVersion)
+	}
+
+	specs := make([]targetSpec, 0, len(list.Targets))
+	for i, raw := range list.Targets {
+		var spec targetSpec
+		if err := decodeStrict(raw, &spec); err != nil {
+			return nil, fmt.Errorf("targets[%d]: %w", i, err)
+		}
+		if err := spec.validate(); err != nil {
+			return nil, fmt.Errorf("targets[%d] (%s): %w", i, describeSpec(spec), err)
+		}
+		if spec.Namespace == "" {
+			spec.Namespace = defaultNS
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// decodeStrict decodes JSON into v, rejecting unknown fields.
+func decodeStrict(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// describeSpec identifies a target spec in error messages, as far as it is known.
+func describeSpec(spec targetSpec) string {
+	parts := make([]string, 0, 3)
+	for _, part := range []string{spec.Kind.String(), spec.Namespace, spec.Name} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return "unnamed"
+	}
+	return strings.Join(parts, "/")
+}
+
+// edgeSpecs returns the structured targets of the source. Invalid annotations are reported when
+// the targets are extracted and yield no specs.
+func edgeSpecs(source client.Object) edgeSpecList {
+	specs, err := parseTargetSpecs(source)
+	if err != nil {
+		return nil
+	}
+	return specs
+}
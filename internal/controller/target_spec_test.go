@@ -0,0 +1,301 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestParseTargetSpecs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		value    string
+		expected []targetSpec
+		err      string
+	}{
+		{
+			name: "YAML",
+			value: `
+version: v1
+targets:
+  - kind: Deployment
+    name: backend
+    delay: 30s
+    strategy: evict
+    when: [images, env]
+  - kind: StatefulSet
+    namespace: db
+    name: postgres
+    optional: true
+`,
+			expected: []targetSpec{
+				{
+					Kind:      kinds.DeploymentKind,
+					Namespace: "default",
+					Name:      "backend",
+					Delay:     &metav1.Duration{Duration: 30 * time.Second},
+					Strategy:  targets.EvictStrategy,
+					When:      []string{"images", "env"},
+				},
+				{Kind: kinds.StatefulSetKind, Namespace: "db", Name: "postgres", Optional: true},
+			},
+		},
+		{
+			name:     "JSON",
+			value:    `{"version":"v1","targets":[{"kind":"DaemonSet","name":"agent"}]}`,
+			expected: []targetSpec{{Kind: kinds.DaemonSetKind, Namespace: "default", Name: "agent"}},
+		},
+		{
+			name:     "No targets",
+			value:    `version: v1`,
+			expected: []targetSpec{},
+		},
+		{
+			name:  "Missing version",
+			value: `targets: []`,
+			err:   `invalid annotation "cascader.tkb.ch/targets": missing version, expected "v1"`,
+		},
+		{
+			name:  "Unsupported version",
+			value: `version: v2`,
+			err:   `invalid annotation "cascader.tkb.ch/targets": unsupported version "v2", expected "v1"`,
+		},
+		{
+			name:  "Invalid YAML",
+			value: `version: [v1`,
+			err:   `invalid annotation "cascader.tkb.ch/targets": yaml: line 1: did not find expected ',' or ']'`,
+		},
+		{
+			name:  "Unknown top-level field",
+			value: `{"version":"v1","target":[]}`,
+			err:   `invalid annotation "cascader.tkb.ch/targets": json: unknown field "target"`,
+		},
+		{
+			name:  "Unknown field of entry",
+			value: `{"version":"v1","targets":[{"kind":"Deployment","name":"a"},{"kind":"Deployment","name":"b","dealy":"1s"}]}`,
+			err:   `invalid annotation "cascader.tkb.ch/targets": targets[1]: json: unknown field "dealy"`,
+		},
+		{
+			name:  "Unsupported kind",
+			value: `{"version":"v1","targets":[{"kind":"ReplicaSet","name":"migrate"}]}`,
+			err:   `invalid annotation "cascader.tkb.ch/targets": targets[0] (ReplicaSet/migrate): unsupported kind "ReplicaSet"`,
+		},
+		{
+			name:     "Scale follow",
+			value:    `{"version":"v1","targets":[{"kind":"StatefulSet","name":"worker","scaleFollow":true}]}`,
+			expected: []targetSpec{{Kind: kinds.StatefulSetKind, Namespace: "default", Name: "worker", ScaleFollow: true}},
+		},
+		{
+			name:  "Scale follow of unsupported kind",
+			value: `{"version":"v1","targets":[{"kind":"DaemonSet","name":"agent","scaleFollow":true}]}`,
+			err:   `invalid annotation "cascader.tkb.ch/targets": targets[0] (DaemonSet/agent): scaleFollow is not supported for kind "DaemonSet"`,
+		},
+		{
+			name:     "Quiesce",
+			value:    `{"version":"v1","targets":[{"kind":"CronJob","name":"report","quiesce":true}]}`,
+			expected: []targetSpec{{Kind: kinds.CronJobKind, Namespace: "default", Name: "report", Quiesce: true}},
+		},
+		{
+			name:  "Quiesce of unsupported kind",
+			value: `{"version":"v1","targets":[{"kind":"Job","name":"migrate","quiesce":true}]}`,
+			err:   `invalid annotation "cascader.tkb.ch/targets": targets[0] (Job/migrate): quiesce is not supported for kind "Job"`,
+		},
+		{
+			name:  "Missing name",
+			value: `{"version":"v1","targets":[{"kind":"Deployment","namespace":"db"}]}`,
+			err:   `invalid annotation "cascader.tkb.ch/targets": targets[0] (Deployment/db): missing name`,
+		},
+		{
+			name:  "Invalid delay",
+			value: `{"version":"v1","targets":[{"kind":"Deployment","name":"a","delay":"soon"}]}`,
+			err:   `invalid annotation "cascader.tkb.ch/targets": targets[0]: time: invalid duration "soon"`,
+		},
+		{
+			name:  "Negative delay",
+			value: `{"version":"v1","targets":[{"kind":"Deployment","name":"a","delay":"-1s"}]}`,
+			err:   `invalid annotation "cascader.tkb.ch/targets": targets[0] (Deployment/a): delay must not be negative, got -1s`,
+		},
+		{
+			name:  "Invalid strategy",
+			value: `{"version":"v1","targets":[{"kind":"Deployment","name":"a","strategy":"recreate"}]}`,
+			err:   `invalid annotation "cascader.tkb.ch/targets": targets[0] (Deployment/a): unsupported restart strategy: "recreate"`,
+		},
+		{
+			name:  "Invalid condition",
+			value: `{"version":"v1","targets":[{"kind":"Deployment","name":"a","when":["image"]}]}`,
+			err: `invalid annotation "cascader.tkb.ch/targets": targets[0] (Deployment/a): unsupported condition "image", ` +
+				`expected one of images, env, command, resources, volumes, annotations`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := newStableDeployment("source", map[string]string{flag.TargetsAnnotation: tt.value})
+
+			specs, err := parseTargetSpecs(source)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, specs)
+		})
+	}
+}
+
+func TestEdgeSpecs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Specs by target", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{
+			flag.TargetsAnnotation: `{"version":"v1","targets":[` +
+				`{"kind":"Deployment","name":"a","optional":true},` +
+				`{"kind":"Deployment","namespace":"*","name":"worker-*","delay":"1m"}]}`,
+		})
+
+		specs := edgeSpecs(source)
+		assert.Len(t, specs, 2)
+		assert.True(t, specs.lookup(targets.NewDeployment("default", "a", nil)).Optional)
+		assert.Equal(t, time.Minute, specs.lookup(targets.NewDeployment("jobs", "worker-3", nil)).Delay.Duration)
+		assert.Equal(t, targetSpec{}, specs.lookup(targets.NewStatefulSet("jobs", "worker-3", nil)))
+	})
+
+	t.Run("Invalid annotation", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{flag.TargetsAnnotation: `{`})
+		assert.Nil(t, edgeSpecs(source))
+	})
+}
+
+func TestReconcileWorkload_TargetSpecs(t *testing.T) {
+	t.Parallel()
+
+	b := newStableDeployment("b", nil)
+	c := newStableDeployment("c", nil)
+
+	t.Run("Structured and legacy targets are restarted", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler := createBaseReconciler(
+			newStableDeployment("a", map[string]string{
+				flag.TargetsAnnotation:       "version: v1\ntargets:\n  - kind: Deployment\n    name: b\n",
+				"cascader.tkb.ch/deployment": "c",
+			}),
+			newStableDeployment("b", nil),
+			newStableDeployment("c", nil),
+		)
+
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "a"))
+		assert.True(t, restarted(t, reconciler.KubeClient, b))
+		assert.True(t, restarted(t, reconciler.KubeClient, c))
+	})
+
+	t.Run("Invalid annotation is reported", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("a", map[string]string{
+			flag.TargetsAnnotation: `{"version":"v1","targets":[{"kind":"Deployment","name":"b"},{"kind":"ReplicaSet","name":"migrate"}]}`,
+		})
+		recorder := events.NewFakeRecorder(10)
+		reconciler := createBaseReconciler(source, newStableDeployment("b", nil))
+		reconciler.Recorder = recorder
+
+		_, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.Error(t, err)
+		assert.Equal(
+			t,
+			`Warning InvalidTargets Cascader cannot determine the targets: invalid annotation "cascader.tkb.ch/targets": targets[1] (ReplicaSet/migrate): unsupported kind "ReplicaSet"`,
+			<-recorder.Events,
+		)
+		assert.False(t, restarted(t, reconciler.KubeClient, b))
+	})
+
+	t.Run("Missing optional target is skipped", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := events.NewFakeRecorder(10)
+		reconciler := createBaseReconciler(
+			newStableDeployment("a", map[string]string{
+				flag.TargetsAnnotation: `{"version":"v1","targets":[{"kind":"Deployment","name":"b"},{"kind":"Deployment","name":"missing","optional":true}]}`,
+			}),
+			newStableDeployment("b", nil),
+		)
+		reconciler.Recorder = recorder
+		reconciler.MissingTargets = MissingTargetsFail
+
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "a"))
+		assert.True(t, restarted(t, reconciler.KubeClient, b))
+		assert.Equal(t, `Normal ReloadSucceeded Cascader triggered reload due to change in "Deployment/default/a"`, <-recorder.Events)
+	})
+
+	t.Run("Delayed target", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := events.NewFakeRecorder(10)
+		reconciler := createBaseReconciler(
+			newStableDeployment("a", map[string]string{
+				flag.TargetsAnnotation: `{"version":"v1","targets":[{"kind":"Deployment","name":"b","delay":"1h"},{"kind":"Deployment","name":"c"}]}`,
+			}),
+			newStableDeployment("b", nil),
+			newStableDeployment("c", nil),
+		)
+		reconciler.Recorder = recorder
+
+		result := reconcileDeployment(t, reconciler, "a")
+		assert.Equal(t, defaultRequeuAfter, result.RequeueAfter)
+		assert.False(t, restarted(t, reconciler.KubeClient, b))
+		assert.True(t, restarted(t, reconciler.KubeClient, c))
+		assert.Equal(t, "Normal RestartDeferred Cascader deferred restart of Deployment/default/b: delayed by 1h0m0s", <-recorder.Events)
+
+		// The delay has not passed yet.
+		result = reconcileDeployment(t, reconciler, "a")
+		assert.Equal(t, defaultRequeuAfter, result.RequeueAfter)
+		assert.False(t, restarted(t, reconciler.KubeClient, b))
+
+		// Pretend the cascade started before the delay.
+		source := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "a"}, source))
+		state, err := loadDeferredState(source)
+		require.NoError(t, err)
+		state.Since = state.Since.Add(-time.Hour)
+		require.NoError(t, reconciler.saveDeferredState(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, state))
+
+		assert.Equal(t, ctrl.Result{}, reconcileDeployment(t, reconciler, "a"))
+		assert.True(t, restarted(t, reconciler.KubeClient, b))
+	})
+}
@@ -17,9 +17,20 @@ limitations under the License.
 package controller
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
 	"github.com/thurgauerkb/cascader/internal/targets"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // targetIDs returns the list of IDs for a slice of targets.
@@ -31,6 +42,22 @@ func targetIDs(ts []targets.Target) []string {
 	return ids
 }
 
+// restartedTargets returns the targets which did not fail to restart.
+func restartedTargets(all, failed []targets.Target) []targets.Target {
+	return withoutTargets(all, failed)
+}
+
+// withoutTargets returns the targets which are not contained in excluded.
+func withoutTargets(all, excluded []targets.Target) []targets.Target {
+	remaining := make([]targets.Target, 0, len(all))
+	for _, t := range all {
+		if !slices.ContainsFunc(excluded, func(e targets.Target) bool { return e.ID() == t.ID() }) {
+			remaining = append(remaining, t)
+		}
+	}
+	return remaining
+}
+
 // hasAnnotation reports whether a annotation is present.
 func hasAnnotation(obj client.Object, key string) bool {
 	ann := obj.GetAnnotations()
@@ -40,3 +67,75 @@ func hasAnnotation(obj client.Object, key string) bool {
 	_, found := ann[key]
 	return found
 }
+
+// hasTargetAnnotation reports whether any of the target annotations, or the structured targets annotation, is present.
+func hasTargetAnnotation(obj client.Object, annotations kinds.AnnotationKindMap) bool {
+	if hasAnnotation(obj, flag.TargetsAnnotation) {
+		return true
+	}
+	for key := range annotations {
+		if hasAnnotation(obj, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// newWorkloadObject returns an empty object for the given workload kind.
+func newWorkloadObject(kind kinds.Kind) (client.Object, error) {
+	switch kind {
+	case kinds.DeploymentKind:
+		return &appsv1.Deployment{}, nil
+	case kinds.StatefulSetKind:
+		return &appsv1.StatefulSet{}, nil
+	case kinds.DaemonSetKind:
+		return &appsv1.DaemonSet{}, nil
+	case kinds.JobKind:
+		return &batchv1.Job{}, nil
+	case kinds.CronJobKind:
+		return &batchv1.CronJob{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported workload kind: %s", kind)
+	}
+}
+
+// newWorkloadList returns an empty list for the given workload kind.
+func newWorkloadList(kind kinds.Kind) (client.ObjectList, error) {
+	switch kind {
+	case kinds.DeploymentKind:
+		return &appsv1.DeploymentList{}, nil
+	case kinds.StatefulSetKind:
+		return &appsv1.StatefulSetList{}, nil
+	case kinds.DaemonSetKind:
+		return &appsv1.DaemonSetList{}, nil
+	case kinds.JobKind:
+		return &batchv1.JobList{}, nil
+	case kinds.CronJobKind:
+		return &batchv1.CronJobList{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported workload kind: %s", kind)
+	}
+}
+
+// podOwnerRequest maps a Pod to a request for the workload of the given kind controlling it.
+// Deployment Pods are owned by a ReplicaSet named after the Deployment and the pod template hash.
+func podOwnerRequest(kind kinds.Kind, pod client.Object) (reconcile.Request, bool) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return reconcile.Request{}, false
+	}
+
+	name := owner.Name
+	switch {
+	case kind == kinds.DeploymentKind && owner.Kind == "ReplicaSet":
+		hash := pod.GetLabels()[appsv1.DefaultDeploymentUniqueLabelKey]
+		if hash == "" || !strings.HasSuffix(name, "-"+hash) {
+			return reconcile.Request{}, false
+		}
+		name = strings.TrimSuffix(name, "-"+hash)
+	case owner.Kind != kind.String():
+		return reconcile.Request{}, false
+	}
+
+	return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: pod.GetNamespace(), Name: name}}, true
+}
@@ -0,0 +1,448 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/internal/promquery"
+	"github.com/thurgauerkb/cascader/internal/targets"
+	"github.com/thurgauerkb/cascader/internal/utils"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// metricsCheck is a PromQL expression whose result must satisfy a threshold.
+type metricsCheck struct {
+	Query     string
+	Threshold promquery.Threshold
+}
+
+// parseMetricsCheck reads a metrics check from the given query and threshold annotations.
+// Returns nil if neither annotation is set.
+func parseMetricsCheck(annotations map[string]string, queryKey, thresholdKey string) (*metricsCheck, error) {
+	query := strings.TrimSpace(annotations[queryKey])
+	val := strings.TrimSpace(annotations[thresholdKey])
+	if query == "" {
+		if val != "" {
+			return nil, fmt.Errorf("invalid annotation %q: missing query in %q", thresholdKey, queryKey)
+		}
+		return nil, nil
+	}
+
+	if val == "" {
+		return nil, fmt.Errorf("invalid annotation %q: missing threshold in %q", queryKey, thresholdKey)
+	}
+	threshold, err := promquery.ParseThreshold(val)
+	if err != nil {
+		return nil, fmt.Errorf("invalid annotation %q: %w", thresholdKey, err)
+	}
+
+	return &metricsCheck{Query: query, Threshold: threshold}, nil
+}
+
+// evaluateMetrics evaluates the metrics check against Prometheus.
+func (b *BaseReconciler) evaluateMetrics(ctx context.Context, check *metricsCheck) error {
+	if b.Prometheus == nil {
+		return errors.New("no Prometheus configured, set --prometheus-url")
+	}
+	return promquery.Evaluate(ctx, b.Prometheus, check.Query, check.Threshold)
+}
+
+// verificationState tracks the restarted targets of a source whose post-restart checks did not pass yet.
+// It is persisted as JSON in an annotation on the source.
+type verificationState struct {
+	Generation int64     `json:"generation"`        // Generation of the source the cascade belongs to.
+	Since      time.Time `json:"since"`             // Time the first target was restarted.
+	Targets    []string  `json:"targets"`           // IDs of the targets pending verification.
+	Failing    []string  `json:"failing,omitempty"` // IDs of the targets whose check failed, reported once.
+}
+
+// loadVerificationState reads the verification state from the source annotations.
+// Returns nil if no verification is pending.
+func loadVerificationState(obj client.Object) (*verificationState, error) {
+	val, ok := obj.GetAnnotations()[flag.PendingVerificationAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	state := &verificationState{}
+	if err := json.Unmarshal([]byte(val), state); err != nil {
+		return nil, fmt.Errorf("invalid annotation %q: %w", flag.PendingVerificationAnnotation, err)
+	}
+	return state, nil
+}
+
+// saveVerificationState persists the verification state on the source workload.
+func (b *BaseReconciler) saveVerificationState(ctx context.Context, workload workloads.Workload, state *verificationState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to serialize verification state: %w", err)
+	}
+	return utils.PatchWorkloadAnnotation(ctx, b.KubeClient, workload.Resource(), flag.PendingVerificationAnnotation, string(data))
+}
+
+// clearVerificationState removes the verification state from the source workload, if present.
+func (b *BaseReconciler) clearVerificationState(ctx context.Context, workload workloads.Workload) error {
+	if !hasAnnotation(workload.Resource(), flag.PendingVerificationAnnotation) {
+		return nil
+	}
+	return utils.DeleteWorkloadAnnotation(ctx, b.KubeClient, workload.Resource(), flag.PendingVerificationAnnotation)
+}
+
+// scheduleVerification records the restarted targets which have a post-restart check and requeues
+// the source to verify them. Targets already pending verification for the same cascade are kept.
+func (b *BaseReconciler) scheduleVerification(
+	ctx context.Context,
+	workload workloads.Workload,
+	restarted []targets.Target,
+	requeueAfter time.Duration,
+) (ctrl.Result, error) {
+	res := workload.Resource()
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	state, err := loadVerificationState(res)
+	if err != nil || state == nil || state.Generation != res.GetGeneration() {
+		state = &verificationState{Generation: res.GetGeneration(), Since: time.Now()}
+	}
+
+	for _, t := range restarted {
+		obj := t.Resource()
+		if err := b.KubeClient.Get(ctx, client.ObjectKey{Namespace: t.Namespace(), Name: t.Name()}, obj); err != nil {
+			log.Error(err, "Failed to fetch target for post-restart check", "targetID", t.ID())
+			continue
+		}
+		if hasPostRestartCheck(obj) && !slices.Contains(state.Targets, t.ID()) {
+			state.Targets = append(state.Targets, t.ID())
+		}
+	}
+
+	if len(state.Targets) == 0 {
+		return ctrl.Result{}, nil
+	}
+	if err := b.saveVerificationState(ctx, workload, state); err != nil {
+		log.Error(err, "Failed to persist verification state; post-restart checks will be skipped")
+		return ctrl.Result{}, nil
+	}
+
+	log.Info(fmt.Sprintf("Verifying restarted targets after %s.", requeueAfter), "targets", state.Targets)
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// hasPostRestartCheck returns true if the object configures a post-restart check, even an invalid one.
+func hasPostRestartCheck(obj client.Object) bool {
+	return hasAnnotation(obj, flag.PostRestartCheckAnnotation) || hasAnnotation(obj, flag.PostRestartThresholdAnnotation)
+}
+
+// verifyTargets evaluates the post-restart checks of the restarted targets once they are stable.
+// Failed checks keep the cascade paused and are reported once, until the checks pass or time out.
+func (b *BaseReconciler) verifyTargets(ctx context.Context, workload workloads.Workload, state *verificationState) (ctrl.Result, error) {
+	res := workload.Resource()
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	if b.PostRestartCheckTimeout > 0 && time.Since(state.Since) >= b.PostRestartCheckTimeout {
+		b.Recorder.Eventf(
+			res,
+			nil,
+			corev1.EventTypeWarning,
+			"PostRestartCheckFailed",
+			"VerifyRestart",
+			"Post-restart checks did not pass within %s: %s",
+			b.PostRestartCheckTimeout,
+			strings.Join(state.Targets, ", "),
+		)
+		log.Error(errors.New("post-restart checks timed out"), "Giving up on post-restart checks", "targets", state.Targets)
+		b.abortVerificationHolds(ctx, workload, state.Targets)
+		if err := b.clearVerificationState(ctx, workload); err != nil {
+			log.Error(err, "Failed to delete verification annotation")
+		}
+		return ctrl.Result{}, nil
+	}
+
+	all, err := b.extractTargets(ctx, res)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to create targets: %w", err)
+	}
+
+	// Only verify targets which are still referenced by the source.
+	var pending, failing []string
+	for _, t := range all {
+		if !slices.Contains(state.Targets, t.ID()) {
+			continue
+		}
+
+		reason, failed, err := b.verifyTarget(ctx, workload, t, slices.Contains(state.Failing, t.ID()))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if reason == "" {
+			log.Info("Post-restart check passed", "targetID", t.ID())
+			continue
+		}
+		pending = append(pending, t.ID())
+		if failed {
+			failing = append(failing, t.ID())
+		}
+		log.Info("Post-restart check pending", "targetID", t.ID(), "reason", reason)
+	}
+
+	if len(pending) == 0 {
+		if err := b.clearVerificationState(ctx, workload); err != nil {
+			log.Error(err, "Failed to delete verification annotation")
+		}
+		log.Info("Finished verifying targets")
+		return ctrl.Result{}, nil
+	}
+
+	state.Targets, state.Failing = pending, failing
+	if err := b.saveVerificationState(ctx, workload, state); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to patch verification state: %w", err)
+	}
+
+	dur, err := b.requeueDurationFor(res)
+	if err != nil {
+		log.Error(err, fmt.Sprintf("Invalid requeue annotation, using default: %s", b.RequeueAfterDefault))
+	}
+	return ctrl.Result{RequeueAfter: dur}, nil
+}
+
+// verifyTarget evaluates the post-restart check of a single target. It returns why the check did not
+// pass yet, or an empty string once it passed, and whether the check itself failed. An invalid check
+// counts as failed. A newly failed check is recorded as event on the target and the source.
+func (b *BaseReconciler) verifyTarget(
+	ctx context.Context,
+	workload workloads.Workload,
+	t targets.Target,
+	reported bool,
+) (string, bool, error) {
+	obj := t.Resource()
+	if err := b.KubeClient.Get(ctx, client.ObjectKey{Namespace: t.Namespace(), Name: t.Name()}, obj); err != nil {
+		return "", false, fmt.Errorf("failed to fetch %s: %w", t.ID(), err)
+	}
+
+	check, err := parseMetricsCheck(obj.GetAnnotations(), flag.PostRestartCheckAnnotation, flag.PostRestartThresholdAnnotation)
+	if err != nil {
+		if !reported {
+			if err := b.reportCheckFailure(ctx, workload, t, obj, "InvalidPostRestartCheck", "invalid", err); err != nil {
+				return "", false, err
+			}
+		}
+		return fmt.Sprintf("invalid post-restart check: %s", err), true, nil
+	}
+	if check == nil {
+		// The check was removed from the target in the meantime.
+		return "", false, b.releaseVerificationHold(ctx, obj)
+	}
+
+	target, err := workloads.FromObject(obj)
+	if err != nil {
+		return "", false, err
+	}
+	if stable, reason := target.Stable(); !stable {
+		return fmt.Sprintf("target not stable: %s", reason), false, nil
+	}
+
+	if err := b.evaluateMetrics(ctx, check); err != nil {
+		if !reported {
+			if err := b.reportCheckFailure(ctx, workload, t, obj, "PostRestartCheckFailed", "failed", err); err != nil {
+				return "", false, err
+			}
+		}
+		return fmt.Sprintf("post-restart check failed: %s", err), true, nil
+	}
+
+	return "", false, b.releaseVerificationHold(ctx, obj)
+}
+
+// reportCheckFailure records a failed or invalid post-restart check in the verification hold of the target
+// and as event on the target and the source.
+func (b *BaseReconciler) reportCheckFailure(
+	ctx context.Context,
+	workload workloads.Workload,
+	t targets.Target,
+	obj client.Object,
+	reason, outcome string,
+	checkErr error,
+) error {
+	if err := b.updateVerificationHold(ctx, obj, checkErr.Error(), false); err != nil {
+		return fmt.Errorf("failed to patch verification hold of %s: %w", t.ID(), err)
+	}
+	b.Recorder.Eventf(
+		obj,
+		workload.Resource(),
+		corev1.EventTypeWarning,
+		reason,
+		"VerifyRestart",
+		"Post-restart check %s after restart by %q: %s",
+		outcome,
+		workload.ID(),
+		checkErr,
+	)
+	b.Recorder.Eventf(
+		workload.Resource(),
+		obj,
+		corev1.EventTypeWarning,
+		reason,
+		"VerifyRestart",
+		"Cascade paused, post-restart check of %s %s: %s",
+		t.ID(),
+		outcome,
+		checkErr,
+	)
+	return nil
+}
+
+// verificationHold pauses the cascade of a restarted target until the upstream which restarted it
+// verified its post-restart check. It is persisted as JSON in an annotation on the target, which the
+// target honors like a stability gate.
+type verificationHold struct {
+	Source     string `json:"source"`            // ID of the upstream verifying the target.
+	Generation int64  `json:"generation"`        // Generation of the target the restart results in.
+	Failed     string `json:"failed,omitempty"`  // Why the post-restart check failed, empty while it was not evaluated yet.
+	Aborted    bool   `json:"aborted,omitempty"` // Whether the upstream gave up on the post-restart check.
+}
+
+// loadVerificationHold reads the verification hold from the target annotations.
+// Returns nil if the target is not held.
+func loadVerificationHold(obj client.Object) (*verificationHold, error) {
+	val, ok := obj.GetAnnotations()[flag.VerificationHoldAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	hold := &verificationHold{}
+	if err := json.Unmarshal([]byte(val), hold); err != nil {
+		return nil, fmt.Errorf("invalid annotation %q: %w", flag.VerificationHoldAnnotation, err)
+	}
+	return hold, nil
+}
+
+// holdForVerification holds the cascade of a target with a post-restart check and targets of its own
+// before it is restarted, so that its targets are not restarted before the check passed.
+// Returns the held target, or nil if the target is not held.
+func (b *BaseReconciler) holdForVerification(ctx context.Context, sourceID string, t targets.Target) (client.Object, error) {
+	obj := t.Resource()
+	if err := b.KubeClient.Get(ctx, client.ObjectKey{Namespace: t.Namespace(), Name: t.Name()}, obj); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil // Missing targets are reported by the restart.
+		}
+		return nil, fmt.Errorf("failed to fetch %s: %w", t.ID(), err)
+	}
+	if !hasPostRestartCheck(obj) || !hasTargetAnnotation(obj, b.AnnotationKindMap) {
+		return nil, nil
+	}
+
+	// Restarting the target increments its generation, re-running a Job recreates it.
+	generation := obj.GetGeneration() + 1
+	if t.Kind() == kinds.JobKind {
+		generation = 1
+	}
+	if err := b.patchJSONAnnotation(ctx, obj, flag.VerificationHoldAnnotation, &verificationHold{
+		Source:     sourceID,
+		Generation: generation,
+	}); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// releaseVerificationHold removes the verification hold from the target, if present.
+func (b *BaseReconciler) releaseVerificationHold(ctx context.Context, obj client.Object) error {
+	if !hasAnnotation(obj, flag.VerificationHoldAnnotation) {
+		return nil
+	}
+	return utils.DeleteWorkloadAnnotation(ctx, b.KubeClient, obj, flag.VerificationHoldAnnotation)
+}
+
+// updateVerificationHold records the outcome of the post-restart check in the verification hold of the target, if present.
+func (b *BaseReconciler) updateVerificationHold(ctx context.Context, obj client.Object, failed string, aborted bool) error {
+	hold, err := loadVerificationHold(obj)
+	if err != nil || hold == nil {
+		return err
+	}
+	hold.Failed, hold.Aborted = failed, aborted
+	return b.patchJSONAnnotation(ctx, obj, flag.VerificationHoldAnnotation, hold)
+}
+
+// abortVerificationHolds marks the verification holds of the given targets as aborted, so that the targets
+// abort their own cascade instead of waiting for a post-restart check which is no longer evaluated.
+func (b *BaseReconciler) abortVerificationHolds(ctx context.Context, workload workloads.Workload, ids []string) {
+	res := workload.Resource()
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	for _, id := range ids {
+		kind, ns, name, err := utils.ParseID(id)
+		if err != nil {
+			log.Error(err, "Skipping verification hold of invalid target", "targetID", id)
+			continue
+		}
+		t, err := targets.NewTarget(ctx, b.KubeClient, kind, ns+"/"+name, res)
+		if err != nil {
+			log.Error(err, "Skipping verification hold of invalid target", "targetID", id)
+			continue
+		}
+		obj := t.Resource()
+		if err := b.KubeClient.Get(ctx, client.ObjectKey{Namespace: ns, Name: name}, obj); err != nil {
+			log.Error(err, "Failed to fetch target", "targetID", id)
+			continue
+		}
+		reason := fmt.Sprintf("timed out after %s", b.PostRestartCheckTimeout)
+		if err := b.updateVerificationHold(ctx, obj, reason, true); err != nil {
+			log.Error(err, "Failed to abort verification hold", "targetID", id)
+		}
+	}
+}
+
+// checkVerificationHold evaluates the verification hold of a stable workload like a stability gate. The cascade
+// is pending while its upstream verifies the workload and fails once the upstream gave up. Holds of an earlier
+// restart are released, holds of a restart which was not applied yet stay pending.
+func (b *BaseReconciler) checkVerificationHold(ctx context.Context, workload workloads.Workload, requeueAfter time.Duration) gateResult {
+	res := workload.Resource()
+	log := b.Logger.WithValues("workloadID", workload.ID())
+
+	hold, err := loadVerificationHold(res)
+	if err != nil {
+		log.Error(err, "Discarding invalid verification hold")
+	}
+	if hold == nil || hold.Generation < res.GetGeneration() {
+		if err := b.releaseVerificationHold(ctx, res); err != nil {
+			log.Error(err, "Failed to delete verification hold annotation")
+		}
+		return gateResult{}
+	}
+
+	switch {
+	case hold.Aborted:
+		return gateResult{Failed: fmt.Sprintf("post-restart check by %s did not pass: %s", hold.Source, hold.Failed)}
+	case hold.Failed != "":
+		return gateResult{Pending: fmt.Sprintf("post-restart check by %s failed: %s", hold.Source, hold.Failed), Requeue: requeueAfter}
+	default:
+		return gateResult{Pending: fmt.Sprintf("awaiting post-restart check by %s", hold.Source), Requeue: requeueAfter}
+	}
+}
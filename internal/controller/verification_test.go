@@ -0,0 +1,523 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/promquery"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fakeQuerier answers queries with a single sample per query, or an error for unknown queries.
+type fakeQuerier map[string]float64
+
+func (f fakeQuerier) Query(_ context.Context, query string, ts time.Time, _ ...v1.Option) (model.Value, v1.Warnings, error) {
+	v, ok := f[query]
+	if !ok {
+		return nil, nil, errors.New("connection refused")
+	}
+	return model.Vector{{Metric: model.Metric{}, Value: model.SampleValue(v), Timestamp: model.TimeFromUnixNano(ts.UnixNano())}}, nil, nil
+}
+
+func TestParseMetricsCheck(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Not configured", func(t *testing.T) {
+		t.Parallel()
+
+		check, err := parseMetricsCheck(map[string]string{}, flag.MetricsGateAnnotation, flag.MetricsGateThresholdAnnotation)
+		require.NoError(t, err)
+		assert.Nil(t, check)
+	})
+
+	t.Run("Valid check", func(t *testing.T) {
+		t.Parallel()
+
+		check, err := parseMetricsCheck(map[string]string{
+			flag.MetricsGateAnnotation:          "error_rate",
+			flag.MetricsGateThresholdAnnotation: "<0.05",
+		}, flag.MetricsGateAnnotation, flag.MetricsGateThresholdAnnotation)
+		require.NoError(t, err)
+		assert.Equal(t, &metricsCheck{Query: "error_rate", Threshold: promquery.Threshold{Op: "<", Value: 0.05}}, check)
+	})
+
+	t.Run("Missing threshold", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseMetricsCheck(map[string]string{
+			flag.MetricsGateAnnotation: "error_rate",
+		}, flag.MetricsGateAnnotation, flag.MetricsGateThresholdAnnotation)
+		assert.EqualError(t, err, `invalid annotation "cascader.tkb.ch/metrics-gate": missing threshold in "cascader.tkb.ch/metrics-gate-threshold"`)
+	})
+
+	t.Run("Missing query", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseMetricsCheck(map[string]string{
+			flag.MetricsGateThresholdAnnotation: "<0.05",
+		}, flag.MetricsGateAnnotation, flag.MetricsGateThresholdAnnotation)
+		assert.EqualError(t, err, `invalid annotation "cascader.tkb.ch/metrics-gate-threshold": missing query in "cascader.tkb.ch/metrics-gate"`)
+	})
+
+	t.Run("Invalid threshold", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseMetricsCheck(map[string]string{
+			flag.MetricsGateAnnotation:          "error_rate",
+			flag.MetricsGateThresholdAnnotation: "low",
+		}, flag.MetricsGateAnnotation, flag.MetricsGateThresholdAnnotation)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid annotation "cascader.tkb.ch/metrics-gate-threshold"`)
+	})
+}
+
+func TestCheckStabilityGates_MetricsGate(t *testing.T) {
+	t.Parallel()
+
+	newSource := func() *appsv1.Deployment {
+		return newGatedSource(map[string]string{
+			flag.MetricsGateAnnotation:          "error_rate",
+			flag.MetricsGateThresholdAnnotation: "<0.05",
+		})
+	}
+
+	t.Run("Error rate normal", func(t *testing.T) {
+		t.Parallel()
+
+		source := newSource()
+		reconciler := createBaseReconciler(source)
+		reconciler.Prometheus = fakeQuerier{"error_rate": 0.01}
+
+		result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Empty(t, result.Pending)
+	})
+
+	t.Run("Error rate elevated pauses cascade", func(t *testing.T) {
+		t.Parallel()
+
+		source := newSource()
+		reconciler := createBaseReconciler(source)
+		reconciler.Prometheus = fakeQuerier{"error_rate": 0.2}
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+		workload := &workloads.DeploymentWorkload{Deployment: source}
+
+		result, err := reconciler.checkStabilityGates(t.Context(), workload, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Equal(t, "stability gates pending: metrics gate: value 0.2 does not satisfy <0.05", result.Pending)
+		assert.Empty(t, result.Failed)
+		assert.Equal(t, "Warning MetricsGateFailed Cascade paused, metrics gate failed: value 0.2 does not satisfy <0.05", <-recorder.Events)
+
+		// The failure is reported only once.
+		_, err = reconciler.checkStabilityGates(t.Context(), workload, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("Invalid gate pauses cascade", func(t *testing.T) {
+		t.Parallel()
+
+		source := newGatedSource(map[string]string{flag.MetricsGateAnnotation: "error_rate"})
+		reconciler := createBaseReconciler(source)
+		reconciler.Prometheus = fakeQuerier{"error_rate": 0}
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+
+		result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Equal(t, `invalid stability gate: invalid annotation "cascader.tkb.ch/metrics-gate": missing threshold in "cascader.tkb.ch/metrics-gate-threshold"`, result.Pending)
+		assert.Equal(t, `Warning InvalidStabilityGate Cascade paused, invalid stability gate: invalid annotation "cascader.tkb.ch/metrics-gate": missing threshold in "cascader.tkb.ch/metrics-gate-threshold"`, <-recorder.Events)
+	})
+
+	t.Run("Prometheus not configured", func(t *testing.T) {
+		t.Parallel()
+
+		source := newSource()
+		reconciler := createBaseReconciler(source)
+
+		result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Equal(t, "stability gates pending: metrics gate: no Prometheus configured, set --prometheus-url", result.Pending)
+	})
+
+	t.Run("Prometheus unreachable", func(t *testing.T) {
+		t.Parallel()
+
+		source := newSource()
+		reconciler := createBaseReconciler(source)
+		reconciler.Prometheus = fakeQuerier{}
+
+		result, err := reconciler.checkStabilityGates(t.Context(), &workloads.DeploymentWorkload{Deployment: source}, defaultRequeuAfter)
+		require.NoError(t, err)
+		assert.Equal(t, `stability gates pending: metrics gate: query "error_rate" failed: connection refused`, result.Pending)
+	})
+}
+
+func TestReconcileWorkload_PostRestartCheck(t *testing.T) {
+	t.Parallel()
+
+	// newVerifiedTarget returns a stable target with a post-restart check on the given query.
+	newVerifiedTarget := func(name, query string) *appsv1.Deployment {
+		return newStableDeployment(name, map[string]string{
+			flag.PostRestartCheckAnnotation:     query,
+			flag.PostRestartThresholdAnnotation: "<0.05",
+		})
+	}
+
+	t.Run("Schedules verification of restarted targets", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{
+			"cascader.tkb.ch/deployment": "checked,unchecked",
+		})
+		reconciler := createBaseReconciler(source, newVerifiedTarget("checked", "error_rate"), newStableDeployment("unchecked", nil))
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: defaultRequeuAfter}, result)
+
+		state, err := loadVerificationState(source)
+		require.NoError(t, err)
+		require.NotNil(t, state)
+		assert.Equal(t, []string{"Deployment/default/checked"}, state.Targets)
+	})
+
+	t.Run("No verification without post-restart checks", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{
+			"cascader.tkb.ch/deployment": "unchecked",
+		})
+		reconciler := createBaseReconciler(source, newStableDeployment("unchecked", nil))
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.NotContains(t, source.Annotations, flag.PendingVerificationAnnotation)
+	})
+
+	t.Run("Check passes", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{
+			"cascader.tkb.ch/deployment":       "checked",
+			flag.PendingVerificationAnnotation: `{"generation":1,"since":"` + time.Now().Format(time.RFC3339) + `","targets":["Deployment/default/checked"]}`,
+		})
+		target := newVerifiedTarget("checked", "error_rate")
+		reconciler := createBaseReconciler(source, target)
+		reconciler.Prometheus = fakeQuerier{"error_rate": 0}
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.NotContains(t, source.Annotations, flag.PendingVerificationAnnotation)
+
+		// The verification does not restart the target again.
+		updatedTarget := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(target), updatedTarget))
+		assert.NotContains(t, updatedTarget.Spec.Template.Annotations, flag.LastObservedRestartAnnotation)
+	})
+
+	t.Run("Check fails and pauses", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{
+			"cascader.tkb.ch/deployment":       "checked",
+			flag.PendingVerificationAnnotation: `{"generation":1,"since":"` + time.Now().Format(time.RFC3339) + `","targets":["Deployment/default/checked"]}`,
+		})
+		reconciler := createBaseReconciler(source, newVerifiedTarget("checked", "error_rate"))
+		reconciler.Prometheus = fakeQuerier{"error_rate": 0.5}
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+		workload := &workloads.DeploymentWorkload{Deployment: source}
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), workload)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: defaultRequeuAfter}, result)
+		assert.Equal(t, `Warning PostRestartCheckFailed Post-restart check failed after restart by "Deployment/default/source": value 0.5 does not satisfy <0.05`, <-recorder.Events)
+		assert.Equal(t, "Warning PostRestartCheckFailed Cascade paused, post-restart check of Deployment/default/checked failed: value 0.5 does not satisfy <0.05", <-recorder.Events)
+
+		state, err := loadVerificationState(source)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Deployment/default/checked"}, state.Failing)
+
+		// The failure is reported only once.
+		_, err = reconciler.ReconcileWorkload(t.Context(), workload)
+		require.NoError(t, err)
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("Invalid check fails and pauses", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{
+			"cascader.tkb.ch/deployment":       "checked",
+			flag.PendingVerificationAnnotation: `{"generation":1,"since":"` + time.Now().Format(time.RFC3339) + `","targets":["Deployment/default/checked"]}`,
+		})
+		target := newStableDeployment("checked", map[string]string{flag.PostRestartCheckAnnotation: "error_rate"})
+		reconciler := createBaseReconciler(source, target)
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+		workload := &workloads.DeploymentWorkload{Deployment: source}
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), workload)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: defaultRequeuAfter}, result)
+		assert.Equal(t, `Warning InvalidPostRestartCheck Post-restart check invalid after restart by "Deployment/default/source": invalid annotation "cascader.tkb.ch/post-restart-check": missing threshold in "cascader.tkb.ch/post-restart-check-threshold"`, <-recorder.Events)
+		assert.Equal(t, `Warning InvalidPostRestartCheck Cascade paused, post-restart check of Deployment/default/checked invalid: invalid annotation "cascader.tkb.ch/post-restart-check": missing threshold in "cascader.tkb.ch/post-restart-check-threshold"`, <-recorder.Events)
+
+		state, err := loadVerificationState(source)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Deployment/default/checked"}, state.Failing)
+
+		_, err = reconciler.ReconcileWorkload(t.Context(), workload)
+		require.NoError(t, err)
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("Waits for target to become stable", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{
+			"cascader.tkb.ch/deployment":       "checked",
+			flag.PendingVerificationAnnotation: `{"generation":1,"since":"` + time.Now().Format(time.RFC3339) + `","targets":["Deployment/default/checked"]}`,
+		})
+		target := newVerifiedTarget("checked", "error_rate")
+		target.Status.UpdatedReplicas = 0
+		reconciler := createBaseReconciler(source, target)
+		reconciler.Prometheus = fakeQuerier{}
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: defaultRequeuAfter}, result)
+
+		state, err := loadVerificationState(source)
+		require.NoError(t, err)
+		assert.Empty(t, state.Failing)
+	})
+
+	t.Run("Gives up after timeout", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{
+			"cascader.tkb.ch/deployment":       "checked",
+			flag.PendingVerificationAnnotation: `{"generation":1,"since":"` + time.Now().Add(-time.Hour).Format(time.RFC3339) + `","targets":["Deployment/default/checked"]}`,
+		})
+		reconciler := createBaseReconciler(source, newVerifiedTarget("checked", "error_rate"))
+		reconciler.PostRestartCheckTimeout = 10 * time.Minute
+		recorder := events.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.Equal(t, "Warning PostRestartCheckFailed Post-restart checks did not pass within 10m0s: Deployment/default/checked", <-recorder.Events)
+		assert.NotContains(t, source.Annotations, flag.PendingVerificationAnnotation)
+	})
+
+	t.Run("New restart supersedes verification", func(t *testing.T) {
+		t.Parallel()
+
+		source := newStableDeployment("source", map[string]string{
+			"cascader.tkb.ch/deployment":       "unchecked",
+			flag.PendingVerificationAnnotation: `{"generation":0,"since":"` + time.Now().Format(time.RFC3339) + `","targets":["Deployment/default/checked"]}`,
+		})
+		target := newStableDeployment("unchecked", nil)
+		reconciler := createBaseReconciler(source, target)
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: source})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.NotContains(t, source.Annotations, flag.PendingVerificationAnnotation)
+
+		updatedTarget := &appsv1.Deployment{}
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(target), updatedTarget))
+		assert.Contains(t, updatedTarget.Spec.Template.Annotations, flag.LastObservedRestartAnnotation)
+	})
+}
+
+func TestReconcileWorkload_VerificationHold(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Failed check holds downstream cascade", func(t *testing.T) {
+		t.Parallel()
+
+		a := newStableDeployment("a", map[string]string{"cascader.tkb.ch/deployment": "b"})
+		b := newStableDeployment("b", map[string]string{
+			"cascader.tkb.ch/deployment":        "c",
+			flag.PostRestartCheckAnnotation:     "error_rate",
+			flag.PostRestartThresholdAnnotation: "<0.05",
+		})
+		c := newStableDeployment("c", nil)
+		reconciler := createBaseReconciler(a, b, c)
+		reconciler.Prometheus = fakeQuerier{"error_rate": 0.5}
+
+		// A restarts B and holds its cascade.
+		_, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: a})
+		require.NoError(t, err)
+		require.True(t, restarted(t, reconciler.KubeClient, b))
+		rollOut(t, reconciler.KubeClient, "b")
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(b), b))
+		assert.Equal(t, `{"source":"Deployment/default/a","generation":2}`, b.Annotations[flag.VerificationHoldAnnotation])
+
+		// The post-restart check of B fails.
+		_, err = reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: a})
+		require.NoError(t, err)
+
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(b), b))
+		hold, err := loadVerificationHold(b)
+		require.NoError(t, err)
+		require.NotNil(t, hold)
+		assert.Equal(t, "value 0.5 does not satisfy <0.05", hold.Failed)
+
+		// B does not restart C while held.
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: b})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: defaultRequeuAfter}, result)
+		assert.False(t, restarted(t, reconciler.KubeClient, c))
+
+		// Once the check passes, A releases B, which restarts C.
+		reconciler.Prometheus = fakeQuerier{"error_rate": 0}
+		_, err = reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: a})
+		require.NoError(t, err)
+
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(b), b))
+		assert.NotContains(t, b.Annotations, flag.VerificationHoldAnnotation)
+		_, err = reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: b})
+		require.NoError(t, err)
+		assert.True(t, restarted(t, reconciler.KubeClient, c))
+	})
+
+	t.Run("Timed out check aborts downstream cascade", func(t *testing.T) {
+		t.Parallel()
+
+		a := newStableDeployment("a", map[string]string{
+			"cascader.tkb.ch/deployment":       "b",
+			flag.PendingVerificationAnnotation: `{"generation":1,"since":"` + time.Now().Add(-time.Hour).Format(time.RFC3339) + `","targets":["Deployment/default/b"]}`,
+		})
+		b := newStableDeployment("b", map[string]string{
+			"cascader.tkb.ch/deployment":        "c",
+			flag.PostRestartCheckAnnotation:     "error_rate",
+			flag.PostRestartThresholdAnnotation: "<0.05",
+			flag.VerificationHoldAnnotation:     `{"source":"Deployment/default/a","generation":1}`,
+		})
+		b.Spec.Template.Annotations = map[string]string{flag.LastObservedRestartAnnotation: time.Now().Format(time.RFC3339)}
+		c := newStableDeployment("c", nil)
+		reconciler := createBaseReconciler(a, b, c)
+		reconciler.PostRestartCheckTimeout = 10 * time.Minute
+
+		_, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: a})
+		require.NoError(t, err)
+
+		require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(b), b))
+		hold, err := loadVerificationHold(b)
+		require.NoError(t, err)
+		require.NotNil(t, hold)
+		assert.True(t, hold.Aborted)
+
+		result, err := reconciler.ReconcileWorkload(t.Context(), &workloads.DeploymentWorkload{Deployment: b})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		assert.False(t, restarted(t, reconciler.KubeClient, c))
+		assert.NotContains(t, b.Annotations, flag.VerificationHoldAnnotation)
+	})
+}
+
+func TestCheckVerificationHold(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		hold     string
+		expected gateResult
+		released bool
+	}{
+		{
+			name:     "Not held",
+			expected: gateResult{},
+		},
+		{
+			name:     "Awaiting check",
+			hold:     `{"source":"Deployment/default/a","generation":1}`,
+			expected: gateResult{Pending: "awaiting post-restart check by Deployment/default/a", Requeue: time.Minute},
+		},
+		{
+			name:     "Restart not applied yet",
+			hold:     `{"source":"Deployment/default/a","generation":2}`,
+			expected: gateResult{Pending: "awaiting post-restart check by Deployment/default/a", Requeue: time.Minute},
+		},
+		{
+			name:     "Check failed",
+			hold:     `{"source":"Deployment/default/a","generation":1,"failed":"value 0.5 does not satisfy <0.05"}`,
+			expected: gateResult{Pending: "post-restart check by Deployment/default/a failed: value 0.5 does not satisfy <0.05", Requeue: time.Minute},
+		},
+		{
+			name:     "Check aborted",
+			hold:     `{"source":"Deployment/default/a","generation":1,"failed":"timed out after 10m0s","aborted":true}`,
+			expected: gateResult{Failed: "post-restart check by Deployment/default/a did not pass: timed out after 10m0s"},
+		},
+		{
+			name:     "Hold of earlier restart",
+			hold:     `{"source":"Deployment/default/a","generation":0}`,
+			expected: gateResult{},
+			released: true,
+		},
+		{
+			name:     "Invalid hold",
+			hold:     `{`,
+			expected: gateResult{},
+			released: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			annotations := map[string]string{}
+			if tt.hold != "" {
+				annotations[flag.VerificationHoldAnnotation] = tt.hold
+			}
+			dep := newStableDeployment("b", annotations)
+			reconciler := createBaseReconciler(dep)
+
+			result := reconciler.checkVerificationHold(t.Context(), &workloads.DeploymentWorkload{Deployment: dep}, time.Minute)
+			assert.Equal(t, tt.expected, result)
+
+			if tt.released {
+				updated := &appsv1.Deployment{}
+				require.NoError(t, reconciler.KubeClient.Get(t.Context(), client.ObjectKeyFromObject(dep), updated))
+				assert.NotContains(t, updated.Annotations, flag.VerificationHoldAnnotation)
+			}
+		})
+	}
+}
@@ -19,17 +19,66 @@ package flag
 import (
 	"fmt"
 	"net"
+	"net/url"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/containeroo/tinyflags"
 )
 
 const (
-	daemonSetAnnotation           string = "cascader.tkb.ch/daemonset"
-	deploymentAnnotation          string = "cascader.tkb.ch/deployment"
-	statefulSetAnnotation         string = "cascader.tkb.ch/statefulset"
-	LastObservedRestartAnnotation string = "cascader.tkb.ch/last-observed-restart"
-	requeueAfterAnnotation        string = "cascader.tkb.ch/requeue-after"
+	daemonSetAnnotation              string = "cascader.tkb.ch/daemonset"
+	deploymentAnnotation             string = "cascader.tkb.ch/deployment"
+	statefulSetAnnotation            string = "cascader.tkb.ch/statefulset"
+	jobAnnotation                    string = "cascader.tkb.ch/job"
+	cronJobAnnotation                string = "cascader.tkb.ch/cronjob"
+	LastObservedRestartAnnotation    string = "cascader.tkb.ch/last-observed-restart"
+	requeueAfterAnnotation           string = "cascader.tkb.ch/requeue-after"
+	RestartStrategyAnnotation        string = "cascader.tkb.ch/restart-strategy"
+	PendingRetryAnnotation           string = "cascader.tkb.ch/pending-retry"
+	PendingEvictionsAnnotation       string = "cascader.tkb.ch/pending-evictions"
+	MissingTargetsAnnotation         string = "cascader.tkb.ch/missing-targets"
+	MissingTargetsTimeoutAnnotation  string = "cascader.tkb.ch/missing-targets-timeout"
+	CascadeOnRecreateAnnotation      string = "cascader.tkb.ch/cascade-on-recreate"
+	ImageDigestsAnnotation           string = "cascader.tkb.ch/image-digests"
+	OnDeleteAnnotation               string = "cascader.tkb.ch/on-delete"
+	SoakAnnotation                   string = "cascader.tkb.ch/soak"
+	SoakNoRestartsAnnotation         string = "cascader.tkb.ch/soak-no-restarts"
+	ConsecutiveReadyChecksAnnotation string = "cascader.tkb.ch/consecutive-ready-checks"
+	StabilityGatesAnnotation         string = "cascader.tkb.ch/stability-gates"
+	ReadyCheckAnnotation             string = "cascader.tkb.ch/ready-check"
+	ReadyCheckTimeoutAnnotation      string = "cascader.tkb.ch/ready-check-timeout"
+	ReadyCheckStatusAnnotation       string = "cascader.tkb.ch/ready-check-status"
+	ReadyCheckRetriesAnnotation      string = "cascader.tkb.ch/ready-check-retries"
+	MetricsGateAnnotation            string = "cascader.tkb.ch/metrics-gate"
+	MetricsGateThresholdAnnotation   string = "cascader.tkb.ch/metrics-gate-threshold"
+	PostRestartCheckAnnotation       string = "cascader.tkb.ch/post-restart-check"
+	PostRestartThresholdAnnotation   string = "cascader.tkb.ch/post-restart-check-threshold"
+	PendingVerificationAnnotation    string = "cascader.tkb.ch/pending-verification"
+	VerificationHoldAnnotation       string = "cascader.tkb.ch/verification-hold"
+	DeferredTargetsAnnotation        string = "cascader.tkb.ch/deferred-targets"
+	CascadeAnnotation                string = "cascader.tkb.ch/cascade"
+	CascadeArrivalsAnnotation        string = "cascader.tkb.ch/cascade-arrivals"
+	BlastRadiusOverrideAnnotation    string = "cascader.tkb.ch/blast-radius-override"
+	RequiresApprovalAnnotation       string = "cascader.tkb.ch/requires-approval"
+	RequiresApprovalForAnnotation    string = "cascader.tkb.ch/requires-approval-for"
+	PendingApprovalAnnotation        string = "cascader.tkb.ch/pending-approval"
+	ApproveAnnotation                string = "cascader.tkb.ch/approve"
+	TargetsAnnotation                string = "cascader.tkb.ch/targets"
+	TemplateHashesAnnotation         string = "cascader.tkb.ch/template-hashes"
+	TriggeredByAnnotation            string = "cascader.tkb.ch/triggered-by"
+	ScaleFollowAnnotation            string = "cascader.tkb.ch/scale-follow"
+	ScaleFollowHPAAnnotation         string = "cascader.tkb.ch/scale-follow-hpa"
+	QuiescedAnnotation               string = "cascader.tkb.ch/quiesced"
+	QuiesceTimeoutAnnotation         string = "cascader.tkb.ch/quiesce-timeout"
+	PodRestartsAnnotation            string = "cascader.tkb.ch/pod-restarts"
+	PodRestartThresholdAnnotation    string = "cascader.tkb.ch/pod-restart-threshold"
+	ScheduleAnnotation               string = "cascader.tkb.ch/schedule"
+	ScheduleTimeZoneAnnotation       string = "cascader.tkb.ch/schedule-timezone"
+	ScheduleJitterAnnotation         string = "cascader.tkb.ch/schedule-jitter"
+	ScheduleDeadlineAnnotation       string = "cascader.tkb.ch/schedule-starting-deadline"
+	LastScheduleAnnotation           string = "cascader.tkb.ch/last-schedule"
 )
 
 // Options holds all configuration options for the application.
@@ -43,9 +92,39 @@ type Options struct {
 	DeploymentAnnotation          string         // Annotation key for monitored Deployments
 	StatefulSetAnnotation         string         // Annotation key for monitored StatefulSets
 	DaemonSetAnnotation           string         // Annotation key for monitored DaemonSets
+	JobAnnotation                 string         // Annotation key for Job targets
+	CronJobAnnotation             string         // Annotation key for CronJob targets
 	LastObservedRestartAnnotation string         // Annotation key for last observed restart
 	RequeueAfterAnnotation        string         // Annotation key for requeue interval
 	RequeueAfterDefault           time.Duration  // Default requeue interval
+	EvictionTimeout               time.Duration  // Maximum duration for evicting all pods of a target
+	MaxRetries                    int            // Maximum number of retries for failed target restarts
+	RetryBackoff                  time.Duration  // Initial backoff between retries
+	RetryBackoffMax               time.Duration  // Maximum backoff between retries
+	MissingTargets                string         // Default handling of missing targets: "skip", "wait" or "fail"
+	MissingTargetsTimeout         time.Duration  // Default duration to wait for missing targets
+	StatefulSetOnDelete           string         // Default handling of StatefulSets with the OnDelete update strategy: "immediate", "wait" or "skip"
+	PrometheusURL                 string         // Address of the Prometheus server evaluating metrics gates and post-restart checks
+	PostRestartCheckTimeout       time.Duration  // Maximum duration for restarted targets to pass their post-restart checks
+	ReadyCheckMaxTimeout          time.Duration  // Maximum timeout of a single probe of an external ready check
+	ReadyCheckAllowedHosts        []string       // Hosts external ready checks may probe besides in-cluster Services
+	WatchImageDigests             bool           // Treat changed image digests of source Pods as restarts
+	WatchPodRestarts              bool           // Treat replaced Pods and restarted containers of sources as restarts
+	PodRestartThreshold           int            // Default number of replaced Pods of a source which cascade a restart
+	ScheduleTimeZone              string         // Default time zone of restart schedules
+	ScheduleStartingDeadline      time.Duration  // Default duration after which missed schedules are skipped, 0 always runs them
+	StabilityTimeout              time.Duration  // Maximum duration for a source to become stable before its cascade is aborted
+	JoinTimeout                   time.Duration  // Maximum duration a shared dependent waits for the other upstreams of its cascade
+	StabilityResync               time.Duration  // Safety-net requeue while waiting for status updates of unstable sources
+	WatchSourcePods               bool           // Wake sources with a pending cascade on readiness changes of their Pods
+	SkipScaledDownTargets         bool           // Skip restarts of targets scaled to zero replicas
+	ScaleFollowHPA                string         // Default handling of targets managed by a HorizontalPodAutoscaler in scale-follow mode: "pause" or "skip"
+	QuiesceTimeout                time.Duration  // Maximum duration targets stay quiesced while their source is updated
+	MaxFanOut                     int            // Maximum number of direct targets per source, 0 disables the limit
+	MaxCascadeSize                int            // Maximum number of workloads restarted by a cascade, 0 disables the limit
+	MaxCascadeDepth               int            // Maximum length of a dependency chain of a cascade, 0 disables the limit
+	ApprovalTimeout               time.Duration  // Duration after which pending approvals expire, 0 disables expiry
+	TargetGroupsConfigMap         string         // ConfigMap defining named target groups as "namespace/name", empty disables them
 	EnableMetrics                 bool           // Enable or disable metrics
 	LogEncoder                    string         // Log format: "json" or "console"
 	LogStacktraceLevel            string         // Stacktrace log level
@@ -72,6 +151,12 @@ func ParseArgs(args []string, version string) (Options, error) {
 	tf.StringVar(&options.DaemonSetAnnotation, "daemonset-annotation", daemonSetAnnotation, "Annotation key for monitored DaemonSets").
 		Placeholder("ANNOTATION").
 		Value()
+	tf.StringVar(&options.JobAnnotation, "job-annotation", jobAnnotation, "Annotation key for Job targets").
+		Placeholder("ANNOTATION").
+		Value()
+	tf.StringVar(&options.CronJobAnnotation, "cronjob-annotation", cronJobAnnotation, "Annotation key for CronJob targets").
+		Placeholder("ANNOTATION").
+		Value()
 	tf.StringVar(&options.LastObservedRestartAnnotation, "last-observed-restart-annotation", LastObservedRestartAnnotation, "Annotation key for last observed restart").
 		Placeholder("ANNOTATION").
 		Value()
@@ -89,6 +174,226 @@ func ParseArgs(args []string, version string) (Options, error) {
 		Placeholder("DURATION").
 		Value()
 
+	tf.DurationVar(&options.EvictionTimeout, "eviction-timeout", 10*time.Minute, "Maximum duration for restarting a target with the evict strategy").
+		Validate(func(d time.Duration) error {
+			if d <= 0 {
+				return fmt.Errorf("eviction-timeout must be greater than 0")
+			}
+			return nil
+		}).
+		Placeholder("DURATION").
+		Value()
+
+	tf.IntVar(&options.MaxRetries, "max-retries", 5, "Maximum number of retries for targets that failed to restart (0 disables retries)").
+		Validate(func(n int) error {
+			if n < 0 {
+				return fmt.Errorf("max-retries must not be negative")
+			}
+			return nil
+		}).
+		Placeholder("COUNT").
+		Value()
+	tf.DurationVar(&options.RetryBackoff, "retry-backoff", 5*time.Second, "Initial backoff between retries, doubled on every attempt (Minimum 1 Second)").
+		Validate(func(d time.Duration) error {
+			if d < 1*time.Second {
+				return fmt.Errorf("retry-backoff must be at least 1 second")
+			}
+			return nil
+		}).
+		Placeholder("DURATION").
+		Value()
+	tf.DurationVar(&options.RetryBackoffMax, "retry-backoff-max", 5*time.Minute, "Maximum backoff between retries").
+		Placeholder("DURATION").
+		Value()
+
+	tf.StringVar(&options.MissingTargets, "missing-targets", "fail", "Default handling of targets which do not exist (skip, wait, fail)").
+		Choices("skip", "wait", "fail").
+		Value()
+	tf.DurationVar(&options.MissingTargetsTimeout, "missing-targets-timeout", 5*time.Minute, "Default duration to wait for missing targets to appear").
+		Validate(func(d time.Duration) error {
+			if d <= 0 {
+				return fmt.Errorf("missing-targets-timeout must be greater than 0")
+			}
+			return nil
+		}).
+		Placeholder("DURATION").
+		Value()
+
+	tf.StringVar(&options.StatefulSetOnDelete, "statefulset-on-delete", "wait", "Default handling of StatefulSets with the OnDelete update strategy (immediate, wait, skip)").
+		Choices("immediate", "wait", "skip").
+		Value()
+
+	tf.StringVar(&options.PrometheusURL, "prometheus-url", "", "Address of the Prometheus server evaluating metrics gates and post-restart checks").
+		Validate(func(u string) error {
+			if u == "" {
+				return nil
+			}
+			if parsed, err := url.Parse(u); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+				return fmt.Errorf("prometheus-url must be an absolute URL, got %q", u)
+			}
+			return nil
+		}).
+		Placeholder("URL").
+		Value()
+	tf.DurationVar(&options.PostRestartCheckTimeout, "post-restart-check-timeout", 10*time.Minute, "Maximum duration for restarted targets to pass their post-restart checks").
+		Validate(func(d time.Duration) error {
+			if d <= 0 {
+				return fmt.Errorf("post-restart-check-timeout must be greater than 0")
+			}
+			return nil
+		}).
+		Placeholder("DURATION").
+		Value()
+	tf.DurationVar(&options.ReadyCheckMaxTimeout, "ready-check-max-timeout", 30*time.Second, "Maximum timeout of a single probe of an external ready check").
+		Validate(func(d time.Duration) error {
+			if d <= 0 {
+				return fmt.Errorf("ready-check-max-timeout must be greater than 0")
+			}
+			return nil
+		}).
+		Placeholder("DURATION").
+		Value()
+	tf.StringSliceVar(&options.ReadyCheckAllowedHosts, "ready-check-allowed-host", nil, "Host external ready checks may probe besides in-cluster Services, \"*.example.com\" matches subdomains (can be repeated or comma-separated)").
+		Placeholder("HOST").
+		Value()
+
+	tf.DurationVar(&options.StabilityTimeout, "stability-timeout", 0, "Maximum duration for a source to become stable before its cascade is aborted (0 disables)").
+		Validate(func(d time.Duration) error {
+			if d < 0 {
+				return fmt.Errorf("stability-timeout must not be negative")
+			}
+			return nil
+		}).
+		Placeholder("DURATION").
+		Value()
+
+	tf.DurationVar(&options.JoinTimeout, "join-timeout", 30*time.Minute, "Maximum duration a shared dependent waits for other upstreams of its cascade").
+		Validate(func(d time.Duration) error {
+			if d <= 0 {
+				return fmt.Errorf("join-timeout must be greater than 0")
+			}
+			return nil
+		}).
+		Placeholder("DURATION").
+		Value()
+
+	tf.DurationVar(&options.StabilityResync, "stability-resync", time.Minute, "Safety-net requeue interval while waiting for status updates of unstable sources (0 polls with the requeue interval)").
+		Validate(func(d time.Duration) error {
+			if d < 0 {
+				return fmt.Errorf("stability-resync must not be negative")
+			}
+			return nil
+		}).
+		Placeholder("DURATION").
+		Value()
+	tf.BoolVar(&options.WatchSourcePods, "watch-source-pods", false, "Wake sources with a pending cascade on readiness changes of their Pods").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&options.SkipScaledDownTargets, "skip-scaled-down-targets", false, "Skip restarts of targets scaled to zero replicas").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.StringVar(&options.ScaleFollowHPA, "scale-follow-hpa", "skip", "Default handling of targets managed by a HorizontalPodAutoscaler in scale-follow mode (pause, skip)").
+		Choices("pause", "skip").
+		Value()
+	tf.DurationVar(&options.QuiesceTimeout, "quiesce-timeout", 30*time.Minute, "Maximum duration targets stay quiesced while their source is updated").
+		Validate(func(d time.Duration) error {
+			if d <= 0 {
+				return fmt.Errorf("quiesce-timeout must be greater than 0")
+			}
+			return nil
+		}).
+		Placeholder("DURATION").
+		Value()
+	tf.IntVar(&options.MaxFanOut, "max-fan-out", 0, "Maximum number of direct targets per source (0 disables the limit)").
+		Validate(func(n int) error {
+			if n < 0 {
+				return fmt.Errorf("max-fan-out must not be negative")
+			}
+			return nil
+		}).
+		Placeholder("COUNT").
+		Value()
+	tf.IntVar(&options.MaxCascadeSize, "max-cascade-size", 0, "Maximum number of workloads restarted by a cascade (0 disables the limit)").
+		Validate(func(n int) error {
+			if n < 0 {
+				return fmt.Errorf("max-cascade-size must not be negative")
+			}
+			return nil
+		}).
+		Placeholder("COUNT").
+		Value()
+	tf.IntVar(&options.MaxCascadeDepth, "max-cascade-depth", 0, "Maximum length of a dependency chain of a cascade (0 disables the limit)").
+		Validate(func(n int) error {
+			if n < 0 {
+				return fmt.Errorf("max-cascade-depth must not be negative")
+			}
+			return nil
+		}).
+		Placeholder("COUNT").
+		Value()
+	tf.DurationVar(&options.ApprovalTimeout, "approval-timeout", 24*time.Hour, "Duration after which pending approvals of restarts expire (0 disables expiry)").
+		Validate(func(d time.Duration) error {
+			if d < 0 {
+				return fmt.Errorf("approval-timeout must not be negative")
+			}
+			return nil
+		}).
+		Placeholder("DURATION").
+		Value()
+
+	tf.StringVar(&options.TargetGroupsConfigMap, "target-groups-configmap", "", "ConfigMap defining named target groups, referenced as group:<name> in target annotations").
+		Validate(func(ref string) error {
+			if ref == "" {
+				return nil
+			}
+			if ns, name, ok := strings.Cut(ref, "/"); !ok || ns == "" || name == "" || strings.Contains(name, "/") {
+				return fmt.Errorf("target-groups-configmap must be in the format namespace/name, got %q", ref)
+			}
+			return nil
+		}).
+		Placeholder("NAMESPACE/NAME").
+		Value()
+
+	tf.BoolVar(&options.WatchImageDigests, "watch-image-digests", false, "Treat changed image digests of source Pods as restarts, e.g. for mutable tags").
+		Strict().
+		HideAllowed().
+		Value()
+
+	tf.BoolVar(&options.WatchPodRestarts, "watch-pod-restarts", false, "Treat replaced Pods and restarted containers of sources as restarts").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.IntVar(&options.PodRestartThreshold, "pod-restart-threshold", 1, "Default number of replaced Pods of a source which cascade a restart").
+		Validate(func(n int) error {
+			if n < 1 {
+				return fmt.Errorf("pod-restart-threshold must be at least 1")
+			}
+			return nil
+		}).
+		Placeholder("COUNT").
+		Value()
+
+	tf.StringVar(&options.ScheduleTimeZone, "schedule-timezone", "UTC", "Default time zone of restart schedules").
+		Validate(func(name string) error {
+			if _, err := time.LoadLocation(name); err != nil {
+				return fmt.Errorf("schedule-timezone must be a valid time zone: %w", err)
+			}
+			return nil
+		}).
+		Placeholder("ZONE").
+		Value()
+	tf.DurationVar(&options.ScheduleStartingDeadline, "schedule-starting-deadline", 0, "Default duration after which missed schedules are skipped (0 always runs them)").
+		Validate(func(d time.Duration) error {
+			if d < 0 {
+				return fmt.Errorf("schedule-starting-deadline must not be negative")
+			}
+			return nil
+		}).
+		Placeholder("DURATION").
+		Value()
+
 	tf.StringSliceVar(&options.WatchNamespaces, "watch-namespace", nil, "Namespaces to watch (can be repeated or comma-separated)").
 		Placeholder("NAMESPACE").
 		Value()
@@ -133,9 +438,41 @@ func ParseArgs(args []string, version string) (Options, error) {
 		return Options{}, err
 	}
 
+	if options.RetryBackoffMax < options.RetryBackoff {
+		return Options{}, fmt.Errorf("retry-backoff-max (%s) must not be lower than retry-backoff (%s)", options.RetryBackoffMax, options.RetryBackoff)
+	}
+	if err := ValidateTargetGroupsNamespace(options.TargetGroupsConfigMap, operatorNamespace()); err != nil {
+		return Options{}, err
+	}
+
 	options.MetricsAddr = (*metricsBindAddress).String()
 	options.ProbeAddr = (*healthProbeaddress).String()
 	options.OverriddenValues = tf.OverriddenValues()
 
 	return options, nil
 }
+
+// serviceAccountNamespaceFile holds the namespace of the Pod the operator runs in.
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// operatorNamespace returns the namespace the operator runs in, or an empty string outside of a cluster.
+func operatorNamespace() string {
+	data, err := os.ReadFile(serviceAccountNamespaceFile)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// ValidateTargetGroupsNamespace ensures the target groups ConfigMap is in the namespace of the operator,
+// since the operator is only granted access to ConfigMaps in its own namespace. The check is skipped if
+// the namespace of the operator is unknown, e.g. when running outside of a cluster.
+func ValidateTargetGroupsNamespace(ref, operatorNS string) error {
+	if ref == "" || operatorNS == "" {
+		return nil
+	}
+	if ns, _, _ := strings.Cut(ref, "/"); ns != operatorNS {
+		return fmt.Errorf("target-groups-configmap must be in the namespace of Cascader (%s), got %q", operatorNS, ref)
+	}
+	return nil
+}
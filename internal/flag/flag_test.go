@@ -62,9 +62,39 @@ func TestParseArgs(t *testing.T) {
 		assert.Equal(t, "cascader.tkb.ch/deployment", opts.DeploymentAnnotation)
 		assert.Equal(t, "cascader.tkb.ch/statefulset", opts.StatefulSetAnnotation)
 		assert.Equal(t, "cascader.tkb.ch/daemonset", opts.DaemonSetAnnotation)
+		assert.Equal(t, "cascader.tkb.ch/job", opts.JobAnnotation)
+		assert.Equal(t, "cascader.tkb.ch/cronjob", opts.CronJobAnnotation)
 		assert.Equal(t, "cascader.tkb.ch/requeue-after", opts.RequeueAfterAnnotation)
 		assert.Equal(t, "cascader.tkb.ch/last-observed-restart", opts.LastObservedRestartAnnotation)
 		assert.Equal(t, 5*time.Second, opts.RequeueAfterDefault)
+		assert.Equal(t, 10*time.Minute, opts.EvictionTimeout)
+		assert.Equal(t, 5, opts.MaxRetries)
+		assert.Equal(t, 5*time.Second, opts.RetryBackoff)
+		assert.Equal(t, 5*time.Minute, opts.RetryBackoffMax)
+		assert.Equal(t, "fail", opts.MissingTargets)
+		assert.Equal(t, 5*time.Minute, opts.MissingTargetsTimeout)
+		assert.Equal(t, "wait", opts.StatefulSetOnDelete)
+		assert.Empty(t, opts.PrometheusURL)
+		assert.Equal(t, 10*time.Minute, opts.PostRestartCheckTimeout)
+		assert.Equal(t, 30*time.Second, opts.ReadyCheckMaxTimeout)
+		assert.Empty(t, opts.ReadyCheckAllowedHosts)
+		assert.False(t, opts.WatchImageDigests)
+		assert.False(t, opts.WatchPodRestarts)
+		assert.Equal(t, 1, opts.PodRestartThreshold)
+		assert.Equal(t, "UTC", opts.ScheduleTimeZone)
+		assert.Zero(t, opts.ScheduleStartingDeadline)
+		assert.Zero(t, opts.StabilityTimeout)
+		assert.Equal(t, 30*time.Minute, opts.JoinTimeout)
+		assert.Equal(t, time.Minute, opts.StabilityResync)
+		assert.False(t, opts.WatchSourcePods)
+		assert.False(t, opts.SkipScaledDownTargets)
+		assert.Equal(t, "skip", opts.ScaleFollowHPA)
+		assert.Equal(t, 30*time.Minute, opts.QuiesceTimeout)
+		assert.Zero(t, opts.MaxFanOut)
+		assert.Zero(t, opts.MaxCascadeSize)
+		assert.Zero(t, opts.MaxCascadeDepth)
+		assert.Equal(t, 24*time.Hour, opts.ApprovalTimeout)
+		assert.Empty(t, opts.TargetGroupsConfigMap)
 		assert.Equal(t, ":8443", opts.MetricsAddr)
 		assert.Equal(t, ":8081", opts.ProbeAddr)
 		assert.True(t, opts.LeaderElection)
@@ -83,9 +113,39 @@ func TestParseArgs(t *testing.T) {
 			"--deployment-annotation", "custom.deployment",
 			"--statefulset-annotation", "custom.statefulset",
 			"--daemonset-annotation", "custom.daemonset",
+			"--job-annotation", "custom.job",
+			"--cronjob-annotation", "custom.cronjob",
 			"--last-observed-restart-annotation", "custom.last-observed-restart",
 			"--requeue-after-annotation", "custom.requeue-after",
 			"--requeue-after-default", "10s",
+			"--eviction-timeout", "2m",
+			"--max-retries", "3",
+			"--retry-backoff", "2s",
+			"--retry-backoff-max", "1m",
+			"--missing-targets", "wait",
+			"--missing-targets-timeout", "30s",
+			"--statefulset-on-delete", "immediate",
+			"--prometheus-url", "http://prometheus.monitoring:9090",
+			"--post-restart-check-timeout", "2m",
+			"--ready-check-max-timeout", "10s",
+			"--ready-check-allowed-host", "status.example.com,*.internal.example.com",
+			"--watch-image-digests=true",
+			"--watch-pod-restarts=true",
+			"--pod-restart-threshold", "2",
+			"--schedule-timezone", "Europe/Zurich",
+			"--schedule-starting-deadline", "2h",
+			"--stability-timeout", "10m",
+			"--join-timeout", "1h",
+			"--stability-resync", "0s",
+			"--watch-source-pods=true",
+			"--skip-scaled-down-targets=true",
+			"--scale-follow-hpa", "pause",
+			"--quiesce-timeout", "1h",
+			"--max-fan-out", "10",
+			"--max-cascade-size", "50",
+			"--max-cascade-depth", "4",
+			"--approval-timeout", "1h",
+			"--target-groups-configmap", "cascader-system/target-groups",
 			"--metrics-bind-address", ":9090",
 			"--health-probe-bind-address", ":9091",
 			"--leader-elect=true",
@@ -103,9 +163,39 @@ func TestParseArgs(t *testing.T) {
 		assert.Equal(t, "custom.deployment", opts.DeploymentAnnotation)
 		assert.Equal(t, "custom.statefulset", opts.StatefulSetAnnotation)
 		assert.Equal(t, "custom.daemonset", opts.DaemonSetAnnotation)
+		assert.Equal(t, "custom.job", opts.JobAnnotation)
+		assert.Equal(t, "custom.cronjob", opts.CronJobAnnotation)
 		assert.Equal(t, "custom.last-observed-restart", opts.LastObservedRestartAnnotation)
 		assert.Equal(t, "custom.requeue-after", opts.RequeueAfterAnnotation)
 		assert.Equal(t, 10*time.Second, opts.RequeueAfterDefault)
+		assert.Equal(t, 2*time.Minute, opts.EvictionTimeout)
+		assert.Equal(t, 3, opts.MaxRetries)
+		assert.Equal(t, 2*time.Second, opts.RetryBackoff)
+		assert.Equal(t, time.Minute, opts.RetryBackoffMax)
+		assert.Equal(t, "wait", opts.MissingTargets)
+		assert.Equal(t, 30*time.Second, opts.MissingTargetsTimeout)
+		assert.Equal(t, "immediate", opts.StatefulSetOnDelete)
+		assert.Equal(t, "http://prometheus.monitoring:9090", opts.PrometheusURL)
+		assert.Equal(t, 2*time.Minute, opts.PostRestartCheckTimeout)
+		assert.Equal(t, 10*time.Second, opts.ReadyCheckMaxTimeout)
+		assert.Equal(t, []string{"status.example.com", "*.internal.example.com"}, opts.ReadyCheckAllowedHosts)
+		assert.True(t, opts.WatchImageDigests)
+		assert.True(t, opts.WatchPodRestarts)
+		assert.Equal(t, 2, opts.PodRestartThreshold)
+		assert.Equal(t, "Europe/Zurich", opts.ScheduleTimeZone)
+		assert.Equal(t, 2*time.Hour, opts.ScheduleStartingDeadline)
+		assert.Equal(t, 10*time.Minute, opts.StabilityTimeout)
+		assert.Equal(t, time.Hour, opts.JoinTimeout)
+		assert.Zero(t, opts.StabilityResync)
+		assert.True(t, opts.WatchSourcePods)
+		assert.True(t, opts.SkipScaledDownTargets)
+		assert.Equal(t, "pause", opts.ScaleFollowHPA)
+		assert.Equal(t, time.Hour, opts.QuiesceTimeout)
+		assert.Equal(t, 10, opts.MaxFanOut)
+		assert.Equal(t, 50, opts.MaxCascadeSize)
+		assert.Equal(t, 4, opts.MaxCascadeDepth)
+		assert.Equal(t, time.Hour, opts.ApprovalTimeout)
+		assert.Equal(t, "cascader-system/target-groups", opts.TargetGroupsConfigMap)
 		assert.Equal(t, ":9090", opts.MetricsAddr)
 		assert.Equal(t, ":9091", opts.ProbeAddr)
 		assert.True(t, opts.LeaderElection)
@@ -127,6 +217,128 @@ func TestParseArgs(t *testing.T) {
 		assert.EqualError(t, err, "unknown flag --invalid-flag")
 	})
 
+	t.Run("Retry backoff max lower than backoff", func(t *testing.T) {
+		t.Parallel()
+
+		args := []string{"--retry-backoff", "1m", "--retry-backoff-max", "10s"}
+		_, err := ParseArgs(args, "0.0.0")
+
+		require.Error(t, err)
+		assert.EqualError(t, err, "retry-backoff-max (10s) must not be lower than retry-backoff (1m0s)")
+	})
+
+	t.Run("Invalid statefulset on-delete mode", func(t *testing.T) {
+		t.Parallel()
+
+		args := []string{"--statefulset-on-delete", "never"}
+		_, err := ParseArgs(args, "0.0.0")
+
+		require.Error(t, err)
+	})
+
+	t.Run("Invalid prometheus url", func(t *testing.T) {
+		t.Parallel()
+
+		args := []string{"--prometheus-url", "prometheus:9090/api"}
+		_, err := ParseArgs(args, "0.0.0")
+
+		require.Error(t, err)
+	})
+
+	t.Run("Negative blast radius limit", func(t *testing.T) {
+		t.Parallel()
+
+		for _, name := range []string{"--max-fan-out", "--max-cascade-size", "--max-cascade-depth"} {
+			_, err := ParseArgs([]string{name, "-1"}, "0.0.0")
+			require.Error(t, err, name)
+		}
+	})
+
+	t.Run("Negative approval timeout", func(t *testing.T) {
+		t.Parallel()
+
+		args := []string{"--approval-timeout", "-1h"}
+		_, err := ParseArgs(args, "0.0.0")
+
+		require.Error(t, err)
+	})
+
+	t.Run("Invalid quiesce timeout", func(t *testing.T) {
+		t.Parallel()
+
+		for _, d := range []string{"0s", "-1m"} {
+			_, err := ParseArgs([]string{"--quiesce-timeout", d}, "0.0.0")
+			require.Error(t, err, d)
+		}
+	})
+
+	t.Run("Invalid join timeout", func(t *testing.T) {
+		t.Parallel()
+
+		for _, d := range []string{"0s", "-1m"} {
+			_, err := ParseArgs([]string{"--join-timeout", d}, "0.0.0")
+			require.Error(t, err, d)
+		}
+	})
+
+	t.Run("Invalid pod restart threshold", func(t *testing.T) {
+		t.Parallel()
+
+		args := []string{"--pod-restart-threshold", "0"}
+		_, err := ParseArgs(args, "0.0.0")
+
+		require.Error(t, err)
+	})
+
+	t.Run("Invalid schedule time zone", func(t *testing.T) {
+		t.Parallel()
+
+		args := []string{"--schedule-timezone", "Mars/Olympus_Mons"}
+		_, err := ParseArgs(args, "0.0.0")
+
+		require.Error(t, err)
+	})
+
+	t.Run("Invalid schedule starting deadline", func(t *testing.T) {
+		t.Parallel()
+
+		args := []string{"--schedule-starting-deadline", "-1m"}
+		_, err := ParseArgs(args, "0.0.0")
+
+		require.Error(t, err)
+	})
+
+	t.Run("Invalid target groups ConfigMap", func(t *testing.T) {
+		t.Parallel()
+
+		for _, ref := range []string{"target-groups", "/target-groups", "cascader-system/", "a/b/c"} {
+			_, err := ParseArgs([]string{"--target-groups-configmap", ref}, "0.0.0")
+			require.Error(t, err, ref)
+		}
+	})
+
+	t.Run("Target groups outside of the operator namespace", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, ValidateTargetGroupsNamespace("", "cascader-system"))
+		assert.NoError(t, ValidateTargetGroupsNamespace("cascader-system/target-groups", "cascader-system"))
+		assert.NoError(t, ValidateTargetGroupsNamespace("other/target-groups", ""))
+		assert.EqualError(
+			t,
+			ValidateTargetGroupsNamespace("other/target-groups", "cascader-system"),
+			`target-groups-configmap must be in the namespace of Cascader (cascader-system), got "other/target-groups"`,
+		)
+	})
+
+	t.Run("Invalid missing targets mode", func(t *testing.T) {
+		t.Parallel()
+
+		args := []string{"--missing-targets", "ignore"}
+		_, err := ParseArgs(args, "0.0.0")
+
+		require.Error(t, err)
+	})
+
 	t.Run("Test Usage", func(t *testing.T) {
 		t.Parallel()
 
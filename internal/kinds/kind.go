@@ -23,8 +23,10 @@ type AnnotationKindMap map[string]Kind
 type Kind string
 
 const (
+	CronJobKind     Kind = "CronJob"     // Represents a Kubernetes CronJob resource, supported as target only.
 	DaemonSetKind   Kind = "DaemonSet"   // Represents a Kubernetes DaemonSet resource.
 	DeploymentKind  Kind = "Deployment"  // Represents a Kubernetes Deployment resource.
+	JobKind         Kind = "Job"         // Represents a Kubernetes Job resource.
 	StatefulSetKind Kind = "StatefulSet" // Represents a Kubernetes StatefulSet resource.
 )
 
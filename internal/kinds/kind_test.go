@@ -45,4 +45,18 @@ func TestKindString(t *testing.T) {
 		kind := DaemonSetKind.String()
 		assert.Equal(t, "DaemonSet", kind)
 	})
+
+	t.Run("JobKind", func(t *testing.T) {
+		t.Parallel()
+
+		kind := JobKind.String()
+		assert.Equal(t, "Job", kind)
+	})
+
+	t.Run("CronJobKind", func(t *testing.T) {
+		t.Parallel()
+
+		kind := CronJobKind.String()
+		assert.Equal(t, "CronJob", kind)
+	})
 }
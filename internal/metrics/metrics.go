@@ -31,6 +31,15 @@ type Registry struct {
 	dependencyCyclesDetected *prometheus.GaugeVec
 	workingTargets           *prometheus.GaugeVec
 	restartsPerformed        *prometheus.CounterVec
+	restartRetries           *prometheus.CounterVec
+	missingTargets           *prometheus.GaugeVec
+	cascadesResumed          *prometheus.CounterVec
+	cascadesAborted          *prometheus.CounterVec
+	targetPreflights         *prometheus.CounterVec
+	pendingApprovals         *prometheus.GaugeVec
+	scaleFollows             *prometheus.CounterVec
+	quiesces                 *prometheus.CounterVec
+	scheduledRestarts        *prometheus.CounterVec
 }
 
 // NewRegistry creates and registers all AutoVPA metrics with the provided
@@ -64,13 +73,107 @@ func NewRegistry(reg prometheus.Registerer) *Registry {
 		[]string{"namespace", "name", "resource_kind"},
 	)
 
-	reg.MustRegister(dependencyCyclesDetected, workloadTargets, restartsPerformed)
+	restartRetries := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cascader_restart_retries_total",
+			Help: "Total number of retried restarts of targets that previously failed to restart.",
+		},
+		[]string{"namespace", "name", "resource_kind"},
+	)
+
+	missingTargets := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cascader_missing_targets",
+			Help: "Number of targets referenced in a workload's annotations which do not exist.",
+		},
+		[]string{"namespace", "name", "resource_kind"},
+	)
+
+	cascadesResumed := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cascader_cascades_resumed_total",
+			Help: "Total number of in-flight cascades resumed after an operator restart or leader failover.",
+		},
+		[]string{"namespace", "name", "resource_kind"},
+	)
+
+	cascadesAborted := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cascader_cascades_aborted_total",
+			Help: "Total number of cascades aborted because the source workload failed to become stable.",
+		},
+		[]string{"namespace", "name", "resource_kind"},
+	)
+
+	targetPreflights := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cascader_target_preflight_total",
+			Help: "Total number of preflight decisions taken before restarting a target, by decision.",
+		},
+		[]string{"namespace", "name", "resource_kind", "decision"},
+	)
+
+	pendingApprovals := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cascader_pending_approvals",
+			Help: "Indicates whether a restart of a target is waiting for manual approval (1 = pending, 0 = none).",
+		},
+		[]string{"namespace", "name", "resource_kind"},
+	)
+
+	scaleFollows := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cascader_scale_follow_total",
+			Help: "Total number of targets scaled with their source in scale-follow mode, by action.",
+		},
+		[]string{"namespace", "name", "resource_kind", "action"},
+	)
+
+	quiesces := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cascader_quiesce_total",
+			Help: "Total number of targets quiesced while their source was updated, by action.",
+		},
+		[]string{"namespace", "name", "resource_kind", "action"},
+	)
+
+	scheduledRestarts := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cascader_scheduled_restarts_total",
+			Help: "Total number of scheduled restarts of source workloads, by action.",
+		},
+		[]string{"namespace", "name", "resource_kind", "action"},
+	)
+
+	reg.MustRegister(
+		dependencyCyclesDetected,
+		workloadTargets,
+		restartsPerformed,
+		restartRetries,
+		missingTargets,
+		cascadesResumed,
+		cascadesAborted,
+		targetPreflights,
+		pendingApprovals,
+		scaleFollows,
+		quiesces,
+		scheduledRestarts,
+	)
 
 	return &Registry{
 		reg:                      reg,
 		dependencyCyclesDetected: dependencyCyclesDetected,
 		workingTargets:           workloadTargets,
 		restartsPerformed:        restartsPerformed,
+		restartRetries:           restartRetries,
+		missingTargets:           missingTargets,
+		cascadesResumed:          cascadesResumed,
+		cascadesAborted:          cascadesAborted,
+		targetPreflights:         targetPreflights,
+		pendingApprovals:         pendingApprovals,
+		scaleFollows:             scaleFollows,
+		quiesces:                 quiesces,
+		scheduledRestarts:        scheduledRestarts,
 	}
 }
 
@@ -88,3 +191,48 @@ func (r *Registry) SetWorkloadTargets(namespace, name, kind string, value float6
 func (r *Registry) IncRestartsPerformed(namespace, name, kind string) {
 	r.restartsPerformed.WithLabelValues(namespace, name, kind).Inc()
 }
+
+// IncRestartRetries increments the total number of retried target restarts.
+func (r *Registry) IncRestartRetries(namespace, name, kind string) {
+	r.restartRetries.WithLabelValues(namespace, name, kind).Inc()
+}
+
+// SetMissingTargets sets the number of targets referenced by a workload which do not exist.
+func (r *Registry) SetMissingTargets(namespace, name, kind string, value float64) {
+	r.missingTargets.WithLabelValues(namespace, name, kind).Set(value)
+}
+
+// IncCascadesResumed increments the total number of resumed in-flight cascades.
+func (r *Registry) IncCascadesResumed(namespace, name, kind string) {
+	r.cascadesResumed.WithLabelValues(namespace, name, kind).Inc()
+}
+
+// IncCascadesAborted increments the total number of aborted cascades.
+func (r *Registry) IncCascadesAborted(namespace, name, kind string) {
+	r.cascadesAborted.WithLabelValues(namespace, name, kind).Inc()
+}
+
+// IncTargetPreflight increments the total number of preflight decisions taken for a target.
+func (r *Registry) IncTargetPreflight(namespace, name, kind, decision string) {
+	r.targetPreflights.WithLabelValues(namespace, name, kind, decision).Inc()
+}
+
+// SetPendingApproval sets whether a restart of the given target is waiting for manual approval.
+func (r *Registry) SetPendingApproval(namespace, name, kind string, value float64) {
+	r.pendingApprovals.WithLabelValues(namespace, name, kind).Set(value)
+}
+
+// IncScaleFollow increments the total number of times a target was scaled with its source.
+func (r *Registry) IncScaleFollow(namespace, name, kind, action string) {
+	r.scaleFollows.WithLabelValues(namespace, name, kind, action).Inc()
+}
+
+// IncQuiesce increments the total number of times a target was quiesced or restored while its source was updated.
+func (r *Registry) IncQuiesce(namespace, name, kind, action string) {
+	r.quiesces.WithLabelValues(namespace, name, kind, action).Inc()
+}
+
+// IncScheduledRestart increments the total number of times a scheduled restart of a source was performed or skipped.
+func (r *Registry) IncScheduledRestart(namespace, name, kind, action string) {
+	r.scheduledRestarts.WithLabelValues(namespace, name, kind, action).Inc()
+}
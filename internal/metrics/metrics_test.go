@@ -47,6 +47,15 @@ func resetAll(r *Registry) {
 	r.dependencyCyclesDetected.Reset()
 	r.workingTargets.Reset()
 	r.restartsPerformed.Reset()
+	r.restartRetries.Reset()
+	r.missingTargets.Reset()
+	r.cascadesResumed.Reset()
+	r.cascadesAborted.Reset()
+	r.targetPreflights.Reset()
+	r.pendingApprovals.Reset()
+	r.scaleFollows.Reset()
+	r.quiesces.Reset()
+	r.scheduledRestarts.Reset()
 }
 
 func TestRegistryMetrics_AllMethods(t *testing.T) {
@@ -78,5 +87,82 @@ func TestRegistryMetrics_AllMethods(t *testing.T) {
 			val := testutil.ToFloat64(r.restartsPerformed.WithLabelValues("ns1", "demo", "Deployment"))
 			assert.Equal(t, float64(1), val)
 		})
+
+		t.Run("IncRestartRetries increments", func(t *testing.T) {
+			resetAll(r)
+
+			r.IncRestartRetries("ns1", "demo", "Deployment")
+			val := testutil.ToFloat64(r.restartRetries.WithLabelValues("ns1", "demo", "Deployment"))
+			assert.Equal(t, float64(1), val)
+		})
+
+		t.Run("SetMissingTargets sets", func(t *testing.T) {
+			resetAll(r)
+
+			r.SetMissingTargets("ns1", "demo", "Deployment", 2)
+			val := testutil.ToFloat64(r.missingTargets.WithLabelValues("ns1", "demo", "Deployment"))
+			assert.Equal(t, float64(2), val)
+		})
+
+		t.Run("IncCascadesResumed increments", func(t *testing.T) {
+			resetAll(r)
+
+			r.IncCascadesResumed("ns1", "demo", "Deployment")
+			val := testutil.ToFloat64(r.cascadesResumed.WithLabelValues("ns1", "demo", "Deployment"))
+			assert.Equal(t, float64(1), val)
+		})
+
+		t.Run("IncCascadesAborted increments", func(t *testing.T) {
+			resetAll(r)
+
+			r.IncCascadesAborted("ns1", "demo", "Deployment")
+			val := testutil.ToFloat64(r.cascadesAborted.WithLabelValues("ns1", "demo", "Deployment"))
+			assert.Equal(t, float64(1), val)
+		})
+
+		t.Run("IncTargetPreflight increments per decision", func(t *testing.T) {
+			resetAll(r)
+
+			r.IncTargetPreflight("ns1", "demo", "Deployment", "deferred")
+			r.IncTargetPreflight("ns1", "demo", "Deployment", "deferred")
+			r.IncTargetPreflight("ns1", "demo", "Deployment", "restart")
+			assert.Equal(t, float64(2), testutil.ToFloat64(r.targetPreflights.WithLabelValues("ns1", "demo", "Deployment", "deferred")))
+			assert.Equal(t, float64(1), testutil.ToFloat64(r.targetPreflights.WithLabelValues("ns1", "demo", "Deployment", "restart")))
+		})
+
+		t.Run("SetPendingApproval sets", func(t *testing.T) {
+			resetAll(r)
+
+			r.SetPendingApproval("ns1", "demo", "Deployment", 1)
+			val := testutil.ToFloat64(r.pendingApprovals.WithLabelValues("ns1", "demo", "Deployment"))
+			assert.Equal(t, float64(1), val)
+		})
+
+		t.Run("IncScaleFollow increments per action", func(t *testing.T) {
+			resetAll(r)
+
+			r.IncScaleFollow("ns1", "demo", "Deployment", "scale-down")
+			r.IncScaleFollow("ns1", "demo", "Deployment", "restore")
+			assert.Equal(t, float64(1), testutil.ToFloat64(r.scaleFollows.WithLabelValues("ns1", "demo", "Deployment", "scale-down")))
+			assert.Equal(t, float64(1), testutil.ToFloat64(r.scaleFollows.WithLabelValues("ns1", "demo", "Deployment", "restore")))
+		})
+
+		t.Run("IncQuiesce increments per action", func(t *testing.T) {
+			resetAll(r)
+
+			r.IncQuiesce("ns1", "demo", "Deployment", "quiesce")
+			r.IncQuiesce("ns1", "demo", "Deployment", "timeout")
+			assert.Equal(t, float64(1), testutil.ToFloat64(r.quiesces.WithLabelValues("ns1", "demo", "Deployment", "quiesce")))
+			assert.Equal(t, float64(1), testutil.ToFloat64(r.quiesces.WithLabelValues("ns1", "demo", "Deployment", "timeout")))
+		})
+
+		t.Run("IncScheduledRestart increments per action", func(t *testing.T) {
+			resetAll(r)
+
+			r.IncScheduledRestart("ns1", "demo", "DaemonSet", "restart")
+			r.IncScheduledRestart("ns1", "demo", "DaemonSet", "skip")
+			assert.Equal(t, float64(1), testutil.ToFloat64(r.scheduledRestarts.WithLabelValues("ns1", "demo", "DaemonSet", "restart")))
+			assert.Equal(t, float64(1), testutil.ToFloat64(r.scheduledRestarts.WithLabelValues("ns1", "demo", "DaemonSet", "skip")))
+		})
 	})
 }
@@ -0,0 +1,73 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"strings"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// PodImagesChanged creates a predicate admitting Pods whose resolved container images changed.
+// Created Pods which already resolved their images are admitted as well, to record a baseline.
+func PodImagesChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return podImageIDs(e.Object) != ""
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return podImageIDs(e.ObjectOld) != podImageIDs(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// podImageIDs returns the resolved images of a Pod, or an empty string for other objects.
+func podImageIDs(obj client.Object) string {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return ""
+	}
+	return workloads.PodImageIDs(pod)
+}
+
+// ImageDigestsChanged returns true if the recorded image digests of a workload changed while
+// its pod template did not, i.e. the Pods pulled new images for unchanged (mutable) tags.
+// The annotation holds "<pod template hash>/<image digests>".
+func ImageDigestsChanged(oldObj, newObj client.Object) bool {
+	oldTemplate, oldDigests, ok := strings.Cut(oldObj.GetAnnotations()[flag.ImageDigestsAnnotation], "/")
+	if !ok {
+		return false
+	}
+	newTemplate, newDigests, ok := strings.Cut(newObj.GetAnnotations()[flag.ImageDigestsAnnotation], "/")
+	if !ok {
+		return false
+	}
+
+	return oldTemplate == newTemplate && oldDigests != newDigests
+}
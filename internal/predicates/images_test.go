@@ -0,0 +1,96 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"testing"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestPodImagesChanged(t *testing.T) {
+	t.Parallel()
+
+	newPod := func(imageID string) *corev1.Pod {
+		return &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "app", ImageID: imageID},
+		}}}
+	}
+
+	p := PodImagesChanged()
+
+	t.Run("Create with resolved images", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, p.Create(event.CreateEvent{Object: newPod("app@sha256:1")}))
+		assert.False(t, p.Create(event.CreateEvent{Object: newPod("")}))
+	})
+
+	t.Run("Update with changed images", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, p.Update(event.UpdateEvent{ObjectOld: newPod("app@sha256:1"), ObjectNew: newPod("app@sha256:2")}))
+		assert.True(t, p.Update(event.UpdateEvent{ObjectOld: newPod(""), ObjectNew: newPod("app@sha256:2")}))
+		assert.False(t, p.Update(event.UpdateEvent{ObjectOld: newPod("app@sha256:1"), ObjectNew: newPod("app@sha256:1")}))
+	})
+
+	t.Run("Delete and generic events", func(t *testing.T) {
+		t.Parallel()
+
+		assert.False(t, p.Delete(event.DeleteEvent{Object: newPod("app@sha256:1")}))
+		assert.False(t, p.Generic(event.GenericEvent{Object: newPod("app@sha256:1")}))
+	})
+}
+
+func TestImageDigestsChanged(t *testing.T) {
+	t.Parallel()
+
+	withDigests := func(value string) *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		if value != "" {
+			dep.ObjectMeta = metav1.ObjectMeta{Annotations: map[string]string{flag.ImageDigestsAnnotation: value}}
+		}
+		return dep
+	}
+
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		expected bool
+	}{
+		{name: "Digests changed, template unchanged", old: "tpl/a", new: "tpl/b", expected: true},
+		{name: "Digests and template changed", old: "tpl1/a", new: "tpl2/b", expected: false},
+		{name: "Nothing changed", old: "tpl/a", new: "tpl/a", expected: false},
+		{name: "Baseline recorded", old: "", new: "tpl/a", expected: false},
+		{name: "Annotation removed", old: "tpl/a", new: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ImageDigestsChanged(withDigests(tt.old), withDigests(tt.new)))
+		})
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"testing"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/recreation"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestRecreated(t *testing.T) {
+	t.Parallel()
+
+	newStatefulSet := func(name string, annotations map[string]string) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+		}
+	}
+
+	t.Run("Create admitted after remembered deletion", func(t *testing.T) {
+		t.Parallel()
+
+		p := Recreated(recreation.NewTracker())
+		deleted := newStatefulSet("database", map[string]string{flag.CascadeOnRecreateAnnotation: "true"})
+
+		assert.False(t, p.Create(event.CreateEvent{Object: newStatefulSet("database", nil)}))
+		assert.True(t, p.Delete(event.DeleteEvent{Object: deleted}))
+		assert.True(t, p.Create(event.CreateEvent{Object: newStatefulSet("database", nil)}))
+		assert.False(t, p.Create(event.CreateEvent{Object: newStatefulSet("other", nil)}))
+	})
+
+	t.Run("Deletion without opt-in is not remembered", func(t *testing.T) {
+		t.Parallel()
+
+		p := Recreated(recreation.NewTracker())
+
+		assert.False(t, p.Delete(event.DeleteEvent{Object: newStatefulSet("database", nil)}))
+		assert.False(t, p.Create(event.CreateEvent{Object: newStatefulSet("database", nil)}))
+	})
+
+	t.Run("Updates and generic events are ignored", func(t *testing.T) {
+		t.Parallel()
+
+		p := Recreated(recreation.NewTracker())
+		obj := newStatefulSet("database", map[string]string{flag.CascadeOnRecreateAnnotation: "true"})
+
+		assert.False(t, p.Update(event.UpdateEvent{ObjectOld: obj, ObjectNew: obj}))
+		assert.False(t, p.Generic(event.GenericEvent{Object: obj}))
+	})
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// PodRestarted creates a predicate admitting Pods which were created, deleted, restarted a container or
+// changed readiness, the latter to evaluate replaced Pods once the workload is stable again.
+func PodRestarted() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return podRestartCount(e.ObjectOld) != podRestartCount(e.ObjectNew) || podReady(e.ObjectOld) != podReady(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return true
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// podRestartCount returns the total container restarts of a Pod, or zero for other objects.
+func podRestartCount(obj client.Object) int32 {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return 0
+	}
+	return workloads.PodRestartCount(pod)
+}
+
+// PodRestartsDetected returns true if replaced Pods of a workload were recorded as a restart,
+// i.e. the number of cascades in the recorded pod restarts increased.
+func PodRestartsDetected(oldObj, newObj client.Object) bool {
+	oldRestarts, err := workloads.ParsePodRestarts(oldObj.GetAnnotations()[flag.PodRestartsAnnotation])
+	if err != nil || oldRestarts == nil {
+		return false
+	}
+	newRestarts, err := workloads.ParsePodRestarts(newObj.GetAnnotations()[flag.PodRestartsAnnotation])
+	if err != nil || newRestarts == nil {
+		return false
+	}
+
+	return newRestarts.Cascades > oldRestarts.Cascades
+}
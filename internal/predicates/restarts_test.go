@@ -0,0 +1,96 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"testing"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestPodRestarted(t *testing.T) {
+	t.Parallel()
+
+	newPod := func(restarts int32, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{Status: corev1.PodStatus{
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", RestartCount: restarts}},
+		}}
+	}
+
+	p := PodRestarted()
+
+	t.Run("Create and delete", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, p.Create(event.CreateEvent{Object: newPod(0, corev1.ConditionFalse)}))
+		assert.True(t, p.Delete(event.DeleteEvent{Object: newPod(0, corev1.ConditionTrue)}))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, p.Update(event.UpdateEvent{ObjectOld: newPod(0, corev1.ConditionTrue), ObjectNew: newPod(1, corev1.ConditionTrue)}))
+		assert.True(t, p.Update(event.UpdateEvent{ObjectOld: newPod(1, corev1.ConditionFalse), ObjectNew: newPod(1, corev1.ConditionTrue)}))
+		assert.False(t, p.Update(event.UpdateEvent{ObjectOld: newPod(1, corev1.ConditionTrue), ObjectNew: newPod(1, corev1.ConditionTrue)}))
+	})
+
+	t.Run("Generic events", func(t *testing.T) {
+		t.Parallel()
+
+		assert.False(t, p.Generic(event.GenericEvent{Object: newPod(0, corev1.ConditionTrue)}))
+	})
+}
+
+func TestPodRestartsDetected(t *testing.T) {
+	t.Parallel()
+
+	withRestarts := func(value string) *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		if value != "" {
+			dep.ObjectMeta = metav1.ObjectMeta{Annotations: map[string]string{flag.PodRestartsAnnotation: value}}
+		}
+		return dep
+	}
+
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		expected bool
+	}{
+		{name: "Cascade recorded", old: `{"template":"tpl","pods":{"a":0}}`, new: `{"template":"tpl","pods":{"b":0},"cascades":1}`, expected: true},
+		{name: "Baseline updated", old: `{"template":"tpl","pods":{"a":0}}`, new: `{"template":"tpl","pods":{"b":0}}`, expected: false},
+		{name: "Baseline recorded", old: "", new: `{"template":"tpl","pods":{"a":0}}`, expected: false},
+		{name: "Annotation removed", old: `{"template":"tpl","pods":{"a":0},"cascades":1}`, new: "", expected: false},
+		{name: "Invalid annotation", old: "invalid", new: `{"template":"tpl","pods":{"a":0},"cascades":1}`, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, PodRestartsDetected(withRestarts(tt.old), withRestarts(tt.new)))
+		})
+	}
+}
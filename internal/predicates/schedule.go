@@ -0,0 +1,67 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"github.com/thurgauerkb/cascader/internal/flag"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// scheduleAnnotations are the annotations determining when a scheduled restart is due.
+var scheduleAnnotations = []string{
+	flag.ScheduleAnnotation,
+	flag.ScheduleTimeZoneAnnotation,
+	flag.ScheduleJitterAnnotation,
+	flag.ScheduleDeadlineAnnotation,
+	flag.LastScheduleAnnotation,
+}
+
+// ScheduleChanged creates a predicate admitting workloads with a restart schedule which were created,
+// e.g. on startup, or whose schedule or last scheduled restart changed.
+func ScheduleChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return hasSchedule(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if !hasSchedule(e.ObjectNew) {
+				return false
+			}
+			oldAnnotations, newAnnotations := e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations()
+			for _, key := range scheduleAnnotations {
+				if oldAnnotations[key] != newAnnotations[key] {
+					return true
+				}
+			}
+			return false
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// hasSchedule returns true if the object has a restart schedule.
+func hasSchedule(obj client.Object) bool {
+	return obj != nil && obj.GetAnnotations()[flag.ScheduleAnnotation] != ""
+}
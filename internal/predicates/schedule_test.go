@@ -0,0 +1,94 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"testing"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestScheduleChanged(t *testing.T) {
+	t.Parallel()
+
+	withAnnotations := func(annotations map[string]string) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	}
+	scheduled := map[string]string{flag.ScheduleAnnotation: "0 3 * * *"}
+
+	p := ScheduleChanged()
+
+	t.Run("Create", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, p.Create(event.CreateEvent{Object: withAnnotations(scheduled)}))
+		assert.False(t, p.Create(event.CreateEvent{Object: withAnnotations(nil)}))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name     string
+			old      map[string]string
+			new      map[string]string
+			expected bool
+		}{
+			{name: "Schedule added", old: nil, new: scheduled, expected: true},
+			{name: "Schedule removed", old: scheduled, new: nil, expected: false},
+			{name: "Schedule changed", old: scheduled, new: map[string]string{flag.ScheduleAnnotation: "0 4 * * *"}, expected: true},
+			{
+				name:     "Time zone changed",
+				old:      scheduled,
+				new:      map[string]string{flag.ScheduleAnnotation: "0 3 * * *", flag.ScheduleTimeZoneAnnotation: "Europe/Zurich"},
+				expected: true,
+			},
+			{
+				name:     "Last schedule recorded",
+				old:      scheduled,
+				new:      map[string]string{flag.ScheduleAnnotation: "0 3 * * *", flag.LastScheduleAnnotation: "2026-10-18T03:00:00Z"},
+				expected: true,
+			},
+			{
+				name:     "Unrelated annotation changed",
+				old:      scheduled,
+				new:      map[string]string{flag.ScheduleAnnotation: "0 3 * * *", "team": "platform"},
+				expected: false,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				assert.Equal(t, tt.expected, p.Update(event.UpdateEvent{ObjectOld: withAnnotations(tt.old), ObjectNew: withAnnotations(tt.new)}))
+			})
+		}
+	})
+
+	t.Run("Delete and generic events", func(t *testing.T) {
+		t.Parallel()
+
+		assert.False(t, p.Delete(event.DeleteEvent{Object: withAnnotations(scheduled)}))
+		assert.False(t, p.Generic(event.GenericEvent{Object: withAnnotations(scheduled)}))
+	})
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// CascadePending creates an update check admitting status updates of workloads with a pending cascade,
+// i.e. workloads carrying the given last-observed-restart annotation, so that they are woken up
+// as soon as their rollout progresses instead of polling.
+func CascadePending(lastObservedRestartAnnotation string) UpdateCheck {
+	return func(oldObj, newObj client.Object) bool {
+		if _, ok := newObj.GetAnnotations()[lastObservedRestartAnnotation]; !ok {
+			return false
+		}
+		return statusChanged(oldObj, newObj)
+	}
+}
+
+// statusChanged returns true if the status of a supported workload differs between old and new objects.
+func statusChanged(oldObj, newObj client.Object) bool {
+	switch o := oldObj.(type) {
+	case *appsv1.Deployment:
+		n, ok := newObj.(*appsv1.Deployment)
+		return ok && !equality.Semantic.DeepEqual(o.Status, n.Status)
+	case *appsv1.StatefulSet:
+		n, ok := newObj.(*appsv1.StatefulSet)
+		return ok && !equality.Semantic.DeepEqual(o.Status, n.Status)
+	case *appsv1.DaemonSet:
+		n, ok := newObj.(*appsv1.DaemonSet)
+		return ok && !equality.Semantic.DeepEqual(o.Status, n.Status)
+	case *batchv1.Job:
+		n, ok := newObj.(*batchv1.Job)
+		return ok && !equality.Semantic.DeepEqual(o.Status, n.Status)
+	default:
+		return false
+	}
+}
+
+// JobFinished returns true if a Job just completed or failed. Jobs which finished before are ignored,
+// so that a finished Job triggers its targets only once.
+func JobFinished(oldObj, newObj client.Object) bool {
+	o, ok := oldObj.(*batchv1.Job)
+	if !ok {
+		return false
+	}
+	n, ok := newObj.(*batchv1.Job)
+	return ok && !workloads.JobFinished(o) && workloads.JobFinished(n)
+}
+
+// TriggeredJobFailed creates a predicate admitting Jobs triggered by Cascader which just failed,
+// so that the failure is reported on the source which triggered them.
+func TriggeredJobFailed() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if _, ok := e.ObjectNew.GetAnnotations()[flag.TriggeredByAnnotation]; !ok {
+				return false
+			}
+			o, ok := e.ObjectOld.(*batchv1.Job)
+			if !ok {
+				return false
+			}
+			n, ok := e.ObjectNew.(*batchv1.Job)
+			if !ok {
+				return false
+			}
+			oldFailed, _ := workloads.JobFailed(o)
+			newFailed, _ := workloads.JobFailed(n)
+			return !oldFailed && newFailed
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// PodReadinessChanged creates a predicate admitting Pods whose readiness changed or which were deleted.
+func PodReadinessChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return podReady(e.ObjectOld) != podReady(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return true
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// podReady reports whether the object is a Pod with a true Ready condition.
+func podReady(obj client.Object) bool {
+	pod, ok := obj.(*corev1.Pod)
+	return ok && workloads.PodReady(pod)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"testing"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestCascadePending(t *testing.T) {
+	t.Parallel()
+
+	const annotation = "cascader.tkb.ch/last-observed-restart"
+	check := CascadePending(annotation)
+
+	newDeployment := func(pending bool, ready int32) *appsv1.Deployment {
+		dep := &appsv1.Deployment{Status: appsv1.DeploymentStatus{ReadyReplicas: ready}}
+		if pending {
+			dep.Annotations = map[string]string{annotation: "2026-01-01T00:00:00Z"}
+		}
+		return dep
+	}
+
+	t.Run("Status update of pending workload", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, check(newDeployment(true, 1), newDeployment(true, 2)))
+	})
+
+	t.Run("Status unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		assert.False(t, check(newDeployment(true, 1), newDeployment(true, 1)))
+	})
+
+	t.Run("No pending cascade", func(t *testing.T) {
+		t.Parallel()
+
+		assert.False(t, check(newDeployment(false, 1), newDeployment(false, 2)))
+	})
+
+	t.Run("StatefulSet, DaemonSet and Job", func(t *testing.T) {
+		t.Parallel()
+
+		pending := metav1.ObjectMeta{Annotations: map[string]string{annotation: "2026-01-01T00:00:00Z"}}
+
+		assert.True(t, check(
+			&appsv1.StatefulSet{ObjectMeta: pending},
+			&appsv1.StatefulSet{ObjectMeta: pending, Status: appsv1.StatefulSetStatus{ReadyReplicas: 1}},
+		))
+		assert.True(t, check(
+			&appsv1.DaemonSet{ObjectMeta: pending},
+			&appsv1.DaemonSet{ObjectMeta: pending, Status: appsv1.DaemonSetStatus{NumberReady: 1}},
+		))
+		assert.True(t, check(
+			&batchv1.Job{ObjectMeta: pending},
+			&batchv1.Job{ObjectMeta: pending, Status: batchv1.JobStatus{Succeeded: 1}},
+		))
+	})
+
+	t.Run("Unsupported type", func(t *testing.T) {
+		t.Parallel()
+
+		pending := metav1.ObjectMeta{Annotations: map[string]string{annotation: "2026-01-01T00:00:00Z"}}
+
+		assert.False(t, check(&corev1.Pod{ObjectMeta: pending}, &corev1.Pod{ObjectMeta: pending}))
+	})
+}
+
+func TestJobFinished(t *testing.T) {
+	t.Parallel()
+
+	newJob := func(conditions ...batchv1.JobConditionType) *batchv1.Job {
+		job := &batchv1.Job{}
+		for _, cond := range conditions {
+			job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{Type: cond, Status: corev1.ConditionTrue})
+		}
+		return job
+	}
+
+	t.Run("Completed", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, JobFinished(newJob(), newJob(batchv1.JobSuccessCriteriaMet, batchv1.JobComplete)))
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, JobFinished(newJob(), newJob(batchv1.JobFailed)))
+	})
+
+	t.Run("Still running", func(t *testing.T) {
+		t.Parallel()
+
+		assert.False(t, JobFinished(newJob(), newJob(batchv1.JobSuspended)))
+	})
+
+	t.Run("Finished before", func(t *testing.T) {
+		t.Parallel()
+
+		assert.False(t, JobFinished(newJob(batchv1.JobComplete), newJob(batchv1.JobComplete)))
+	})
+
+	t.Run("Unsupported type", func(t *testing.T) {
+		t.Parallel()
+
+		assert.False(t, JobFinished(&appsv1.Deployment{}, &appsv1.Deployment{}))
+	})
+}
+
+func TestTriggeredJobFailed(t *testing.T) {
+	t.Parallel()
+
+	newJob := func(triggered bool, conditions ...batchv1.JobConditionType) *batchv1.Job {
+		job := &batchv1.Job{}
+		if triggered {
+			job.Annotations = map[string]string{flag.TriggeredByAnnotation: "Deployment/default/cache"}
+		}
+		for _, cond := range conditions {
+			job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{Type: cond, Status: corev1.ConditionTrue})
+		}
+		return job
+	}
+
+	p := TriggeredJobFailed()
+
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: newJob(true), ObjectNew: newJob(true, batchv1.JobFailed)}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: newJob(true, batchv1.JobFailed), ObjectNew: newJob(true, batchv1.JobFailed)}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: newJob(true), ObjectNew: newJob(true, batchv1.JobComplete)}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: newJob(false), ObjectNew: newJob(false, batchv1.JobFailed)}))
+	assert.False(t, p.Create(event.CreateEvent{Object: newJob(true, batchv1.JobFailed)}))
+	assert.False(t, p.Delete(event.DeleteEvent{Object: newJob(true, batchv1.JobFailed)}))
+	assert.False(t, p.Generic(event.GenericEvent{Object: newJob(true, batchv1.JobFailed)}))
+}
+
+func TestPodReadinessChanged(t *testing.T) {
+	t.Parallel()
+
+	newPod := func(ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+			{Type: corev1.PodReady, Status: ready},
+		}}}
+	}
+
+	p := PodReadinessChanged()
+
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: newPod(corev1.ConditionFalse), ObjectNew: newPod(corev1.ConditionTrue)}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: newPod(corev1.ConditionTrue), ObjectNew: newPod(corev1.ConditionTrue)}))
+	assert.True(t, p.Delete(event.DeleteEvent{Object: newPod(corev1.ConditionTrue)}))
+	assert.False(t, p.Create(event.CreateEvent{Object: newPod(corev1.ConditionTrue)}))
+	assert.False(t, p.Generic(event.GenericEvent{Object: newPod(corev1.ConditionTrue)}))
+}
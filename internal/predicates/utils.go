@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"hash/fnv"
 
+	"github.com/thurgauerkb/cascader/internal/flag"
 	"github.com/thurgauerkb/cascader/internal/kinds"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -28,12 +29,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// hasAnnotation returns true if obj contains any of the specified annotations.
+// hasAnnotation returns true if obj contains any of the specified annotations or the structured targets annotation.
 func hasAnnotation(obj client.Object, annotations kinds.AnnotationKindMap) bool {
 	objAnnots := obj.GetAnnotations()
 	if objAnnots == nil {
 		return false
 	}
+	if _, ok := objAnnots[flag.TargetsAnnotation]; ok {
+		return true
+	}
 	for a := range annotations {
 		if _, ok := objAnnots[a]; ok {
 			return true
@@ -53,11 +57,11 @@ func SpecChanged(oldObj, newObj client.Object) bool {
 		return false
 	}
 
-	oldHash, err := hashTemplate(*oldTpl)
+	oldHash, err := HashTemplate(*oldTpl)
 	if err != nil {
 		return false
 	}
-	newHash, err := hashTemplate(*newTpl)
+	newHash, err := HashTemplate(*newTpl)
 	if err != nil {
 		return false
 	}
@@ -79,8 +83,8 @@ func extractPodTemplate(obj client.Object) (*corev1.PodTemplateSpec, error) {
 	}
 }
 
-// hashTemplate computes a 64-bit FNV-1 hash of a PodTemplateSpec.
-func hashTemplate(t corev1.PodTemplateSpec) (string, error) {
+// HashTemplate computes a 64-bit FNV-1 hash of a PodTemplateSpec.
+func HashTemplate(t corev1.PodTemplateSpec) (string, error) {
 	data, err := json.Marshal(t)
 	if err != nil {
 		return "", fmt.Errorf("failed to serialize PodTemplateSpec: %w", err)
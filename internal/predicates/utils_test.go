@@ -19,6 +19,7 @@ package predicates
 import (
 	"testing"
 
+	"github.com/thurgauerkb/cascader/internal/flag"
 	"github.com/thurgauerkb/cascader/internal/kinds"
 	"github.com/thurgauerkb/cascader/test/testutils"
 
@@ -70,6 +71,25 @@ func TestHasAnnotation(t *testing.T) {
 		assert.False(t, result, "Expected hasAnnotation to return false for non-matching annotation")
 	})
 
+	t.Run("Object has structured targets annotation", func(t *testing.T) {
+		t.Parallel()
+
+		obj := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					flag.TargetsAnnotation: "version: v1",
+				},
+			},
+		}
+
+		annotationKindMap := kinds.AnnotationKindMap{
+			"desired-annotation": "",
+		}
+
+		result := hasAnnotation(obj, annotationKindMap)
+		assert.True(t, result, "Expected hasAnnotation to return true for the structured targets annotation")
+	})
+
 	t.Run("Object has no annotations", func(t *testing.T) {
 		t.Parallel()
 
@@ -242,7 +262,7 @@ func TestHashTemplate(t *testing.T) {
 			},
 		}
 
-		hash, err := hashTemplate(template)
+		hash, err := HashTemplate(template)
 		assert.NoError(t, err, "Expected no error for valid PodTemplateSpec")
 		assert.NotEmpty(t, hash, "Expected non-empty hash for valid PodTemplateSpec")
 	})
@@ -253,7 +273,7 @@ func TestHashTemplate(t *testing.T) {
 		template := corev1.PodTemplateSpec{}
 		template.Annotations = nil // Ensure no annotations
 
-		hash, err := hashTemplate(template)
+		hash, err := HashTemplate(template)
 		assert.NoError(t, err, "Expected no error for valid PodTemplateSpec without annotations")
 		assert.NotEmpty(t, hash, "Expected non-empty hash for valid PodTemplateSpec without annotations")
 	})
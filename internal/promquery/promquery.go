@@ -0,0 +1,143 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+// Querier evaluates instant PromQL queries. It is the subset of the Prometheus HTTP API
+// used by Cascader, so that tests can replace it.
+type Querier interface {
+	Query(ctx context.Context, query string, ts time.Time, opts ...v1.Option) (model.Value, v1.Warnings, error)
+}
+
+// NewQuerier creates a Querier for the Prometheus server at the given address.
+func NewQuerier(address string) (Querier, error) {
+	c, err := api.NewClient(api.Config{Address: address})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Prometheus client: %w", err)
+	}
+	return v1.NewAPI(c), nil
+}
+
+// Threshold compares the result of a query against a value.
+type Threshold struct {
+	Op    string  // Op is the comparison operator: <, <=, >, >=, == or !=.
+	Value float64 // Value is the value compared against.
+}
+
+// operators are the supported comparison operators, longest first so that "<=" is not parsed as "<".
+var operators = []string{"<=", ">=", "==", "!=", "<", ">"}
+
+// ParseThreshold parses a threshold such as "<0.05", ">=0.99" or "0.05".
+// A value without operator is an upper bound, i.e. "<=".
+func ParseThreshold(value string) (Threshold, error) {
+	value = strings.TrimSpace(value)
+
+	op := "<="
+	for _, candidate := range operators {
+		if rest, ok := strings.CutPrefix(value, candidate); ok {
+			op, value = candidate, strings.TrimSpace(rest)
+			break
+		}
+	}
+
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return Threshold{}, fmt.Errorf("invalid threshold %q: expected an optional operator followed by a number", value)
+	}
+	return Threshold{Op: op, Value: v}, nil
+}
+
+// Satisfied returns true if the value satisfies the threshold. NaN never satisfies a threshold.
+func (t Threshold) Satisfied(v float64) bool {
+	if math.IsNaN(v) {
+		return false
+	}
+	switch t.Op {
+	case "<":
+		return v < t.Value
+	case "<=":
+		return v <= t.Value
+	case ">":
+		return v > t.Value
+	case ">=":
+		return v >= t.Value
+	case "==":
+		return v == t.Value
+	case "!=":
+		return v != t.Value
+	default:
+		return false
+	}
+}
+
+// String returns the threshold in its parseable form.
+func (t Threshold) String() string {
+	return t.Op + strconv.FormatFloat(t.Value, 'g', -1, 64)
+}
+
+// Evaluate runs the query and checks every sample of the result against the threshold.
+// It returns nil if all samples satisfy the threshold, or an error describing the first sample
+// which does not. Results without any sample are treated as failure.
+func Evaluate(ctx context.Context, q Querier, query string, threshold Threshold) error {
+	value, _, err := q.Query(ctx, query, time.Now())
+	if err != nil {
+		return fmt.Errorf("query %q failed: %w", query, err)
+	}
+
+	var samples []float64
+	var labels []string
+	switch v := value.(type) {
+	case *model.Scalar:
+		samples = append(samples, float64(v.Value))
+		labels = append(labels, "")
+	case model.Vector:
+		for _, s := range v {
+			samples = append(samples, float64(s.Value))
+			labels = append(labels, s.Metric.String())
+		}
+	default:
+		return fmt.Errorf("query %q returned unsupported result type %s", query, value.Type())
+	}
+
+	if len(samples) == 0 {
+		return errors.New("query returned no data")
+	}
+
+	for i, sample := range samples {
+		if !threshold.Satisfied(sample) {
+			value := strconv.FormatFloat(sample, 'g', -1, 64)
+			if labels[i] != "" && labels[i] != "{}" {
+				value += " for " + labels[i]
+			}
+			return fmt.Errorf("value %s does not satisfy %s", value, threshold)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPrometheus returns a local stand-in for the Prometheus query API, responding with the given result.
+func newPrometheus(t *testing.T, resultType, result string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":%q,"result":%s}}`, resultType, result) // nolint:errcheck
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// fakeQuerier returns a fixed result for every query.
+type fakeQuerier struct {
+	value model.Value
+	err   error
+}
+
+func (f fakeQuerier) Query(context.Context, string, time.Time, ...v1.Option) (model.Value, v1.Warnings, error) {
+	return f.value, nil, f.err
+}
+
+func TestParseThreshold(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value    string
+		expected Threshold
+		err      bool
+	}{
+		{value: "0.05", expected: Threshold{Op: "<=", Value: 0.05}},
+		{value: "<0.05", expected: Threshold{Op: "<", Value: 0.05}},
+		{value: "<= 1", expected: Threshold{Op: "<=", Value: 1}},
+		{value: ">=0.99", expected: Threshold{Op: ">=", Value: 0.99}},
+		{value: ">10", expected: Threshold{Op: ">", Value: 10}},
+		{value: "==0", expected: Threshold{Op: "==", Value: 0}},
+		{value: "!=0", expected: Threshold{Op: "!=", Value: 0}},
+		{value: "", err: true},
+		{value: "=>1", err: true},
+		{value: "<abc", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			threshold, err := ParseThreshold(tt.value)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, threshold)
+		})
+	}
+}
+
+func TestThreshold_Satisfied(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, Threshold{Op: "<", Value: 1}.Satisfied(0.5))
+	assert.False(t, Threshold{Op: "<", Value: 1}.Satisfied(1))
+	assert.True(t, Threshold{Op: "<=", Value: 1}.Satisfied(1))
+	assert.True(t, Threshold{Op: ">", Value: 1}.Satisfied(2))
+	assert.True(t, Threshold{Op: ">=", Value: 1}.Satisfied(1))
+	assert.True(t, Threshold{Op: "==", Value: 0}.Satisfied(0))
+	assert.True(t, Threshold{Op: "!=", Value: 0}.Satisfied(1))
+	assert.False(t, Threshold{Op: "<=", Value: 1}.Satisfied(math.NaN()))
+	assert.Equal(t, "<=0.05", Threshold{Op: "<=", Value: 0.05}.String())
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	threshold := Threshold{Op: "<", Value: 0.05}
+
+	t.Run("Vector satisfies threshold", func(t *testing.T) {
+		t.Parallel()
+
+		server := newPrometheus(t, "vector", `[{"metric":{"pod":"a"},"value":[1700000000,"0.01"]},{"metric":{"pod":"b"},"value":[1700000000,"0.02"]}]`)
+		q, err := NewQuerier(server.URL)
+		require.NoError(t, err)
+
+		assert.NoError(t, Evaluate(t.Context(), q, "error_rate", threshold))
+	})
+
+	t.Run("Vector violates threshold", func(t *testing.T) {
+		t.Parallel()
+
+		server := newPrometheus(t, "vector", `[{"metric":{"pod":"a"},"value":[1700000000,"0.01"]},{"metric":{"pod":"b"},"value":[1700000000,"0.2"]}]`)
+		q, err := NewQuerier(server.URL)
+		require.NoError(t, err)
+
+		err = Evaluate(t.Context(), q, "error_rate", threshold)
+		assert.EqualError(t, err, `value 0.2 for {pod="b"} does not satisfy <0.05`)
+	})
+
+	t.Run("Scalar", func(t *testing.T) {
+		t.Parallel()
+
+		server := newPrometheus(t, "scalar", `[1700000000,"0.5"]`)
+		q, err := NewQuerier(server.URL)
+		require.NoError(t, err)
+
+		err = Evaluate(t.Context(), q, "scalar(error_rate)", threshold)
+		assert.EqualError(t, err, "value 0.5 does not satisfy <0.05")
+	})
+
+	t.Run("Empty result", func(t *testing.T) {
+		t.Parallel()
+
+		server := newPrometheus(t, "vector", `[]`)
+		q, err := NewQuerier(server.URL)
+		require.NoError(t, err)
+
+		err = Evaluate(t.Context(), q, "error_rate", threshold)
+		assert.EqualError(t, err, "query returned no data")
+	})
+
+	t.Run("Unsupported result type", func(t *testing.T) {
+		t.Parallel()
+
+		server := newPrometheus(t, "matrix", `[]`)
+		q, err := NewQuerier(server.URL)
+		require.NoError(t, err)
+
+		err = Evaluate(t.Context(), q, "error_rate[5m]", threshold)
+		assert.EqualError(t, err, `query "error_rate[5m]" returned unsupported result type matrix`)
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		t.Parallel()
+
+		err := Evaluate(t.Context(), fakeQuerier{err: errors.New("connection refused")}, "error_rate", threshold)
+		assert.EqualError(t, err, `query "error_rate" failed: connection refused`)
+	})
+
+	t.Run("Prometheus unavailable", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+		q, err := NewQuerier(server.URL)
+		require.NoError(t, err)
+
+		err = Evaluate(t.Context(), q, "error_rate", threshold)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `query "error_rate" failed`)
+	})
+}
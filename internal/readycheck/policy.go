@@ -0,0 +1,91 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readycheck
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// Policy restricts the ready checks which workloads may configure with annotations, since the
+// probes are sent by the operator from within the cluster.
+type Policy struct {
+	MaxTimeout   time.Duration // MaxTimeout caps the timeout of a single probe, 0 disables the cap.
+	AllowedHosts []string      // AllowedHosts may be probed besides in-cluster Services; "*.example.com" matches all subdomains.
+}
+
+// Apply rejects checks of endpoints which are neither an in-cluster Service nor an allowed host,
+// and caps the timeout of the check. Services referenced as "<service>.<namespace>" are qualified
+// with ".svc", so that the probe cannot resolve to an external host of the same name.
+func (p Policy) Apply(c *Check) error {
+	host := strings.ToLower(strings.TrimSuffix(c.URL.Hostname(), "."))
+	if !p.allowed(host) {
+		service, ok := serviceHost(host)
+		if !ok {
+			return fmt.Errorf("endpoint %s is not an in-cluster Service (<service>.<namespace>[.svc]) or an allowed host", c)
+		}
+		if port := c.URL.Port(); port != "" {
+			service = net.JoinHostPort(service, port)
+		}
+		c.URL.Host = service
+	}
+
+	if p.MaxTimeout > 0 && c.Timeout > p.MaxTimeout {
+		c.Timeout = p.MaxTimeout
+	}
+	return nil
+}
+
+// allowed reports whether the host matches one of the allowed hosts.
+func (p Policy) allowed(host string) bool {
+	for _, pattern := range p.AllowedHosts {
+		pattern = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(pattern), "."))
+		if suffix, ok := strings.CutPrefix(pattern, "*"); ok && strings.HasPrefix(suffix, ".") {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceHost reports whether the host is the DNS name of a Service, i.e. "<service>.<namespace>" or
+// "<service>.<namespace>.svc", optionally followed by the default cluster domain. It returns the
+// host qualified with ".svc".
+func serviceHost(host string) (string, bool) {
+	if net.ParseIP(host) != nil {
+		return "", false
+	}
+
+	labels := strings.Split(strings.TrimSuffix(host, ".cluster.local"), ".")
+	if len(labels) == 2 {
+		labels = append(labels, "svc")
+	}
+	if len(labels) != 3 || labels[0] == "" || labels[1] == "" || labels[2] != "svc" {
+		return "", false
+	}
+	if strings.HasSuffix(host, ".cluster.local") {
+		return host, true
+	}
+	return strings.Join(labels, "."), true
+}
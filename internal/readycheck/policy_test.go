@@ -0,0 +1,90 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readycheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyApply(t *testing.T) {
+	t.Parallel()
+
+	policy := Policy{MaxTimeout: 10 * time.Second, AllowedHosts: []string{"status.example.com", "*.internal.example.com"}}
+
+	tests := []struct {
+		name     string
+		endpoint string
+		allowed  bool
+		probed   string
+	}{
+		{name: "Service", endpoint: "http://api.shop.svc:8080/ready", allowed: true, probed: "http://api.shop.svc:8080/ready"},
+		{name: "Service with cluster domain", endpoint: "tcp://db.shop.svc.cluster.local.:5432", allowed: true, probed: "tcp://db.shop.svc.cluster.local:5432"},
+		{name: "Service with namespace only", endpoint: "http://svc.ns:8080/ready", allowed: true, probed: "http://svc.ns.svc:8080/ready"},
+		{name: "Service with namespace only without port", endpoint: "http://api.shop/ready", allowed: true, probed: "http://api.shop.svc/ready"},
+		{name: "Allowed host", endpoint: "https://status.example.com/health", allowed: true, probed: "https://status.example.com/health"},
+		{name: "Allowed subdomain", endpoint: "https://a.internal.example.com/health", allowed: true, probed: "https://a.internal.example.com/health"},
+		{name: "Wildcard does not match the domain itself", endpoint: "https://internal.example.com/health", allowed: false},
+		{name: "External host", endpoint: "http://status.example.org/ready", allowed: false},
+		{name: "Single label", endpoint: "http://api:8080/ready", allowed: false},
+		{name: "Service suffix of external domain", endpoint: "http://api.shop.svc.example.org/ready", allowed: false},
+		{name: "Link-local address", endpoint: "http://169.254.169.254/latest/meta-data", allowed: false},
+		{name: "Loopback address", endpoint: "tcp://127.0.0.1:6443", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			check, err := Parse(tt.endpoint)
+			require.NoError(t, err)
+
+			err = policy.Apply(check)
+			if tt.allowed {
+				require.NoError(t, err)
+				assert.Equal(t, tt.probed, check.String())
+			} else {
+				assert.ErrorContains(t, err, "is not an in-cluster Service")
+			}
+		})
+	}
+
+	t.Run("Caps timeout", func(t *testing.T) {
+		t.Parallel()
+
+		check, err := Parse("http://api.shop.svc/ready")
+		require.NoError(t, err)
+		check.Timeout = time.Minute
+
+		require.NoError(t, policy.Apply(check))
+		assert.Equal(t, 10*time.Second, check.Timeout)
+	})
+
+	t.Run("Keeps shorter timeout", func(t *testing.T) {
+		t.Parallel()
+
+		check, err := Parse("http://api.shop.svc/ready")
+		require.NoError(t, err)
+		check.Timeout = 2 * time.Second
+
+		require.NoError(t, policy.Apply(check))
+		assert.Equal(t, 2*time.Second, check.Timeout)
+	})
+}
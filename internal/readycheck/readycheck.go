@@ -0,0 +1,231 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readycheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout is the timeout of a single probe if none is configured.
+const DefaultTimeout = 5 * time.Second
+
+// httpClient probes HTTP checks. Redirects are not followed, so that a probe never reaches
+// an endpoint other than the configured one; they are judged by their status code instead.
+var httpClient = &http.Client{
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// Check describes an external ready check. HTTP checks succeed if the endpoint responds with an
+// expected status code, TCP checks succeed if a connection to the endpoint can be established.
+type Check struct {
+	URL      *url.URL      // URL is the endpoint to probe, with scheme http, https or tcp.
+	Timeout  time.Duration // Timeout of a single probe.
+	Statuses StatusCodes   // Statuses are the expected status codes of HTTP checks.
+}
+
+// Parse parses the endpoint of a ready check, e.g. "http://svc.ns.svc:8080/ready" or "tcp://svc.ns.svc:5432".
+// The check uses the default timeout and expects a 2xx status code.
+func Parse(endpoint string) (*Check, error) {
+	u, err := url.Parse(strings.TrimSpace(endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid endpoint %q: missing host", endpoint)
+		}
+	case "tcp":
+		if u.Hostname() == "" || u.Port() == "" {
+			return nil, fmt.Errorf("invalid endpoint %q: expected tcp://host:port", endpoint)
+		}
+	default:
+		return nil, fmt.Errorf("invalid endpoint %q: unsupported scheme %q", endpoint, u.Scheme)
+	}
+
+	return &Check{URL: u, Timeout: DefaultTimeout, Statuses: DefaultStatusCodes()}, nil
+}
+
+// String returns the endpoint of the check.
+func (c *Check) String() string {
+	return c.URL.Redacted()
+}
+
+// Run probes the endpoint once. It returns nil if the endpoint is ready,
+// or an error describing why it is not.
+func (c *Check) Run(ctx context.Context) error {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if c.URL.Scheme == "tcp" {
+		return c.runTCP(ctx)
+	}
+	return c.runHTTP(ctx)
+}
+
+// runTCP succeeds if a connection to the endpoint can be established.
+func (c *Check) runTCP(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.URL.Host)
+	if err != nil {
+		return fmt.Errorf("tcp check %s failed: %w", c, err)
+	}
+	return conn.Close()
+}
+
+// runHTTP succeeds if the endpoint responds with an expected status code.
+func (c *Check) runHTTP(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("http check %s failed: %w", c, err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err // The URL is already part of the message.
+		}
+		return fmt.Errorf("http check %s failed: %w", c, err)
+	}
+	defer resp.Body.Close() // nolint:errcheck
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	statuses := c.Statuses
+	if len(statuses) == 0 {
+		statuses = DefaultStatusCodes()
+	}
+	if !statuses.Contains(resp.StatusCode) {
+		return fmt.Errorf("http check %s returned status %d, expected %s", c, resp.StatusCode, statuses)
+	}
+	return nil
+}
+
+// statusRange is an inclusive range of HTTP status codes.
+type statusRange struct {
+	min, max int
+}
+
+// StatusCodes is a set of expected HTTP status codes.
+type StatusCodes []statusRange
+
+// DefaultStatusCodes returns the status codes expected if none are configured, i.e. 2xx.
+func DefaultStatusCodes() StatusCodes {
+	return StatusCodes{{min: 200, max: 299}}
+}
+
+// ParseStatusCodes parses a comma-separated list of status codes, ranges and classes,
+// e.g. "200,204", "200-299" or "2xx,301".
+func ParseStatusCodes(value string) (StatusCodes, error) {
+	var codes StatusCodes
+	for part := range strings.SplitSeq(value, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+
+		r, err := parseStatusRange(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code %q: %w", part, err)
+		}
+		codes = append(codes, r)
+	}
+
+	if len(codes) == 0 {
+		return nil, errors.New("no status codes given")
+	}
+	return codes, nil
+}
+
+// parseStatusRange parses a single status code, range or class.
+func parseStatusRange(part string) (statusRange, error) {
+	if class, ok := strings.CutSuffix(part, "xx"); ok {
+		n, err := strconv.Atoi(class)
+		if err != nil || n < 1 || n > 5 {
+			return statusRange{}, errors.New("expected a class between 1xx and 5xx")
+		}
+		return statusRange{min: n * 100, max: n*100 + 99}, nil
+	}
+
+	lo, hi, isRange := strings.Cut(part, "-")
+	if !isRange {
+		hi = lo
+	}
+	minCode, err := parseStatusCode(lo)
+	if err != nil {
+		return statusRange{}, err
+	}
+	maxCode, err := parseStatusCode(hi)
+	if err != nil {
+		return statusRange{}, err
+	}
+	if minCode > maxCode {
+		return statusRange{}, errors.New("range start is greater than its end")
+	}
+	return statusRange{min: minCode, max: maxCode}, nil
+}
+
+// parseStatusCode parses a single HTTP status code.
+func parseStatusCode(value string) (int, error) {
+	code, err := strconv.Atoi(value)
+	if err != nil || code < 100 || code > 599 {
+		return 0, errors.New("expected a code between 100 and 599")
+	}
+	return code, nil
+}
+
+// Contains returns true if the status code is expected.
+func (s StatusCodes) Contains(code int) bool {
+	for _, r := range s {
+		if code >= r.min && code <= r.max {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the status codes in their compact form.
+func (s StatusCodes) String() string {
+	parts := make([]string, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r.min == r.max:
+			parts = append(parts, strconv.Itoa(r.min))
+		case r.min%100 == 0 && r.max == r.min+99:
+			parts = append(parts, fmt.Sprintf("%dxx", r.min/100))
+		default:
+			parts = append(parts, fmt.Sprintf("%d-%d", r.min, r.max))
+		}
+	}
+	return strings.Join(parts, ",")
+}
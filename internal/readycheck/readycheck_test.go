@@ -0,0 +1,247 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readycheck
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("HTTP endpoint", func(t *testing.T) {
+		t.Parallel()
+
+		check, err := Parse("http://svc.ns:8080/ready")
+		require.NoError(t, err)
+		assert.Equal(t, "http://svc.ns:8080/ready", check.String())
+		assert.Equal(t, DefaultTimeout, check.Timeout)
+		assert.Equal(t, DefaultStatusCodes(), check.Statuses)
+	})
+
+	t.Run("TCP endpoint", func(t *testing.T) {
+		t.Parallel()
+
+		check, err := Parse(" tcp://db.ns:5432 ")
+		require.NoError(t, err)
+		assert.Equal(t, "tcp://db.ns:5432", check.String())
+	})
+
+	t.Run("Invalid endpoints", func(t *testing.T) {
+		t.Parallel()
+
+		tests := map[string]string{
+			"ftp://svc.ns/ready": `unsupported scheme "ftp"`,
+			"svc.ns:8080":        "unsupported scheme",
+			"http:///ready":      "missing host",
+			"tcp://db.ns":        "expected tcp://host:port",
+			"http://[::1":        "invalid endpoint",
+		}
+		for endpoint, msg := range tests {
+			_, err := Parse(endpoint)
+			require.Error(t, err, endpoint)
+			assert.Contains(t, err.Error(), msg, endpoint)
+		}
+	})
+}
+
+func TestParseStatusCodes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value    string
+		expected string
+		err      string
+	}{
+		{value: "200", expected: "200"},
+		{value: "200, 204", expected: "200,204"},
+		{value: "2XX,301", expected: "2xx,301"},
+		{value: "200-399", expected: "200-399"},
+		{value: "", err: "no status codes given"},
+		{value: "6xx", err: `invalid status code "6xx": expected a class between 1xx and 5xx`},
+		{value: "abc", err: `invalid status code "abc": expected a code between 100 and 599`},
+		{value: "300-200", err: `invalid status code "300-200": range start is greater than its end`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			codes, err := ParseStatusCodes(tt.value)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, codes.String())
+		})
+	}
+}
+
+func TestStatusCodes_Contains(t *testing.T) {
+	t.Parallel()
+
+	codes, err := ParseStatusCodes("2xx,301,400-404")
+	require.NoError(t, err)
+
+	assert.True(t, codes.Contains(200))
+	assert.True(t, codes.Contains(299))
+	assert.True(t, codes.Contains(301))
+	assert.True(t, codes.Contains(404))
+	assert.False(t, codes.Contains(302))
+	assert.False(t, codes.Contains(500))
+}
+
+func TestCheck_Run(t *testing.T) {
+	t.Parallel()
+
+	t.Run("HTTP ready", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/ready", r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		check, err := Parse(server.URL + "/ready")
+		require.NoError(t, err)
+		assert.NoError(t, check.Run(t.Context()))
+	})
+
+	t.Run("HTTP unexpected status", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		check, err := Parse(server.URL)
+		require.NoError(t, err)
+
+		err = check.Run(t.Context())
+		require.Error(t, err)
+		assert.EqualError(t, err, "http check "+server.URL+" returned status 503, expected 2xx")
+	})
+
+	t.Run("HTTP redirect not followed", func(t *testing.T) {
+		t.Parallel()
+
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("redirect must not be followed")
+		}))
+		defer target.Close()
+		server := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+		defer server.Close()
+
+		check, err := Parse(server.URL)
+		require.NoError(t, err)
+
+		err = check.Run(t.Context())
+		require.Error(t, err)
+		assert.EqualError(t, err, "http check "+server.URL+" returned status 302, expected 2xx")
+	})
+
+	t.Run("HTTP custom status codes", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		check, err := Parse(server.URL)
+		require.NoError(t, err)
+		check.Statuses, err = ParseStatusCodes("200,503")
+		require.NoError(t, err)
+
+		assert.NoError(t, check.Run(t.Context()))
+	})
+
+	t.Run("HTTP timeout", func(t *testing.T) {
+		t.Parallel()
+
+		done := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-done:
+			case <-r.Context().Done():
+			}
+		}))
+		defer server.Close()
+		defer close(done)
+
+		check, err := Parse(server.URL)
+		require.NoError(t, err)
+		check.Timeout = 50 * time.Millisecond
+
+		err = check.Run(t.Context())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "context deadline exceeded")
+	})
+
+	t.Run("HTTP endpoint unreachable", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.NotFoundHandler())
+		endpoint := server.URL
+		server.Close()
+
+		check, err := Parse(endpoint)
+		require.NoError(t, err)
+
+		err = check.Run(t.Context())
+		require.Error(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), "http check "+endpoint+" failed: "), err.Error())
+	})
+
+	t.Run("TCP ready", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		check, err := Parse("tcp://" + server.Listener.Addr().String())
+		require.NoError(t, err)
+		assert.NoError(t, check.Run(t.Context()))
+	})
+
+	t.Run("TCP port closed", func(t *testing.T) {
+		t.Parallel()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		check, err := Parse("tcp://" + addr)
+		require.NoError(t, err)
+
+		err = check.Run(t.Context())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tcp check tcp://"+addr+" failed")
+	})
+}
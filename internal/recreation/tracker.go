@@ -0,0 +1,118 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recreation
+
+import (
+	"maps"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TTL is how long a deletion is remembered. Workloads recreated later do not cascade.
+const TTL = time.Hour
+
+// Tracker remembers the annotations of deleted source workloads which opted into
+// cascading on recreation, until a workload with the same name is recreated or TTL expires.
+// The tracking is best effort: deletions are kept in memory only, so they are lost when the
+// operator restarts or another replica acquires leadership, and recreations are missed.
+// It is safe for concurrent use. A nil Tracker remembers nothing.
+type Tracker struct {
+	mu      sync.Mutex
+	clock   clock.PassiveClock
+	deleted map[types.NamespacedName]deletion
+}
+
+// deletion is a remembered deletion of a workload.
+type deletion struct {
+	annotations map[string]string // Annotations of the deleted workload.
+	expires     time.Time         // Time after which the deletion is forgotten.
+}
+
+// NewTracker creates an empty Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{clock: clock.RealClock{}, deleted: map[types.NamespacedName]deletion{}}
+}
+
+// OptedIn reports whether the workload opted into cascading on recreation.
+func OptedIn(obj client.Object) bool {
+	enabled, err := strconv.ParseBool(obj.GetAnnotations()[flag.CascadeOnRecreateAnnotation])
+	return err == nil && enabled
+}
+
+// Remember records the annotations of a deleted workload if it opted into cascading on recreation.
+// Returns true if the deletion was recorded.
+func (t *Tracker) Remember(obj client.Object) bool {
+	if t == nil || !OptedIn(obj) {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.clock.Now()
+	t.prune(now)
+	t.deleted[client.ObjectKeyFromObject(obj)] = deletion{
+		annotations: maps.Clone(obj.GetAnnotations()),
+		expires:     now.Add(TTL),
+	}
+	return true
+}
+
+// Lookup returns the remembered annotations of a deleted workload, unless the deletion expired.
+func (t *Tracker) Lookup(key types.NamespacedName) (map[string]string, bool) {
+	if t == nil {
+		return nil, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	d, ok := t.deleted[key]
+	if !ok {
+		return nil, false
+	}
+	if !t.clock.Now().Before(d.expires) {
+		delete(t.deleted, key)
+		return nil, false
+	}
+	return maps.Clone(d.annotations), true
+}
+
+// Forget drops the remembered deletion of a workload.
+func (t *Tracker) Forget(key types.NamespacedName) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.deleted, key)
+}
+
+// prune drops expired deletions, so that workloads which are never recreated do not accumulate.
+func (t *Tracker) prune(now time.Time) {
+	for key, d := range t.deleted {
+		if !now.Before(d.expires) {
+			delete(t.deleted, key)
+		}
+	}
+}
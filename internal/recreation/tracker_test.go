@@ -0,0 +1,139 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recreation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func newStatefulSet(annotations map[string]string) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "database",
+			Namespace:   "default",
+			Annotations: annotations,
+		},
+	}
+}
+
+func TestOptedIn(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, OptedIn(newStatefulSet(map[string]string{flag.CascadeOnRecreateAnnotation: "true"})))
+	assert.False(t, OptedIn(newStatefulSet(map[string]string{flag.CascadeOnRecreateAnnotation: "false"})))
+	assert.False(t, OptedIn(newStatefulSet(map[string]string{flag.CascadeOnRecreateAnnotation: "yes please"})))
+	assert.False(t, OptedIn(newStatefulSet(nil)))
+}
+
+func TestTracker(t *testing.T) {
+	t.Parallel()
+
+	key := types.NamespacedName{Namespace: "default", Name: "database"}
+
+	t.Run("Remembers opted-in workloads", func(t *testing.T) {
+		t.Parallel()
+
+		tracker := NewTracker()
+		annotations := map[string]string{
+			flag.CascadeOnRecreateAnnotation: "true",
+			"cascader.tkb.ch/deployment":     "app",
+		}
+
+		assert.True(t, tracker.Remember(newStatefulSet(annotations)))
+
+		remembered, ok := tracker.Lookup(key)
+		assert.True(t, ok)
+		assert.Equal(t, annotations, remembered)
+
+		// Modifying the returned annotations must not affect the tracker.
+		remembered["cascader.tkb.ch/deployment"] = "other"
+		remembered, _ = tracker.Lookup(key)
+		assert.Equal(t, "app", remembered["cascader.tkb.ch/deployment"])
+
+		tracker.Forget(key)
+		_, ok = tracker.Lookup(key)
+		assert.False(t, ok)
+	})
+
+	t.Run("Ignores workloads not opted in", func(t *testing.T) {
+		t.Parallel()
+
+		tracker := NewTracker()
+
+		assert.False(t, tracker.Remember(newStatefulSet(map[string]string{"cascader.tkb.ch/deployment": "app"})))
+
+		_, ok := tracker.Lookup(key)
+		assert.False(t, ok)
+	})
+
+	t.Run("Forgets expired deletions", func(t *testing.T) {
+		t.Parallel()
+
+		clock := clocktesting.NewFakePassiveClock(time.Now())
+		tracker := NewTracker()
+		tracker.clock = clock
+
+		assert.True(t, tracker.Remember(newStatefulSet(map[string]string{flag.CascadeOnRecreateAnnotation: "true"})))
+
+		clock.SetTime(clock.Now().Add(TTL - time.Second))
+		_, ok := tracker.Lookup(key)
+		assert.True(t, ok)
+
+		clock.SetTime(clock.Now().Add(time.Second))
+		_, ok = tracker.Lookup(key)
+		assert.False(t, ok)
+		assert.Empty(t, tracker.deleted)
+	})
+
+	t.Run("Prunes expired deletions", func(t *testing.T) {
+		t.Parallel()
+
+		clock := clocktesting.NewFakePassiveClock(time.Now())
+		tracker := NewTracker()
+		tracker.clock = clock
+
+		assert.True(t, tracker.Remember(newStatefulSet(map[string]string{flag.CascadeOnRecreateAnnotation: "true"})))
+
+		clock.SetTime(clock.Now().Add(TTL))
+		other := newStatefulSet(map[string]string{flag.CascadeOnRecreateAnnotation: "true"})
+		other.Name = "cache"
+		assert.True(t, tracker.Remember(other))
+
+		assert.Len(t, tracker.deleted, 1)
+		assert.Contains(t, tracker.deleted, types.NamespacedName{Namespace: "default", Name: "cache"})
+	})
+
+	t.Run("Nil tracker", func(t *testing.T) {
+		t.Parallel()
+
+		var tracker *Tracker
+
+		assert.False(t, tracker.Remember(newStatefulSet(map[string]string{flag.CascadeOnRecreateAnnotation: "true"})))
+		_, ok := tracker.Lookup(key)
+		assert.False(t, ok)
+		tracker.Forget(key)
+	})
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchYears limits the search for the next activation, so that schedules which never
+// match (e.g. February 30) terminate.
+const searchYears = 5
+
+// descriptors maps the supported shorthands to their cron expressions.
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field describes the valid values of one cron field.
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minutes = field{name: "minute", min: 0, max: 59}
+	hours   = field{name: "hour", min: 0, max: 23}
+	days    = field{name: "day of month", min: 1, max: 31}
+	months  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Day of week accepts 7 as an alias for Sunday.
+	weekdays = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// Schedule is a parsed cron expression evaluated in a time zone.
+type Schedule struct {
+	minute, hour, day, month, weekday uint64         // Bit sets of the matching values.
+	dayRestricted, weekdayRestricted  bool           // Whether day of month and day of week are restricted.
+	location                          *time.Location // Location the expression is evaluated in.
+}
+
+// Parse parses a standard five-field cron expression (minute, hour, day of month, month, day of week)
+// or one of the descriptors @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly.
+// Fields support lists, ranges, steps and the names of months and weekdays.
+func Parse(spec string, location *time.Location) (*Schedule, error) {
+	if location == nil {
+		location = time.UTC
+	}
+
+	expr := strings.TrimSpace(spec)
+	if strings.HasPrefix(expr, "@") {
+		var ok bool
+		if expr, ok = descriptors[strings.ToLower(expr)]; !ok {
+			return nil, fmt.Errorf("unsupported descriptor %q", spec)
+		}
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	s := &Schedule{location: location}
+	var err error
+	if s.minute, err = parseField(fields[0], minutes); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hours); err != nil {
+		return nil, err
+	}
+	if s.day, err = parseField(fields[2], days); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], months); err != nil {
+		return nil, err
+	}
+	if s.weekday, err = parseField(fields[4], weekdays); err != nil {
+		return nil, err
+	}
+	// Sunday may be written as 0 or 7.
+	if s.weekday&(1<<7) != 0 {
+		s.weekday = s.weekday&^(1<<7) | 1
+	}
+	s.dayRestricted = !isUnrestricted(fields[2])
+	s.weekdayRestricted = !isUnrestricted(fields[4])
+
+	return s, nil
+}
+
+// Location returns the location the schedule is evaluated in.
+func (s *Schedule) Location() *time.Location {
+	return s.location
+}
+
+// Next returns the first activation after the given time, or the zero time if the schedule
+// does not activate within the next years.
+func (s *Schedule) Next(after time.Time) time.Time {
+	t := after.In(s.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + searchYears
+
+	for t.Year() <= limit {
+		switch {
+		case !has(s.month, int(t.Month())):
+			t = advance(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.location))
+		case !s.dayMatches(t):
+			t = advance(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.location))
+		case !has(s.hour, t.Hour()):
+			// Added as a duration, so that repeated hours of a daylight saving change progress.
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+		case !has(s.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// Latest returns the latest activation in (after, until], or false if there is none.
+func (s *Schedule) Latest(after, until time.Time) (time.Time, bool) {
+	// Widen the searched window until it contains an activation, so that long gaps
+	// do not have to be walked activation by activation.
+	from := after
+	for window := time.Hour; until.Add(-window).After(after); window *= 2 {
+		if next := s.Next(until.Add(-window)); !next.IsZero() && !next.After(until) {
+			from = until.Add(-window)
+			break
+		}
+	}
+
+	var latest time.Time
+	for next := s.Next(from); !next.IsZero() && !next.After(until); next = s.Next(next) {
+		latest = next
+	}
+	return latest, !latest.IsZero()
+}
+
+// dayMatches applies the cron rule that a day matches either restriction if both day of month
+// and day of week are restricted, and both otherwise.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	day := has(s.day, t.Day())
+	weekday := has(s.weekday, int(t.Weekday()))
+	if s.dayRestricted && s.weekdayRestricted {
+		return day || weekday
+	}
+	return day && weekday
+}
+
+// advance returns next, or the next minute if next does not lie after t, e.g. when midnight
+// does not exist because of a daylight saving change.
+func advance(t, next time.Time) time.Time {
+	if next.After(t) {
+		return next
+	}
+	return t.Add(time.Minute)
+}
+
+// has returns true if the value is contained in the bit set.
+func has(set uint64, value int) bool {
+	return set&(1<<uint(value)) != 0
+}
+
+// isWildcard returns true if the field matches every value.
+func isWildcard(value string) bool {
+	return value == "*" || value == "?"
+}
+
+// isUnrestricted returns true if the field starts with a wildcard, e.g. "*" or "*/2", which
+// cron does not treat as a restriction when combining day of month and day of week.
+func isUnrestricted(value string) bool {
+	return strings.HasPrefix(value, "*") || value == "?"
+}
+
+// parseField parses a comma-separated list of values, ranges and steps into a bit set.
+func parseField(value string, f field) (uint64, error) {
+	var set uint64
+	for part := range strings.SplitSeq(value, ",") {
+		bitsOfPart, err := parsePart(part, f)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q: %w", f.name, value, err)
+		}
+		set |= bitsOfPart
+	}
+	return set, nil
+}
+
+// parsePart parses a single value, range or step such as "5", "1-5", "*/15" or "MON-FRI/2".
+func parsePart(part string, f field) (uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+	step := 1
+	if hasStep {
+		var err error
+		if step, err = strconv.Atoi(stepPart); err != nil || step < 1 {
+			return 0, fmt.Errorf("step must be a positive integer")
+		}
+	}
+
+	var low, high int
+	switch {
+	case isWildcard(rangePart):
+		low, high = f.min, f.max
+		if f.max == 7 {
+			high = 6 // Sunday is already covered by 0.
+		}
+	case strings.Contains(rangePart, "-"):
+		from, to, _ := strings.Cut(rangePart, "-")
+		var err error
+		if low, err = parseValue(from, f); err != nil {
+			return 0, err
+		}
+		if high, err = parseValue(to, f); err != nil {
+			return 0, err
+		}
+		if low > high {
+			return 0, fmt.Errorf("range start %d exceeds end %d", low, high)
+		}
+	default:
+		var err error
+		if low, err = parseValue(rangePart, f); err != nil {
+			return 0, err
+		}
+		high = low
+		// A step on a single value, e.g. "5/15", runs until the end of the range.
+		if hasStep {
+			high = f.max
+		}
+	}
+
+	var set uint64
+	for v := low; v <= high; v += step {
+		set |= 1 << uint(v)
+	}
+	return set, nil
+}
+
+// parseValue parses a number or name within the bounds of the field.
+func parseValue(value string, f field) (int, error) {
+	if v, ok := f.names[strings.ToLower(value)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", value)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%d is out of range [%d, %d]", v, f.min, f.max)
+	}
+	return v, nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// at parses an RFC3339 time, failing the test on error.
+func at(t *testing.T, value string) time.Time {
+	t.Helper()
+	ts, err := time.Parse(time.RFC3339, value)
+	require.NoError(t, err)
+	return ts
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr string
+	}{
+		{name: "Every minute", spec: "* * * * *"},
+		{name: "Nightly", spec: "0 3 * * *"},
+		{name: "Lists ranges and steps", spec: "0,30 8-18/2 1-15 */3 1-5"},
+		{name: "Names", spec: "0 3 * jan-mar MON-FRI"},
+		{name: "Sunday as seven", spec: "0 3 * * 7"},
+		{name: "Question mark", spec: "0 3 ? * *"},
+		{name: "Descriptor", spec: "@daily"},
+		{name: "Surrounding spaces", spec: "  0 3 * * *  "},
+		{name: "Too few fields", spec: "0 3 * *", wantErr: `invalid schedule "0 3 * *": expected 5 fields, got 4`},
+		{name: "Seconds field", spec: "0 0 3 * * *", wantErr: `invalid schedule "0 0 3 * * *": expected 5 fields, got 6`},
+		{name: "Unknown descriptor", spec: "@reboot", wantErr: `unsupported descriptor "@reboot"`},
+		{name: "Minute out of range", spec: "60 3 * * *", wantErr: `invalid minute "60": 60 is out of range [0, 59]`},
+		{name: "Day of month zero", spec: "0 3 0 * *", wantErr: `invalid day of month "0": 0 is out of range [1, 31]`},
+		{name: "Unknown name", spec: "0 3 * * fun", wantErr: `invalid day of week "fun": "fun" is not a number`},
+		{name: "Reversed range", spec: "0 18-8 * * *", wantErr: `invalid hour "18-8": range start 18 exceeds end 8`},
+		{name: "Zero step", spec: "*/0 * * * *", wantErr: `invalid minute "*/0": step must be a positive integer`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := Parse(tt.spec, nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, time.UTC, s.Location())
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		spec     string
+		after    string
+		expected string
+	}{
+		{name: "Every minute", spec: "* * * * *", after: "2026-10-18T10:15:30Z", expected: "2026-10-18T10:16:00Z"},
+		{name: "Strictly after", spec: "0 3 * * *", after: "2026-10-18T03:00:00Z", expected: "2026-10-19T03:00:00Z"},
+		{name: "Later today", spec: "0 3 * * *", after: "2026-10-18T01:00:00Z", expected: "2026-10-18T03:00:00Z"},
+		{name: "Step", spec: "*/15 * * * *", after: "2026-10-18T10:16:00Z", expected: "2026-10-18T10:30:00Z"},
+		{name: "Step from value", spec: "5/20 * * * *", after: "2026-10-18T10:26:00Z", expected: "2026-10-18T10:45:00Z"},
+		{name: "Next month", spec: "0 0 1 * *", after: "2026-10-18T10:00:00Z", expected: "2026-11-01T00:00:00Z"},
+		{name: "Next year", spec: "@yearly", after: "2026-10-18T10:00:00Z", expected: "2027-01-01T00:00:00Z"},
+		{name: "Weekday", spec: "0 3 * * mon", after: "2026-10-18T10:00:00Z", expected: "2026-10-19T03:00:00Z"},
+		{name: "Sunday as seven", spec: "0 3 * * 7", after: "2026-10-12T10:00:00Z", expected: "2026-10-18T03:00:00Z"},
+		{name: "Day of month or weekday", spec: "0 3 20 * mon", after: "2026-10-19T10:00:00Z", expected: "2026-10-20T03:00:00Z"},
+		{name: "Day of month and stepped weekday", spec: "0 3 20 * */2", after: "2026-10-18T10:00:00Z", expected: "2026-10-20T03:00:00Z"},
+		{name: "Leap day", spec: "0 0 29 2 *", after: "2026-10-18T10:00:00Z", expected: "2028-02-29T00:00:00Z"},
+		{name: "Never", spec: "0 0 30 2 *", after: "2026-10-18T10:00:00Z", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := Parse(tt.spec, time.UTC)
+			require.NoError(t, err)
+
+			next := s.Next(at(t, tt.after))
+			if tt.expected == "" {
+				assert.True(t, next.IsZero())
+				return
+			}
+			assert.Equal(t, at(t, tt.expected), next.UTC())
+		})
+	}
+}
+
+func TestSchedule_NextLocation(t *testing.T) {
+	t.Parallel()
+
+	zurich, err := time.LoadLocation("Europe/Zurich")
+	require.NoError(t, err)
+
+	t.Run("Evaluated in location", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := Parse("0 3 * * *", zurich)
+		require.NoError(t, err)
+
+		next := s.Next(at(t, "2026-10-18T10:00:00Z"))
+		assert.Equal(t, at(t, "2026-10-19T01:00:00Z"), next.UTC())
+		assert.Equal(t, zurich, next.Location())
+	})
+
+	t.Run("Skipped hour of daylight saving", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := Parse("30 2 * * *", zurich)
+		require.NoError(t, err)
+
+		// 02:30 does not exist on 2027-03-28.
+		next := s.Next(at(t, "2027-03-27T12:00:00Z"))
+		assert.Equal(t, at(t, "2027-03-29T00:30:00Z"), next.UTC())
+	})
+
+	t.Run("Repeated hour of daylight saving", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := Parse("0 * * * *", zurich)
+		require.NoError(t, err)
+
+		// 02:00 occurs twice on 2026-10-25.
+		first := s.Next(at(t, "2026-10-24T23:30:00Z"))
+		assert.Equal(t, at(t, "2026-10-25T00:00:00Z"), first.UTC())
+		second := s.Next(first)
+		assert.Equal(t, at(t, "2026-10-25T01:00:00Z"), second.UTC())
+		assert.Equal(t, at(t, "2026-10-25T02:00:00Z"), s.Next(second).UTC())
+	})
+}
+
+func TestSchedule_Latest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		spec     string
+		after    string
+		until    string
+		expected string
+	}{
+		{name: "None", spec: "0 3 * * *", after: "2026-10-18T03:00:00Z", until: "2026-10-18T10:00:00Z", expected: ""},
+		{name: "Single", spec: "0 3 * * *", after: "2026-10-17T03:00:00Z", until: "2026-10-18T10:00:00Z", expected: "2026-10-18T03:00:00Z"},
+		{name: "Includes until", spec: "0 3 * * *", after: "2026-10-17T03:00:00Z", until: "2026-10-18T03:00:00Z", expected: "2026-10-18T03:00:00Z"},
+		{name: "Latest of several", spec: "0 3 * * *", after: "2026-10-01T03:00:00Z", until: "2026-10-18T10:00:00Z", expected: "2026-10-18T03:00:00Z"},
+		{name: "Frequent over long gap", spec: "* * * * *", after: "2025-10-18T10:00:00Z", until: "2026-10-18T10:00:30Z", expected: "2026-10-18T10:00:00Z"},
+		{name: "Rare over long gap", spec: "@yearly", after: "2020-01-01T00:00:00Z", until: "2026-10-18T10:00:00Z", expected: "2026-01-01T00:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := Parse(tt.spec, time.UTC)
+			require.NoError(t, err)
+
+			latest, ok := s.Latest(at(t, tt.after), at(t, tt.until))
+			if tt.expected == "" {
+				assert.False(t, ok)
+				return
+			}
+			assert.True(t, ok)
+			assert.Equal(t, at(t, tt.expected), latest.UTC())
+		})
+	}
+}
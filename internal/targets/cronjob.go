@@ -0,0 +1,112 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targets
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/internal/utils"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// cronJobInstantiateAnnotation marks Jobs created manually from a CronJob, like kubectl create job --from=cronjob.
+	cronJobInstantiateAnnotation = "cronjob.kubernetes.io/instantiate"
+	// cronJobNameSuffix is appended to the CronJob name to generate the names of created Jobs.
+	cronJobNameSuffix = "-cascader-"
+	// maxJobNamePrefixLength leaves room for the random suffix in Job names, which are limited to 63 characters.
+	maxJobNamePrefixLength = 58
+)
+
+// CronJobTarget runs a CronJob immediately by creating a one-off Job from its job template.
+type CronJobTarget struct {
+	namespace   string        // Namespace of the CronJob.
+	name        string        // Name of the CronJob.
+	triggeredBy string        // ID of the source triggering the run.
+	kubeClient  client.Client // Kubernetes client.
+}
+
+// NewCronJob creates a new CronJob target.
+func NewCronJob(namespace, name, triggeredBy string, c client.Client) *CronJobTarget {
+	return &CronJobTarget{
+		namespace:   namespace,
+		name:        name,
+		triggeredBy: triggeredBy,
+		kubeClient:  c,
+	}
+}
+
+func (t *CronJobTarget) Kind() kinds.Kind        { return kinds.CronJobKind }
+func (t *CronJobTarget) Name() string            { return t.name }
+func (t *CronJobTarget) Namespace() string       { return t.namespace }
+func (t *CronJobTarget) Resource() client.Object { return &batchv1.CronJob{} }
+func (t *CronJobTarget) ID() string              { return utils.GenerateID(t.Kind(), t.namespace, t.name) }
+
+// Trigger creates a Job from the job template of the CronJob. The Job is controlled by the CronJob,
+// so the CronJob controller prunes it according to the history limits of the CronJob.
+func (t *CronJobTarget) Trigger(ctx context.Context) error {
+	// Fetch the existing CronJob.
+	cj := &batchv1.CronJob{}
+	if err := t.kubeClient.Get(ctx, client.ObjectKey{Namespace: t.namespace, Name: t.name}, cj); err != nil {
+		return fmt.Errorf("failed to fetch CronJob %s/%s: %w", t.namespace, t.name, err)
+	}
+
+	job := jobFromCronJob(cj, t.triggeredBy)
+	if err := t.kubeClient.Create(ctx, job); err != nil {
+		return fmt.Errorf("failed to create Job for CronJob %s/%s: %w", t.namespace, t.name, err)
+	}
+
+	return nil
+}
+
+// jobFromCronJob returns a new Job built from the job template of the CronJob.
+func jobFromCronJob(cj *batchv1.CronJob, triggeredBy string) *batchv1.Job {
+	annotations := maps.Clone(cj.Spec.JobTemplate.Annotations)
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[cronJobInstantiateAnnotation] = "manual"
+	if triggeredBy != "" {
+		annotations[flag.TriggeredByAnnotation] = triggeredBy
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName:    jobNamePrefix(cj.Name),
+			Namespace:       cj.Namespace,
+			Labels:          maps.Clone(cj.Spec.JobTemplate.Labels),
+			Annotations:     annotations,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(cj, batchv1.SchemeGroupVersion.WithKind("CronJob"))},
+		},
+		Spec: *cj.Spec.JobTemplate.Spec.DeepCopy(),
+	}
+}
+
+// jobNamePrefix returns the prefix for names of Jobs created from the CronJob.
+func jobNamePrefix(name string) string {
+	if limit := maxJobNamePrefixLength - len(cronJobNameSuffix); len(name) > limit {
+		name = name[:limit]
+	}
+	return name + cronJobNameSuffix
+}
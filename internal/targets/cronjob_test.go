@@ -0,0 +1,154 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targets
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestCronJobTarget_Methods(t *testing.T) {
+	t.Parallel()
+
+	target := NewCronJob("default", "cache-warmer", "Deployment/default/cache", nil)
+
+	assert.Equal(t, kinds.CronJobKind, target.Kind())
+	assert.Equal(t, "cache-warmer", target.Name())
+	assert.Equal(t, "default", target.Namespace())
+	assert.IsType(t, &batchv1.CronJob{}, target.Resource())
+	assert.Equal(t, "CronJob/default/cache-warmer", target.ID())
+}
+
+func TestCronJobTarget_Trigger(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	_ = batchv1.AddToScheme(scheme)
+
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cache-warmer",
+			Namespace: "default",
+			UID:       types.UID("1234"),
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule: "0 * * * *",
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"app": "cache-warmer"},
+					Annotations: map[string]string{"cascader.tkb.ch/deployment": "api"},
+				},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyNever,
+							Containers:    []corev1.Container{{Name: "warm", Image: "warm:1.0"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("Creates Job from template", func(t *testing.T) {
+		t.Parallel()
+
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cronJob).Build()
+		target := NewCronJob("default", "cache-warmer", "Deployment/default/cache", fakeClient)
+
+		require.NoError(t, target.Trigger(t.Context()))
+		require.NoError(t, target.Trigger(t.Context()))
+
+		jobs := &batchv1.JobList{}
+		require.NoError(t, fakeClient.List(t.Context(), jobs, client.InNamespace("default")))
+		require.Len(t, jobs.Items, 2)
+		assert.NotEqual(t, jobs.Items[0].Name, jobs.Items[1].Name)
+
+		job := jobs.Items[0]
+		assert.True(t, strings.HasPrefix(job.Name, "cache-warmer-cascader-"))
+		assert.Equal(t, map[string]string{"app": "cache-warmer"}, job.Labels)
+		assert.Equal(t, map[string]string{
+			"cascader.tkb.ch/deployment":        "api",
+			"cronjob.kubernetes.io/instantiate": "manual",
+			flag.TriggeredByAnnotation:          "Deployment/default/cache",
+		}, job.Annotations)
+		assert.Equal(t, "warm:1.0", job.Spec.Template.Spec.Containers[0].Image)
+
+		owner := metav1.GetControllerOf(&job)
+		require.NotNil(t, owner)
+		assert.Equal(t, "CronJob", owner.Kind)
+		assert.Equal(t, "cache-warmer", owner.Name)
+		assert.Equal(t, types.UID("1234"), owner.UID)
+	})
+
+	t.Run("Missing CronJob", func(t *testing.T) {
+		t.Parallel()
+
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		target := NewCronJob("default", "cache-warmer", "", fakeClient)
+
+		err := target.Trigger(t.Context())
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to fetch CronJob default/cache-warmer")
+	})
+
+	t.Run("Create Error", func(t *testing.T) {
+		t.Parallel()
+
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(cronJob).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(context.Context, client.WithWatch, client.Object, ...client.CreateOption) error {
+					return errors.New("simulated create error")
+				},
+			}).
+			Build()
+		target := NewCronJob("default", "cache-warmer", "", fakeClient)
+
+		err := target.Trigger(t.Context())
+
+		require.EqualError(t, err, "failed to create Job for CronJob default/cache-warmer: simulated create error")
+	})
+}
+
+func TestJobNamePrefix(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "cache-warmer-cascader-", jobNamePrefix("cache-warmer"))
+
+	prefix := jobNamePrefix(strings.Repeat("a", 52))
+	assert.Len(t, prefix, 58)
+	assert.True(t, strings.HasSuffix(prefix, "-cascader-"))
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrEvictionBlocked is returned if a PodDisruptionBudget refuses the eviction of a pod.
+var ErrEvictionBlocked = errors.New("eviction blocked by a PodDisruptionBudget")
+
+// EvictionOrder returns the names of the pods of the target in the order they are evicted.
+// Unlike patching the pod template, evicting the pods does not create a new ReplicaSet or
+// ControllerRevision and therefore keeps the rollout history of the target untouched.
+func EvictionOrder(ctx context.Context, c client.Client, t Target) ([]string, error) {
+	obj := t.Resource()
+	if err := c.Get(ctx, client.ObjectKey{Namespace: t.Namespace(), Name: t.Name()}, obj); err != nil {
+		return nil, fmt.Errorf("failed to fetch %s %s/%s: %w", t.Kind(), t.Namespace(), t.Name(), err)
+	}
+
+	pods, err := workloads.ListPods(ctx, c, obj)
+	if err != nil {
+		return nil, err
+	}
+	sortPodsForEviction(t.Kind(), pods)
+
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	return names, nil
+}
+
+// EvictPod creates an eviction for the pod and returns the UID of the evicted pod, or an empty UID if
+// the pod no longer exists. Evictions refused by a PodDisruptionBudget return ErrEvictionBlocked.
+func EvictPod(ctx context.Context, c client.Client, namespace, name string) (types.UID, error) {
+	pod := &corev1.Pod{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, pod); err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to fetch pod %s/%s: %w", namespace, name, err)
+	}
+
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+	}
+
+	err := c.SubResource("eviction").Create(ctx, pod, eviction)
+	switch {
+	case err == nil:
+		return pod.UID, nil
+	case kerrors.IsNotFound(err):
+		return "", nil
+	case kerrors.IsTooManyRequests(err):
+		return "", fmt.Errorf("%w: %v", ErrEvictionBlocked, err)
+	default:
+		return "", err
+	}
+}
+
+// Eviction is the progress of restarting a target by evicting its Pods one at a time. It is persisted
+// by the caller between steps, so that no worker is blocked while Pods are replaced.
+type Eviction struct {
+	Target   string    `json:"target"`            // ID of the target.
+	Pods     []string  `json:"pods"`              // Names of the Pods still to evict, in eviction order.
+	Total    int       `json:"total"`             // Number of Pods of the target when the eviction started.
+	Evicted  string    `json:"evicted,omitempty"` // Name of the evicted Pod whose replacement is awaited.
+	UID      types.UID `json:"uid,omitempty"`     // UID of the evicted Pod.
+	Blocked  bool      `json:"blocked,omitempty"` // Whether a PodDisruptionBudget blocking the next eviction was reported.
+	Deadline time.Time `json:"deadline"`          // Time after which the eviction is given up.
+}
+
+// EvictionStep reports the outcome of a single step of an eviction.
+type EvictionStep struct {
+	Done    bool   // All Pods were evicted and replaced.
+	Evicted string // Name of the Pod evicted by this step.
+	Blocked error  // PodDisruptionBudget refusing the eviction, set only the first time it is hit per Pod.
+}
+
+// EvictionTarget restarts a target with the EvictStrategy. Trigger evicts the first Pod, while the other
+// Pods are evicted by the following calls of Step, each once the replacement of the previous Pod is ready.
+type EvictionTarget struct {
+	Target
+	kubeClient client.Client // Kubernetes client.
+	timeout    time.Duration // Maximum duration for evicting all Pods.
+	eviction   *Eviction     // Progress of the eviction, nil until triggered.
+	last       EvictionStep  // Outcome of the step taken by Trigger.
+}
+
+// NewEvictionTarget returns the target restarted by evicting its Pods within the given timeout.
+func NewEvictionTarget(t Target, c client.Client, timeout time.Duration) *EvictionTarget {
+	return &EvictionTarget{Target: t, kubeClient: c, timeout: timeout}
+}
+
+// ResumeEviction returns the target whose eviction was persisted after a previous step.
+func ResumeEviction(t Target, c client.Client, eviction *Eviction) *EvictionTarget {
+	return &EvictionTarget{Target: t, kubeClient: c, eviction: eviction}
+}
+
+// Trigger starts the eviction of the Pods of the target and evicts the first Pod.
+func (t *EvictionTarget) Trigger(ctx context.Context) error {
+	pods, err := EvictionOrder(ctx, t.kubeClient, t.Target)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	t.eviction = &Eviction{
+		Target:   t.ID(),
+		Pods:     pods,
+		Total:    len(pods),
+		Deadline: now.Add(t.timeout),
+	}
+	t.last, err = t.Step(ctx, now)
+	return err
+}
+
+// Eviction returns the progress of the eviction, to be persisted until it is done.
+func (t *EvictionTarget) Eviction() *Eviction {
+	return t.eviction
+}
+
+// LastStep returns the outcome of the step taken by Trigger.
+func (t *EvictionTarget) LastStep() EvictionStep {
+	return t.last
+}
+
+// Step advances the eviction by one step: once the Pod evicted last is gone, the target observed its
+// current generation and enough of its Pods are ready, the next Pod is evicted.
+func (t *EvictionTarget) Step(ctx context.Context, now time.Time) (EvictionStep, error) {
+	e := t.eviction
+	if now.After(e.Deadline) {
+		return EvictionStep{}, fmt.Errorf("%s did not finish within the eviction timeout", t.ID())
+	}
+
+	if e.Evicted != "" {
+		settled, err := t.settled(ctx)
+		if err != nil || !settled {
+			return EvictionStep{}, err
+		}
+		e.Evicted, e.UID = "", ""
+	}
+	if len(e.Pods) == 0 {
+		return EvictionStep{Done: true}, nil
+	}
+
+	pod := e.Pods[0]
+	uid, err := EvictPod(ctx, t.kubeClient, t.Namespace(), pod)
+	if errors.Is(err, ErrEvictionBlocked) {
+		// Report the block only once per Pod to avoid flooding the event stream.
+		if e.Blocked {
+			return EvictionStep{}, nil
+		}
+		e.Blocked = true
+		return EvictionStep{Blocked: err}, nil
+	}
+	if err != nil {
+		return EvictionStep{}, fmt.Errorf("failed to evict pod %s/%s: %w", t.Namespace(), pod, err)
+	}
+
+	e.Pods = e.Pods[1:]
+	e.Blocked = false
+	if uid == "" {
+		// The Pod is already gone, the next one is evicted on the next step.
+		return EvictionStep{Done: len(e.Pods) == 0}, nil
+	}
+
+	e.Evicted, e.UID = pod, uid
+	return EvictionStep{Evicted: pod}, nil
+}
+
+// settled reports whether the evicted Pod is gone, the target observed its current generation and
+// the Pods it should run are ready. The Pods are counted directly instead of relying on the replica
+// counts of the target status, which lag behind the eviction.
+func (t *EvictionTarget) settled(ctx context.Context) (bool, error) {
+	pod := &corev1.Pod{}
+	err := t.kubeClient.Get(ctx, client.ObjectKey{Namespace: t.Namespace(), Name: t.eviction.Evicted}, pod)
+	switch {
+	case kerrors.IsNotFound(err):
+	case err != nil:
+		return false, err
+	case pod.UID == t.eviction.UID:
+		return false, nil // Evicted pod is still terminating.
+	}
+
+	obj := t.Resource()
+	if err := t.kubeClient.Get(ctx, client.ObjectKey{Namespace: t.Namespace(), Name: t.Name()}, obj); err != nil {
+		return false, err
+	}
+	if observedGeneration(obj) < obj.GetGeneration() {
+		return false, nil
+	}
+
+	pods, err := workloads.ListPods(ctx, t.kubeClient, obj)
+	if err != nil {
+		return false, err
+	}
+	if len(pods) < desiredPods(obj, t.eviction.Total) {
+		return false, nil
+	}
+	for i := range pods {
+		if !workloads.PodReady(&pods[i]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// observedGeneration returns the generation observed by the controller of the workload.
+func observedGeneration(obj client.Object) int64 {
+	switch res := obj.(type) {
+	case *appsv1.Deployment:
+		return res.Status.ObservedGeneration
+	case *appsv1.StatefulSet:
+		return res.Status.ObservedGeneration
+	case *appsv1.DaemonSet:
+		return res.Status.ObservedGeneration
+	default:
+		return obj.GetGeneration()
+	}
+}
+
+// desiredPods returns the number of Pods the workload should run: the Pods it ran when the eviction
+// started, unless it was scaled down in the meantime.
+func desiredPods(obj client.Object, total int) int {
+	var replicas *int32
+	switch res := obj.(type) {
+	case *appsv1.Deployment:
+		replicas = res.Spec.Replicas
+	case *appsv1.StatefulSet:
+		replicas = res.Spec.Replicas
+	}
+	if replicas != nil && int(*replicas) < total {
+		return int(*replicas)
+	}
+	return total
+}
+
+// sortPodsForEviction orders pods deterministically.
+// StatefulSet pods are evicted from the highest to the lowest ordinal, like the StatefulSet controller does.
+func sortPodsForEviction(kind kinds.Kind, pods []corev1.Pod) {
+	if kind != kinds.StatefulSetKind {
+		sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+		return
+	}
+
+	sort.Slice(pods, func(i, j int) bool {
+		return podOrdinal(pods[i].Name) > podOrdinal(pods[j].Name)
+	})
+}
+
+// podOrdinal extracts the ordinal from a StatefulSet pod name, returning -1 if it has none.
+func podOrdinal(name string) int {
+	idx := strings.LastIndex(name, "-")
+	if idx < 0 {
+		return -1
+	}
+	ordinal, err := strconv.Atoi(name[idx+1:])
+	if err != nil {
+		return -1
+	}
+	return ordinal
+}
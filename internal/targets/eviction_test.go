@@ -0,0 +1,301 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targets
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/test/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newStableStatefulSet(name string, replicas int32) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: testutils.Int32Ptr(replicas),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+		},
+		Status: appsv1.StatefulSetStatus{
+			ReadyReplicas:   replicas,
+			UpdatedReplicas: replicas,
+		},
+	}
+}
+
+func newPod(name, app string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID(name + "-uid"),
+			Labels:    map[string]string{"app": app},
+		},
+	}
+}
+
+func newReadyPod(name, app string) *corev1.Pod {
+	pod := newPod(name, app)
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	return pod
+}
+
+func TestEvictionOrder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("StatefulSet pods by descending ordinal", func(t *testing.T) {
+		t.Parallel()
+
+		fakeClient := fake.NewClientBuilder().
+			WithObjects(
+				newStableStatefulSet("db", 3),
+				newPod("db-0", "db"),
+				newPod("db-1", "db"),
+				newPod("db-2", "db"),
+				newPod("other-0", "other"),
+			).
+			Build()
+
+		pods, err := EvictionOrder(t.Context(), fakeClient, NewStatefulSet("default", "db", fakeClient))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"db-2", "db-1", "db-0"}, pods)
+	})
+
+	t.Run("Target not found", func(t *testing.T) {
+		t.Parallel()
+
+		fakeClient := fake.NewClientBuilder().Build()
+
+		_, err := EvictionOrder(t.Context(), fakeClient, NewStatefulSet("default", "db", fakeClient))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to fetch StatefulSet default/db")
+	})
+}
+
+func TestEvictPod(t *testing.T) {
+	t.Parallel()
+
+	// withEviction builds a client whose evictions are handled by evict, or by the fake client if evict returns nil.
+	withEviction := func(evict func() error, objs ...client.Object) client.Client {
+		return fake.NewClientBuilder().
+			WithObjects(objs...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				SubResourceCreate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+					if err := evict(); err != nil {
+						return err
+					}
+					return c.SubResource(subResourceName).Create(ctx, obj, subResource, opts...)
+				},
+			}).
+			Build()
+	}
+
+	t.Run("Evicts pod", func(t *testing.T) {
+		t.Parallel()
+
+		fakeClient := withEviction(func() error { return nil }, newPod("db-0", "db"))
+
+		uid, err := EvictPod(t.Context(), fakeClient, "default", "db-0")
+		require.NoError(t, err)
+		assert.Equal(t, types.UID("db-0-uid"), uid)
+
+		err = fakeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "db-0"}, &corev1.Pod{})
+		assert.True(t, kerrors.IsNotFound(err))
+	})
+
+	t.Run("Pod already gone", func(t *testing.T) {
+		t.Parallel()
+
+		fakeClient := withEviction(func() error { return errors.New("unexpected eviction") })
+
+		uid, err := EvictPod(t.Context(), fakeClient, "default", "db-0")
+		require.NoError(t, err)
+		assert.Empty(t, uid)
+	})
+
+	t.Run("Blocked by PodDisruptionBudget", func(t *testing.T) {
+		t.Parallel()
+
+		fakeClient := withEviction(func() error { return kerrors.NewTooManyRequests("disruption budget", 0) }, newPod("db-0", "db"))
+
+		_, err := EvictPod(t.Context(), fakeClient, "default", "db-0")
+		require.ErrorIs(t, err, ErrEvictionBlocked)
+	})
+
+	t.Run("Eviction error", func(t *testing.T) {
+		t.Parallel()
+
+		fakeClient := withEviction(func() error { return errors.New("simulated eviction error") }, newPod("db-0", "db"))
+
+		_, err := EvictPod(t.Context(), fakeClient, "default", "db-0")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrEvictionBlocked)
+		assert.Contains(t, err.Error(), "simulated eviction error")
+	})
+}
+
+func TestEvictionTargetTrigger(t *testing.T) {
+	t.Parallel()
+
+	fakeClient := fake.NewClientBuilder().
+		WithObjects(newStableStatefulSet("db", 2), newReadyPod("db-0", "db"), newReadyPod("db-1", "db")).
+		Build()
+
+	target := NewEvictionTarget(NewStatefulSet("default", "db", fakeClient), fakeClient, time.Minute)
+	require.NoError(t, target.Trigger(t.Context()))
+
+	err := fakeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "db-1"}, &corev1.Pod{})
+	assert.True(t, kerrors.IsNotFound(err))
+	assert.Equal(t, EvictionStep{Evicted: "db-1"}, target.LastStep())
+
+	eviction := target.Eviction()
+	assert.Equal(t, "StatefulSet/default/db", eviction.Target)
+	assert.Equal(t, []string{"db-0"}, eviction.Pods)
+	assert.Equal(t, 2, eviction.Total)
+	assert.Equal(t, "db-1", eviction.Evicted)
+	assert.Equal(t, types.UID("db-1-uid"), eviction.UID)
+}
+
+func TestEvictionTargetStep(t *testing.T) {
+	t.Parallel()
+
+	replacement := func(pod *corev1.Pod) client.Object {
+		pod.UID = "replacement-uid"
+		return pod
+	}
+
+	tests := []struct {
+		name     string
+		objs     []client.Object
+		expected EvictionStep
+		pending  bool
+	}{
+		{
+			name:    "Evicted pod terminating",
+			objs:    []client.Object{newStableStatefulSet("db", 1), newReadyPod("db-0", "db")},
+			pending: true,
+		},
+		{
+			name: "Generation not observed",
+			objs: []client.Object{
+				func() client.Object {
+					sts := newStableStatefulSet("db", 1)
+					sts.Generation = 2
+					sts.Status.ObservedGeneration = 1
+					return sts
+				}(),
+				replacement(newReadyPod("db-0", "db")),
+			},
+			pending: true,
+		},
+		{
+			name:    "Replacement pod missing",
+			objs:    []client.Object{newStableStatefulSet("db", 1)},
+			pending: true,
+		},
+		{
+			name:    "Replacement pod not ready",
+			objs:    []client.Object{newStableStatefulSet("db", 1), replacement(newPod("db-0", "db"))},
+			pending: true,
+		},
+		{
+			name:     "Replaced and ready",
+			objs:     []client.Object{newStableStatefulSet("db", 1), replacement(newReadyPod("db-0", "db"))},
+			expected: EvictionStep{Done: true},
+		},
+		{
+			name:     "Scaled down to zero",
+			objs:     []client.Object{newStableStatefulSet("db", 0)},
+			expected: EvictionStep{Done: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fakeClient := fake.NewClientBuilder().WithObjects(tt.objs...).Build()
+			eviction := &Eviction{
+				Target:   "StatefulSet/default/db",
+				Total:    1,
+				Evicted:  "db-0",
+				UID:      "db-0-uid",
+				Deadline: time.Now().Add(time.Minute),
+			}
+
+			target := ResumeEviction(NewStatefulSet("default", "db", fakeClient), fakeClient, eviction)
+			step, err := target.Step(t.Context(), time.Now())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, step)
+			assert.Equal(t, tt.pending, eviction.Evicted != "")
+		})
+	}
+
+	t.Run("Deadline exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		fakeClient := fake.NewClientBuilder().Build()
+		now := time.Now()
+		eviction := &Eviction{Target: "StatefulSet/default/db", Pods: []string{"db-0"}, Deadline: now}
+
+		_, err := ResumeEviction(NewStatefulSet("default", "db", fakeClient), fakeClient, eviction).Step(t.Context(), now.Add(time.Second))
+		require.Error(t, err)
+		assert.EqualError(t, err, "StatefulSet/default/db did not finish within the eviction timeout")
+	})
+}
+
+func TestSortPodsForEviction(t *testing.T) {
+	t.Parallel()
+
+	t.Run("StatefulSet pods by descending ordinal", func(t *testing.T) {
+		t.Parallel()
+
+		pods := []corev1.Pod{*newPod("db-2", "db"), *newPod("db-10", "db"), *newPod("db-0", "db")}
+		sortPodsForEviction(kinds.StatefulSetKind, pods)
+
+		assert.Equal(t, "db-10", pods[0].Name)
+		assert.Equal(t, "db-2", pods[1].Name)
+		assert.Equal(t, "db-0", pods[2].Name)
+	})
+
+	t.Run("Other pods by name", func(t *testing.T) {
+		t.Parallel()
+
+		pods := []corev1.Pod{*newPod("web-b", "web"), *newPod("web-a", "web")}
+		sortPodsForEviction(kinds.DeploymentKind, pods)
+
+		assert.Equal(t, "web-a", pods[0].Name)
+		assert.Equal(t, "web-b", pods[1].Name)
+	})
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targets
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/internal/utils"
+	"github.com/thurgauerkb/cascader/internal/workloads"
+
+	batchv1 "k8s.io/api/batch/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// jobGeneratedLabels are added by the Job controller and must not be copied to a new Job.
+var jobGeneratedLabels = []string{
+	"controller-uid",
+	"job-name",
+	batchv1.ControllerUidLabel,
+	batchv1.JobNameLabel,
+}
+
+// jobStateAnnotations hold the cascade state of a previous run, which must not leak into the new run.
+var jobStateAnnotations = []string{
+	flag.LastObservedRestartAnnotation,
+	flag.PendingRetryAnnotation,
+	flag.PendingEvictionsAnnotation,
+	flag.StabilityGatesAnnotation,
+	flag.PendingVerificationAnnotation,
+	flag.DeferredTargetsAnnotation,
+	flag.TriggeredByAnnotation,
+}
+
+// JobTarget re-runs a finished Job by recreating it from its spec.
+type JobTarget struct {
+	namespace   string        // Namespace of the Job.
+	name        string        // Name of the Job.
+	triggeredBy string        // ID of the source triggering the re-run.
+	kubeClient  client.Client // Kubernetes client.
+	rerun       *batchv1.Job  // Re-run of the deleted Job which was not created yet.
+}
+
+// NewJob creates a new Job target.
+func NewJob(namespace, name, triggeredBy string, c client.Client) *JobTarget {
+	return &JobTarget{
+		namespace:   namespace,
+		name:        name,
+		triggeredBy: triggeredBy,
+		kubeClient:  c,
+	}
+}
+
+func (t *JobTarget) Kind() kinds.Kind        { return kinds.JobKind }
+func (t *JobTarget) Name() string            { return t.name }
+func (t *JobTarget) Namespace() string       { return t.namespace }
+func (t *JobTarget) Resource() client.Object { return &batchv1.Job{} }
+func (t *JobTarget) ID() string              { return utils.GenerateID(t.Kind(), t.namespace, t.name) }
+
+// Trigger deletes the finished Job and creates it again with the same name and spec.
+// Running Jobs are not interrupted. If the Job was deleted but could not be created again, e.g.
+// because the previous Job is not gone yet, the re-run is kept, see PendingRerun.
+func (t *JobTarget) Trigger(ctx context.Context) error {
+	if t.rerun == nil {
+		// Fetch the existing Job.
+		old := &batchv1.Job{}
+		if err := t.kubeClient.Get(ctx, client.ObjectKey{Namespace: t.namespace, Name: t.name}, old); err != nil {
+			return fmt.Errorf("failed to fetch Job %s/%s: %w", t.namespace, t.name, err)
+		}
+		if !workloads.JobFinished(old) {
+			return fmt.Errorf("job %s/%s is still running", t.namespace, t.name)
+		}
+
+		job := rerunJob(old, t.triggeredBy)
+
+		// Pods of the previous run are garbage collected in the background.
+		if err := t.kubeClient.Delete(
+			ctx,
+			old,
+			client.PropagationPolicy(metav1.DeletePropagationBackground),
+			client.Preconditions{UID: &old.UID},
+		); err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete Job %s/%s: %w", t.namespace, t.name, err)
+		}
+		t.rerun = job
+	}
+
+	// The name is only released once the previous Job is gone, which is retried instead of waited for.
+	if err := t.kubeClient.Create(ctx, t.rerun.DeepCopy()); err != nil {
+		if kerrors.IsAlreadyExists(err) {
+			return fmt.Errorf("job %s/%s is still being deleted", t.namespace, t.name)
+		}
+		return fmt.Errorf("failed to recreate Job %s/%s: %w", t.namespace, t.name, err)
+	}
+	t.rerun = nil
+
+	return nil
+}
+
+// PendingRerun returns the re-run of the deleted Job which was not created yet, or nil.
+// It must be persisted by the caller, since the spec of the Job is lost otherwise.
+func (t *JobTarget) PendingRerun() *batchv1.Job {
+	return t.rerun
+}
+
+// ResumeRerun sets the re-run of a deleted Job persisted after a failed Trigger, so that
+// the next Trigger creates it instead of fetching the deleted Job.
+func (t *JobTarget) ResumeRerun(job *batchv1.Job) {
+	t.rerun = job
+}
+
+// rerunJob returns a new Job with the metadata and spec of the given Job, without the
+// fields generated for the previous run.
+func rerunJob(old *batchv1.Job, triggeredBy string) *batchv1.Job {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            old.Name,
+			Namespace:       old.Namespace,
+			Labels:          maps.Clone(old.Labels),
+			Annotations:     maps.Clone(old.Annotations),
+			OwnerReferences: old.OwnerReferences,
+		},
+		Spec: *old.Spec.DeepCopy(),
+	}
+
+	// Selectors generated by the API server reference the UID of the previous Job.
+	if job.Spec.ManualSelector == nil || !*job.Spec.ManualSelector {
+		job.Spec.Selector = nil
+		for _, label := range jobGeneratedLabels {
+			delete(job.Labels, label)
+			delete(job.Spec.Template.Labels, label)
+		}
+	}
+
+	for _, key := range jobStateAnnotations {
+		delete(job.Annotations, key)
+	}
+	if triggeredBy != "" {
+		if job.Annotations == nil {
+			job.Annotations = map[string]string{}
+		}
+		job.Annotations[flag.TriggeredByAnnotation] = triggeredBy
+	}
+
+	return job
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/thurgauerkb/cascader/internal/flag"
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/test/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestJobTarget_Methods(t *testing.T) {
+	t.Parallel()
+
+	target := NewJob("default", "warm-cache", "Deployment/default/cache", nil)
+
+	assert.Equal(t, kinds.JobKind, target.Kind())
+	assert.Equal(t, "warm-cache", target.Name())
+	assert.Equal(t, "default", target.Namespace())
+	assert.IsType(t, &batchv1.Job{}, target.Resource())
+	assert.Equal(t, "Job/default/warm-cache", target.ID())
+}
+
+func TestJobTarget_Trigger(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	_ = batchv1.AddToScheme(scheme)
+
+	newFinishedJob := func(condType batchv1.JobConditionType) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "warm-cache",
+				Namespace: "default",
+				UID:       types.UID("1234"),
+				Labels: map[string]string{
+					"app":                      "warm-cache",
+					"controller-uid":           "1234",
+					batchv1.ControllerUidLabel: "1234",
+					batchv1.JobNameLabel:       "warm-cache",
+				},
+				Annotations: map[string]string{
+					"cascader.tkb.ch/deployment":        "api",
+					flag.LastObservedRestartAnnotation:  "2026-01-01T00:00:00Z",
+					flag.PendingRetryAnnotation:         "{}",
+					flag.TriggeredByAnnotation:          "Deployment/default/other",
+					"kubectl.kubernetes.io/restartedAt": "2026-01-01T00:00:00Z",
+				},
+				OwnerReferences: []metav1.OwnerReference{{APIVersion: "v1", Kind: "ConfigMap", Name: "owner", UID: "5678"}},
+			},
+			Spec: batchv1.JobSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{batchv1.ControllerUidLabel: "1234"}},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"app":                      "warm-cache",
+							"controller-uid":           "1234",
+							"job-name":                 "warm-cache",
+							batchv1.ControllerUidLabel: "1234",
+							batchv1.JobNameLabel:       "warm-cache",
+						},
+					},
+					Spec: corev1.PodSpec{
+						RestartPolicy: corev1.RestartPolicyNever,
+						Containers:    []corev1.Container{{Name: "warm", Image: "warm:1.0"}},
+					},
+				},
+			},
+			Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{{Type: condType, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+
+	t.Run("Re-runs completed Job", func(t *testing.T) {
+		t.Parallel()
+
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newFinishedJob(batchv1.JobComplete)).Build()
+		target := NewJob("default", "warm-cache", "Deployment/default/cache", fakeClient)
+
+		require.NoError(t, target.Trigger(t.Context()))
+
+		job := &batchv1.Job{}
+		require.NoError(t, fakeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "warm-cache"}, job))
+		assert.NotEqual(t, types.UID("1234"), job.UID)
+		assert.Empty(t, job.Status.Conditions)
+		assert.Nil(t, job.Spec.Selector)
+		assert.Equal(t, map[string]string{"app": "warm-cache"}, job.Labels)
+		assert.Equal(t, map[string]string{"app": "warm-cache"}, job.Spec.Template.Labels)
+		assert.Equal(t, map[string]string{
+			"cascader.tkb.ch/deployment":        "api",
+			flag.TriggeredByAnnotation:          "Deployment/default/cache",
+			"kubectl.kubernetes.io/restartedAt": "2026-01-01T00:00:00Z",
+		}, job.Annotations)
+		assert.Equal(t, "owner", job.OwnerReferences[0].Name)
+		assert.Equal(t, "warm:1.0", job.Spec.Template.Spec.Containers[0].Image)
+	})
+
+	t.Run("Re-runs failed Job", func(t *testing.T) {
+		t.Parallel()
+
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newFinishedJob(batchv1.JobFailed)).Build()
+		target := NewJob("default", "warm-cache", "Deployment/default/cache", fakeClient)
+
+		require.NoError(t, target.Trigger(t.Context()))
+
+		job := &batchv1.Job{}
+		require.NoError(t, fakeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "warm-cache"}, job))
+		assert.Empty(t, job.Status.Conditions)
+	})
+
+	t.Run("Keeps manual selector", func(t *testing.T) {
+		t.Parallel()
+
+		old := newFinishedJob(batchv1.JobComplete)
+		old.Spec.ManualSelector = ptr.To(true)
+		old.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "warm-cache"}}
+
+		job := rerunJob(old, "")
+
+		assert.Equal(t, old.Spec.Selector, job.Spec.Selector)
+		assert.Equal(t, old.Spec.Template.Labels, job.Spec.Template.Labels)
+		assert.NotContains(t, job.Annotations, flag.TriggeredByAnnotation)
+	})
+
+	t.Run("Keeps re-run while previous Job is deleted", func(t *testing.T) {
+		t.Parallel()
+
+		var creates int
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(newFinishedJob(batchv1.JobComplete)).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					if creates++; creates == 1 {
+						return kerrors.NewAlreadyExists(batchv1.Resource("jobs"), obj.GetName())
+					}
+					return c.Create(ctx, obj, opts...)
+				},
+			}).
+			Build()
+		target := NewJob("default", "warm-cache", "Deployment/default/cache", fakeClient)
+
+		require.EqualError(t, target.Trigger(t.Context()), "job default/warm-cache is still being deleted")
+		require.NotNil(t, target.PendingRerun())
+		assert.True(t, kerrors.IsNotFound(fakeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "warm-cache"}, &batchv1.Job{})))
+
+		// A new target resumes the persisted re-run, since the deleted Job cannot be fetched anymore.
+		resumed := NewJob("default", "warm-cache", "Deployment/default/cache", fakeClient)
+		resumed.ResumeRerun(target.PendingRerun())
+		require.NoError(t, resumed.Trigger(t.Context()))
+		assert.Nil(t, resumed.PendingRerun())
+
+		job := &batchv1.Job{}
+		require.NoError(t, fakeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "warm-cache"}, job))
+		assert.Equal(t, "Deployment/default/cache", job.Annotations[flag.TriggeredByAnnotation])
+	})
+
+	t.Run("Running Job", func(t *testing.T) {
+		t.Parallel()
+
+		running := newFinishedJob(batchv1.JobComplete)
+		running.Status = batchv1.JobStatus{Active: 1}
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(running).Build()
+		target := NewJob("default", "warm-cache", "Deployment/default/cache", fakeClient)
+
+		err := target.Trigger(t.Context())
+
+		require.EqualError(t, err, "job default/warm-cache is still running")
+		job := &batchv1.Job{}
+		require.NoError(t, fakeClient.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "warm-cache"}, job))
+		assert.Equal(t, types.UID("1234"), job.UID)
+	})
+
+	t.Run("Get Error", func(t *testing.T) {
+		t.Parallel()
+
+		mockClient := &testutils.MockClientWithError{
+			Client:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(newFinishedJob(batchv1.JobComplete)).Build(),
+			GetErrorFor: testutils.NamedError{Name: "warm-cache", Namespace: "default"},
+		}
+		target := NewJob("default", "warm-cache", "", mockClient)
+
+		err := target.Trigger(t.Context())
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "simulated get error")
+	})
+}
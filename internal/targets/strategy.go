@@ -0,0 +1,48 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targets
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Strategy defines how a target is restarted.
+type Strategy string
+
+const (
+	RolloutStrategy Strategy = "rollout" // Patches the pod template to trigger a rolling restart.
+	EvictStrategy   Strategy = "evict"   // Evicts the pods one at a time through the Eviction API.
+)
+
+// ParseStrategy converts an annotation value into a Strategy.
+// An empty value defaults to RolloutStrategy.
+func ParseStrategy(value string) (Strategy, error) {
+	switch s := Strategy(strings.ToLower(strings.TrimSpace(value))); s {
+	case "":
+		return RolloutStrategy, nil
+	case RolloutStrategy, EvictStrategy:
+		return s, nil
+	default:
+		return "", fmt.Errorf("unsupported restart strategy: %q", value)
+	}
+}
+
+// String converts the Strategy to its string representation.
+func (s Strategy) String() string {
+	return string(s)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStrategy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Empty defaults to rollout", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := ParseStrategy("")
+		require.NoError(t, err)
+		assert.Equal(t, RolloutStrategy, s)
+	})
+
+	t.Run("Evict is case insensitive", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := ParseStrategy(" Evict ")
+		require.NoError(t, err)
+		assert.Equal(t, EvictStrategy, s)
+	})
+
+	t.Run("Unsupported strategy", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseStrategy("recreate")
+		require.Error(t, err)
+		assert.EqualError(t, err, "unsupported restart strategy: \"recreate\"")
+	})
+}
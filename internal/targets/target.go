@@ -22,6 +22,7 @@ import (
 
 	"github.com/thurgauerkb/cascader/internal/kinds"
 	"github.com/thurgauerkb/cascader/internal/utils"
+	"github.com/thurgauerkb/cascader/internal/workloads"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -50,7 +51,20 @@ func NewTarget(ctx context.Context, c client.Client, kind kinds.Kind, ref string
 		return NewStatefulSet(ns, name, c), nil
 	case kinds.DaemonSetKind:
 		return NewDaemonSet(ns, name, c), nil
+	case kinds.JobKind:
+		return NewJob(ns, name, sourceID(source), c), nil
+	case kinds.CronJobKind:
+		return NewCronJob(ns, name, sourceID(source), c), nil
 	default:
 		return nil, fmt.Errorf("unsupported target kind: %s", kind)
 	}
 }
+
+// sourceID returns the ID of the source workload, or an empty string for unsupported sources.
+func sourceID(source client.Object) string {
+	workload, err := workloads.FromObject(source)
+	if err != nil {
+		return ""
+	}
+	return workload.ID()
+}
@@ -97,6 +97,40 @@ func TestCreateTarget(t *testing.T) {
 		assert.NotNil(t, target, "Expected a non-nil target for DaemonSet")
 	})
 
+	t.Run("Valid Job Target", func(t *testing.T) {
+		t.Parallel()
+
+		origin := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cache",
+				Namespace: "default",
+			},
+		}
+
+		target, err := NewTarget(t.Context(), mockClient, kinds.JobKind, "warm-cache", origin)
+
+		require.NoError(t, err, "Expected no error for Job target creation")
+		require.IsType(t, &JobTarget{}, target)
+		assert.Equal(t, "Deployment/default/cache", target.(*JobTarget).triggeredBy)
+	})
+
+	t.Run("Valid CronJob Target", func(t *testing.T) {
+		t.Parallel()
+
+		origin := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cache",
+				Namespace: "default",
+			},
+		}
+
+		target, err := NewTarget(t.Context(), mockClient, kinds.CronJobKind, "default/cache-warmer", origin)
+
+		require.NoError(t, err, "Expected no error for CronJob target creation")
+		require.IsType(t, &CronJobTarget{}, target)
+		assert.Equal(t, "Deployment/default/cache", target.(*CronJobTarget).triggeredBy)
+	})
+
 	t.Run("Unsupported Workload Type", func(t *testing.T) {
 		t.Parallel()
 
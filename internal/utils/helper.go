@@ -26,6 +26,8 @@ import (
 	"github.com/thurgauerkb/cascader/internal/kinds"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -74,6 +76,19 @@ func ToCacheOptions(watchNamespaces []string) cache.Options {
 	}
 }
 
+// WithConfigMap restricts the cached ConfigMaps to the given ConfigMap, so that it can be read from the
+// cache without watching all ConfigMaps. Only one ConfigMap can be cached.
+func WithConfigMap(opts cache.Options, key types.NamespacedName) cache.Options {
+	if opts.ByObject == nil {
+		opts.ByObject = map[client.Object]cache.ByObject{}
+	}
+	opts.ByObject[&corev1.ConfigMap{}] = cache.ByObject{
+		Namespaces: map[string]cache.Config{key.Namespace: {}},
+		Field:      fields.OneTermEqualSelector("metadata.name", key.Name),
+	}
+	return opts
+}
+
 // ParseTargetRef splits a target reference (e.g. "namespace/name") into its namespace and name.
 // If the reference lacks a namespace, defaultNS is used.
 func ParseTargetRef(ref, defaultNS string) (namespace, name string, err error) {
@@ -96,6 +111,15 @@ func GenerateID(kind kinds.Kind, namespace, name string) string {
 	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
 }
 
+// ParseID splits an identifier in the format "Kind/namespace/name" into its parts.
+func ParseID(id string) (kind kinds.Kind, namespace, name string, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("invalid identifier %q: expected Kind/namespace/name", id)
+	}
+	return kinds.Kind(parts[0]), parts[1], parts[2], nil
+}
+
 // PatchPodTemplateAnnotation updates the given annotation key in the pod template spec
 // and patches the parent object using server-side merge.
 func PatchPodTemplateAnnotation(
@@ -17,6 +17,7 @@ limitations under the License.
 package utils
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -29,6 +30,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -157,6 +160,23 @@ func TestToCacheOptions(t *testing.T) {
 	})
 }
 
+func TestWithConfigMap(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Restricts cached ConfigMaps", func(t *testing.T) {
+		t.Parallel()
+
+		opts := WithConfigMap(ToCacheOptions([]string{"ns1"}), types.NamespacedName{Namespace: "cascader-system", Name: "groups"})
+		assert.Len(t, opts.DefaultNamespaces, 1)
+		require.Len(t, opts.ByObject, 1)
+		for obj, byObject := range opts.ByObject {
+			assert.IsType(t, &corev1.ConfigMap{}, obj)
+			assert.Equal(t, map[string]cache.Config{"cascader-system": {}}, byObject.Namespaces)
+			assert.Equal(t, "metadata.name=groups", byObject.Field.String())
+		}
+	})
+}
+
 func TestParseTargetRef(t *testing.T) {
 	t.Parallel()
 
@@ -261,6 +281,29 @@ func TestGenerateID(t *testing.T) {
 	})
 }
 
+func TestParseID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Valid identifier", func(t *testing.T) {
+		t.Parallel()
+
+		kind, namespace, name, err := ParseID("Deployment/my-namespace/my-deployment")
+		require.NoError(t, err)
+		assert.Equal(t, kinds.DeploymentKind, kind)
+		assert.Equal(t, "my-namespace", namespace)
+		assert.Equal(t, "my-deployment", name)
+	})
+
+	for _, id := range []string{"", "Deployment/my-deployment", "Deployment//my-deployment", "Deployment/a/b/c"} {
+		t.Run("Invalid identifier "+id, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, _, err := ParseID(id)
+			require.EqualError(t, err, fmt.Sprintf("invalid identifier %q: expected Kind/namespace/name", id))
+		})
+	}
+}
+
 func TestPatchPodTemplateAnnotation(t *testing.T) {
 	t.Parallel()
 
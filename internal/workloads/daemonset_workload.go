@@ -79,3 +79,19 @@ func (w *DaemonSetWorkload) Stable() (isStable bool, reason string) {
 
 	return true, fmt.Sprintf("workload is stable: ready=%d, desired=%d", numberReady, desiredNumberScheduled)
 }
+
+// RollingOut checks if the DaemonSet is still replacing Pods of a previous revision.
+// Unlike Stable, it ignores the readiness of the Pods.
+func (w *DaemonSetWorkload) RollingOut() (rolling bool, reason string) {
+	ds := w.DaemonSet
+
+	if ds.Status.ObservedGeneration < ds.Generation {
+		return true, fmt.Sprintf("rollout in progress: observedGeneration=%d, generation=%d", ds.Status.ObservedGeneration, ds.Generation)
+	}
+
+	if ds.Status.UpdatedNumberScheduled < ds.Status.DesiredNumberScheduled {
+		return true, fmt.Sprintf("not all replicas are updated: updated=%d, desired=%d", ds.Status.UpdatedNumberScheduled, ds.Status.DesiredNumberScheduled)
+	}
+
+	return false, ""
+}
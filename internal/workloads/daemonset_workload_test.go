@@ -254,3 +254,50 @@ func TestDaemonSetWorkload_IsStable(t *testing.T) {
 		assert.Equal(t, "scaled to zero replicas", msg)
 	})
 }
+
+func TestDaemonSetWorkload_RollingOut(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		generation int64
+		status     appsv1.DaemonSetStatus
+		rolling    bool
+		reason     string
+	}{
+		{
+			name:   "Settled with unready replica",
+			status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberReady: 2},
+		},
+		{
+			name:       "Generation not observed",
+			generation: 2,
+			status:     appsv1.DaemonSetStatus{ObservedGeneration: 1},
+			rolling:    true,
+			reason:     "rollout in progress: observedGeneration=1, generation=2",
+		},
+		{
+			name:    "Not all replicas updated",
+			status:  appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, UpdatedNumberScheduled: 1},
+			rolling: true,
+			reason:  "not all replicas are updated: updated=1, desired=3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			workload := DaemonSetWorkload{
+				DaemonSet: &appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{Generation: tt.generation},
+					Status:     tt.status,
+				},
+			}
+			rolling, reason := workload.RollingOut()
+
+			assert.Equal(t, tt.rolling, rolling)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
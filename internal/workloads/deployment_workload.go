@@ -80,3 +80,20 @@ func (w *DeploymentWorkload) Stable() (isStable bool, reason string) {
 
 	return true, fmt.Sprintf("workload is stable: ready=%d, desired=%d", ready, desired)
 }
+
+// RollingOut checks if the Deployment is still replacing Pods of a previous revision.
+// Unlike Stable, it ignores the readiness of the Pods.
+func (w *DeploymentWorkload) RollingOut() (rolling bool, reason string) {
+	dep := w.Deployment
+
+	if dep.Status.ObservedGeneration < dep.Generation {
+		return true, fmt.Sprintf("rollout in progress: observedGeneration=%d, generation=%d", dep.Status.ObservedGeneration, dep.Generation)
+	}
+
+	// Replicas includes Pods of old ReplicaSets which were not scaled down yet.
+	if dep.Status.Replicas > dep.Status.UpdatedReplicas {
+		return true, fmt.Sprintf("old replicas pending: replicas=%d, updated=%d", dep.Status.Replicas, dep.Status.UpdatedReplicas)
+	}
+
+	return false, ""
+}
@@ -266,3 +266,51 @@ func TestDeploymentWorkload_IsStable(t *testing.T) {
 		assert.Equal(t, "scaled to zero replicas", msg)
 	})
 }
+
+func TestDeploymentWorkload_RollingOut(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		generation int64
+		status     appsv1.DeploymentStatus
+		rolling    bool
+		reason     string
+	}{
+		{
+			name:   "Settled with unavailable replica",
+			status: appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 3, ReadyReplicas: 2, UnavailableReplicas: 1},
+		},
+		{
+			name:       "Generation not observed",
+			generation: 2,
+			status:     appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 3, UpdatedReplicas: 3},
+			rolling:    true,
+			reason:     "rollout in progress: observedGeneration=1, generation=2",
+		},
+		{
+			name:    "Old replicas pending",
+			status:  appsv1.DeploymentStatus{Replicas: 4, UpdatedReplicas: 2, ReadyReplicas: 3},
+			rolling: true,
+			reason:  "old replicas pending: replicas=4, updated=2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			workload := DeploymentWorkload{
+				Deployment: &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Generation: tt.generation},
+					Spec:       appsv1.DeploymentSpec{Replicas: testutils.Int32Ptr(3)},
+					Status:     tt.status,
+				},
+			}
+			rolling, reason := workload.RollingOut()
+
+			assert.Equal(t, tt.rolling, rolling)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
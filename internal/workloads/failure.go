@@ -0,0 +1,127 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// failedWaitingReasons are container waiting reasons which indicate a failed rollout.
+var failedWaitingReasons = map[string]bool{
+	"CrashLoopBackOff": true,
+	"ImagePullBackOff": true,
+}
+
+// RolloutFailure reports why the rollout of a workload failed, or an empty string if it did not fail.
+// Deployments fail once the progress deadline of their current generation is exceeded; StatefulSets and
+// DaemonSets fail once one of their Pods of the update revision is in CrashLoopBackOff or ImagePullBackOff;
+// Jobs once they failed.
+func RolloutFailure(ctx context.Context, c client.Client, w Workload) (string, error) {
+	switch res := w.Resource().(type) {
+	case *appsv1.Deployment:
+		// The conditions of an older generation do not tell anything about the current rollout.
+		if res.Status.ObservedGeneration != res.Generation {
+			return "", nil
+		}
+		for _, cond := range res.Status.Conditions {
+			if cond.Type == appsv1.DeploymentProgressing && cond.Status == corev1.ConditionFalse && cond.Reason == "ProgressDeadlineExceeded" {
+				return fmt.Sprintf("progress deadline exceeded: %s", cond.Message), nil
+			}
+		}
+		return "", nil
+
+	case *batchv1.Job:
+		_, reason := JobFailed(res)
+		return reason, nil
+
+	case *appsv1.StatefulSet, *appsv1.DaemonSet:
+		revision, err := updateRevision(ctx, c, res)
+		if err != nil || revision == "" {
+			return "", err
+		}
+		pods, err := ListPods(ctx, c, res)
+		if err != nil {
+			return "", err
+		}
+		// Pods of the previous revision may still fail while they are being replaced.
+		pods = slices.DeleteFunc(pods, func(pod corev1.Pod) bool {
+			return pod.Labels[appsv1.ControllerRevisionHashLabelKey] != revision
+		})
+		return failedPod(pods), nil
+
+	default:
+		return "", fmt.Errorf("unsupported workload type: %T", res)
+	}
+}
+
+// updateRevision returns the controller-revision-hash label of the Pods of the revision a StatefulSet or
+// DaemonSet rolls out, or an empty string if it is not known yet.
+func updateRevision(ctx context.Context, c client.Client, obj client.Object) (string, error) {
+	ds, ok := obj.(*appsv1.DaemonSet)
+	if !ok {
+		return obj.(*appsv1.StatefulSet).Status.UpdateRevision, nil
+	}
+
+	// DaemonSets do not report their update revision, which is the newest ControllerRevision they own.
+	selector, err := PodSelector(ds)
+	if err != nil {
+		return "", err
+	}
+	revisions := &appsv1.ControllerRevisionList{}
+	if err := c.List(ctx, revisions,
+		client.InNamespace(ds.Namespace),
+		client.MatchingLabelsSelector{Selector: selector},
+	); err != nil {
+		return "", fmt.Errorf("failed to list controller revisions for %s/%s: %w", ds.Namespace, ds.Name, err)
+	}
+
+	var latest *appsv1.ControllerRevision
+	for i := range revisions.Items {
+		rev := &revisions.Items[i]
+		if !metav1.IsControlledBy(rev, ds) {
+			continue
+		}
+		if latest == nil || rev.Revision > latest.Revision {
+			latest = rev
+		}
+	}
+	if latest == nil {
+		return "", nil
+	}
+	return latest.Labels[appsv1.ControllerRevisionHashLabelKey], nil
+}
+
+// failedPod returns a description of the first Pod with a failed container, or an empty string.
+func failedPod(pods []corev1.Pod) string {
+	for _, pod := range pods {
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, cs := range statuses {
+			if cs.State.Waiting != nil && failedWaitingReasons[cs.State.Waiting.Reason] {
+				return fmt.Sprintf("pod %s container %s is in %s", pod.Name, cs.Name, cs.State.Waiting.Reason)
+			}
+		}
+	}
+	return ""
+}
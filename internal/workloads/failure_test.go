@@ -0,0 +1,204 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloads
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRolloutFailure(t *testing.T) {
+	t.Parallel()
+
+	newPod := func(name, revision, waitingReason string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"app": "db", appsv1.ControllerRevisionHashLabelKey: revision},
+			},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "app",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: waitingReason}},
+			}}},
+		}
+	}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+		},
+		Status: appsv1.StatefulSetStatus{CurrentRevision: "db-1", UpdateRevision: "db-2"},
+	}
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", UID: "agent-uid"},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+		},
+	}
+	newRevision := func(hash string, revision int64, owner client.Object) *appsv1.ControllerRevision {
+		return &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            owner.GetName() + "-" + hash,
+				Namespace:       "default",
+				Labels:          map[string]string{"app": "db", appsv1.ControllerRevisionHashLabelKey: hash},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(owner, appsv1.SchemeGroupVersion.WithKind("DaemonSet"))},
+			},
+			Revision: revision,
+		}
+	}
+	newDeployment := func(generation, observed int64) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: generation},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: observed,
+				Conditions: []appsv1.DeploymentCondition{{
+					Type:    appsv1.DeploymentProgressing,
+					Status:  corev1.ConditionFalse,
+					Reason:  "ProgressDeadlineExceeded",
+					Message: `ReplicaSet "web-123" has timed out progressing.`,
+				}},
+			},
+		}
+	}
+
+	t.Run("Deployment progress deadline exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		reason, err := RolloutFailure(t.Context(), nil, &DeploymentWorkload{Deployment: newDeployment(2, 2)})
+		require.NoError(t, err)
+		assert.Equal(t, `progress deadline exceeded: ReplicaSet "web-123" has timed out progressing.`, reason)
+	})
+
+	t.Run("Deployment progress deadline exceeded by previous generation", func(t *testing.T) {
+		t.Parallel()
+
+		reason, err := RolloutFailure(t.Context(), nil, &DeploymentWorkload{Deployment: newDeployment(3, 2)})
+		require.NoError(t, err)
+		assert.Empty(t, reason)
+	})
+
+	t.Run("Deployment progressing", func(t *testing.T) {
+		t.Parallel()
+
+		dep := &appsv1.Deployment{Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{{
+			Type:   appsv1.DeploymentProgressing,
+			Status: corev1.ConditionTrue,
+			Reason: "ReplicaSetUpdated",
+		}}}}
+
+		reason, err := RolloutFailure(t.Context(), nil, &DeploymentWorkload{Deployment: dep})
+		require.NoError(t, err)
+		assert.Empty(t, reason)
+	})
+
+	t.Run("Job failed", func(t *testing.T) {
+		t.Parallel()
+
+		job := &batchv1.Job{Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{
+			Type:   batchv1.JobFailed,
+			Status: corev1.ConditionTrue,
+			Reason: "BackoffLimitExceeded",
+		}}}}
+
+		reason, err := RolloutFailure(t.Context(), nil, &JobWorkload{Job: job})
+		require.NoError(t, err)
+		assert.Equal(t, "job failed: BackoffLimitExceeded", reason)
+	})
+
+	t.Run("StatefulSet pod in CrashLoopBackOff", func(t *testing.T) {
+		t.Parallel()
+
+		c := fake.NewClientBuilder().WithObjects(newPod("db-0", "db-2", "ContainerCreating"), newPod("db-1", "db-2", "CrashLoopBackOff")).Build()
+
+		reason, err := RolloutFailure(t.Context(), c, &StatefulSetWorkload{StatefulSet: sts})
+		require.NoError(t, err)
+		assert.Equal(t, "pod db-1 container app is in CrashLoopBackOff", reason)
+	})
+
+	t.Run("StatefulSet pod of previous revision in CrashLoopBackOff", func(t *testing.T) {
+		t.Parallel()
+
+		c := fake.NewClientBuilder().WithObjects(newPod("db-0", "db-1", "CrashLoopBackOff"), newPod("db-1", "db-2", "ContainerCreating")).Build()
+
+		reason, err := RolloutFailure(t.Context(), c, &StatefulSetWorkload{StatefulSet: sts})
+		require.NoError(t, err)
+		assert.Empty(t, reason)
+	})
+
+	t.Run("StatefulSet update revision unknown", func(t *testing.T) {
+		t.Parallel()
+
+		unknown := sts.DeepCopy()
+		unknown.Status = appsv1.StatefulSetStatus{}
+		c := fake.NewClientBuilder().WithObjects(newPod("db-0", "db-1", "CrashLoopBackOff")).Build()
+
+		reason, err := RolloutFailure(t.Context(), c, &StatefulSetWorkload{StatefulSet: unknown})
+		require.NoError(t, err)
+		assert.Empty(t, reason)
+	})
+
+	t.Run("StatefulSet pods starting", func(t *testing.T) {
+		t.Parallel()
+
+		c := fake.NewClientBuilder().WithObjects(newPod("db-0", "db-2", "ContainerCreating")).Build()
+
+		reason, err := RolloutFailure(t.Context(), c, &StatefulSetWorkload{StatefulSet: sts})
+		require.NoError(t, err)
+		assert.Empty(t, reason)
+	})
+
+	t.Run("DaemonSet pod in ImagePullBackOff", func(t *testing.T) {
+		t.Parallel()
+
+		c := fake.NewClientBuilder().WithObjects(
+			newRevision("old", 1, ds),
+			newRevision("new", 2, ds),
+			newPod("agent-x", "new", "ImagePullBackOff"),
+		).Build()
+
+		reason, err := RolloutFailure(t.Context(), c, &DaemonSetWorkload{DaemonSet: ds})
+		require.NoError(t, err)
+		assert.Equal(t, "pod agent-x container app is in ImagePullBackOff", reason)
+	})
+
+	t.Run("DaemonSet pod of previous revision in ImagePullBackOff", func(t *testing.T) {
+		t.Parallel()
+
+		other := ds.DeepCopy()
+		other.Name, other.UID = "other", "other-uid"
+		c := fake.NewClientBuilder().WithObjects(
+			newRevision("old", 1, ds),
+			newRevision("new", 2, ds),
+			newRevision("foreign", 3, other),
+			newPod("agent-x", "old", "ImagePullBackOff"),
+			newPod("agent-y", "foreign", "ImagePullBackOff"),
+		).Build()
+
+		reason, err := RolloutFailure(t.Context(), c, &DaemonSetWorkload{DaemonSet: ds})
+		require.NoError(t, err)
+		assert.Empty(t, reason)
+	})
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloads
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ContainerRestarts returns the restart counts of all containers of the given Pods,
+// keyed by "<pod>/<container>". Init containers are included, since sidecars may restart as well.
+func ContainerRestarts(pods []corev1.Pod) map[string]int32 {
+	restarts := make(map[string]int32)
+	for _, pod := range pods {
+		for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+			for _, cs := range statuses {
+				restarts[pod.Name+"/"+cs.Name] = cs.RestartCount
+			}
+		}
+	}
+	return restarts
+}
+
+// RestartedContainers returns the sorted keys of the containers whose restart count increased
+// compared to the baseline. Containers missing from the baseline are ignored.
+func RestartedContainers(baseline, current map[string]int32) []string {
+	var restarted []string
+	for key, count := range current {
+		if prev, ok := baseline[key]; ok && count > prev {
+			restarted = append(restarted, key)
+		}
+	}
+	sort.Strings(restarted)
+	return restarted
+}
+
+// PodsReady checks if all given Pods report the Ready condition.
+// Returns the reason for the first Pod which is not ready.
+func PodsReady(pods []corev1.Pod) (bool, string) {
+	if len(pods) == 0 {
+		return false, "no pods found"
+	}
+	for _, pod := range pods {
+		if !PodReady(&pod) {
+			return false, fmt.Sprintf("pod %s is not ready", pod.Name)
+		}
+	}
+	return true, fmt.Sprintf("all %d pods are ready", len(pods))
+}
+
+// PodReady returns true if the Pod reports the Ready condition.
+func PodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
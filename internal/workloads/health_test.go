@@ -0,0 +1,119 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloads
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func podWithHealth(name string, ready bool, restarts ...int32) corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+	for i, count := range restarts {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:         string(rune('a' + i)),
+			RestartCount: count,
+		})
+	}
+	return pod
+}
+
+func TestContainerRestarts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Includes init containers", func(t *testing.T) {
+		t.Parallel()
+
+		pod := podWithHealth("db-0", true, 2)
+		pod.Status.InitContainerStatuses = []corev1.ContainerStatus{{Name: "sidecar", RestartCount: 1}}
+
+		assert.Equal(t, map[string]int32{"db-0/a": 2, "db-0/sidecar": 1}, ContainerRestarts([]corev1.Pod{pod}))
+	})
+
+	t.Run("No pods", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, ContainerRestarts(nil))
+	})
+}
+
+func TestRestartedContainers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Reports increased counts", func(t *testing.T) {
+		t.Parallel()
+
+		baseline := map[string]int32{"db-0/a": 1, "db-1/a": 0, "db-2/a": 3}
+		current := map[string]int32{"db-0/a": 2, "db-1/a": 1, "db-2/a": 3}
+
+		assert.Equal(t, []string{"db-0/a", "db-1/a"}, RestartedContainers(baseline, current))
+	})
+
+	t.Run("Ignores new containers", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, RestartedContainers(map[string]int32{}, map[string]int32{"db-0/a": 4}))
+	})
+}
+
+func TestPodsReady(t *testing.T) {
+	t.Parallel()
+
+	t.Run("All pods ready", func(t *testing.T) {
+		t.Parallel()
+
+		ready, reason := PodsReady([]corev1.Pod{podWithHealth("db-0", true), podWithHealth("db-1", true)})
+		assert.True(t, ready)
+		assert.Equal(t, "all 2 pods are ready", reason)
+	})
+
+	t.Run("Pod not ready", func(t *testing.T) {
+		t.Parallel()
+
+		ready, reason := PodsReady([]corev1.Pod{podWithHealth("db-0", true), podWithHealth("db-1", false)})
+		assert.False(t, ready)
+		assert.Equal(t, "pod db-1 is not ready", reason)
+	})
+
+	t.Run("Pod without conditions", func(t *testing.T) {
+		t.Parallel()
+
+		ready, reason := PodsReady([]corev1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "db-0"}}})
+		assert.False(t, ready)
+		assert.Equal(t, "pod db-0 is not ready", reason)
+	})
+
+	t.Run("No pods", func(t *testing.T) {
+		t.Parallel()
+
+		ready, reason := PodsReady(nil)
+		assert.False(t, ready)
+		assert.Equal(t, "no pods found", reason)
+	})
+}
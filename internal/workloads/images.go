@@ -0,0 +1,66 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloads
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodImageIDs returns the sorted resolved images of the containers of a Pod,
+// or an empty string if any container has not resolved its image yet.
+func PodImageIDs(pod *corev1.Pod) string {
+	if len(pod.Status.ContainerStatuses) == 0 {
+		return ""
+	}
+
+	ids := make([]string, 0, len(pod.Status.ContainerStatuses))
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.ImageID == "" {
+			return ""
+		}
+		ids = append(ids, cs.Name+"="+cs.ImageID)
+	}
+	slices.Sort(ids)
+
+	return strings.Join(ids, ",")
+}
+
+// ImageDigests returns a fingerprint of the resolved container images of the given Pods.
+// It reports false unless all Pods run the same resolved images, e.g. while a rollout is in progress.
+func ImageDigests(pods []corev1.Pod) (string, bool) {
+	if len(pods) == 0 {
+		return "", false
+	}
+
+	var ids string
+	for i := range pods {
+		podIDs := PodImageIDs(&pods[i])
+		if podIDs == "" || (i > 0 && podIDs != ids) {
+			return "", false
+		}
+		ids = podIDs
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(ids))
+	return fmt.Sprintf("%x", h.Sum64()), true
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloads
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func podWithImages(imageIDs ...string) corev1.Pod {
+	pod := corev1.Pod{}
+	for i, id := range imageIDs {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:    string(rune('a' + i)),
+			ImageID: id,
+		})
+	}
+	return pod
+}
+
+func TestPodImageIDs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Sorted by container", func(t *testing.T) {
+		t.Parallel()
+
+		pod := corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "sidecar", ImageID: "proxy@sha256:2"},
+			{Name: "app", ImageID: "app@sha256:1"},
+		}}}
+		assert.Equal(t, "app=app@sha256:1,sidecar=proxy@sha256:2", PodImageIDs(&pod))
+	})
+
+	t.Run("Unresolved image", func(t *testing.T) {
+		t.Parallel()
+
+		pod := podWithImages("app@sha256:1", "")
+		assert.Empty(t, PodImageIDs(&pod))
+	})
+
+	t.Run("No container statuses", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, PodImageIDs(&corev1.Pod{}))
+	})
+}
+
+func TestImageDigests(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Uniform pods", func(t *testing.T) {
+		t.Parallel()
+
+		digests, ok := ImageDigests([]corev1.Pod{podWithImages("app@sha256:1"), podWithImages("app@sha256:1")})
+		assert.True(t, ok)
+		assert.NotEmpty(t, digests)
+
+		other, ok := ImageDigests([]corev1.Pod{podWithImages("app@sha256:2")})
+		assert.True(t, ok)
+		assert.NotEqual(t, digests, other)
+	})
+
+	t.Run("Pods with different images", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := ImageDigests([]corev1.Pod{podWithImages("app@sha256:1"), podWithImages("app@sha256:2")})
+		assert.False(t, ok)
+	})
+
+	t.Run("Pod without resolved image", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := ImageDigests([]corev1.Pod{podWithImages("app@sha256:1"), podWithImages("")})
+		assert.False(t, ok)
+	})
+
+	t.Run("No pods", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := ImageDigests(nil)
+		assert.False(t, ok)
+	})
+}
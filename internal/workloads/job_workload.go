@@ -0,0 +1,111 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloads
+
+import (
+	"fmt"
+
+	"github.com/thurgauerkb/cascader/internal/kinds"
+	"github.com/thurgauerkb/cascader/internal/utils"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// JobWorkload implements the Workload interface for Jobs. Jobs are stable once they completed.
+type JobWorkload struct {
+	Job *batchv1.Job
+}
+
+func (w *JobWorkload) GetName() string         { return w.Job.GetName() }
+func (w *JobWorkload) GetNamespace() string    { return w.Job.GetNamespace() }
+func (w *JobWorkload) Resource() client.Object { return w.Job }
+func (w *JobWorkload) Kind() kinds.Kind        { return kinds.JobKind }
+func (w *JobWorkload) ID() string {
+	return utils.GenerateID(w.Kind(), w.Job.GetNamespace(), w.Job.GetName())
+}
+
+func (w *JobWorkload) PodTemplateSpec() *corev1.PodTemplateSpec {
+	return &w.Job.Spec.Template
+}
+
+// Stable checks if the Job completed successfully.
+func (w *JobWorkload) Stable() (isStable bool, reason string) {
+	job := w.Job
+
+	if JobComplete(job) {
+		return true, fmt.Sprintf("job completed: succeeded=%d", job.Status.Succeeded)
+	}
+
+	if failed, reason := JobFailed(job); failed {
+		return false, reason
+	}
+
+	return false, fmt.Sprintf("job not complete: active=%d, succeeded=%d, failed=%d", job.Status.Active, job.Status.Succeeded, job.Status.Failed)
+}
+
+// RollingOut reports whether the Job is still running. Jobs run to completion instead of rolling out,
+// so a running Job target is only re-run once it finished.
+func (w *JobWorkload) RollingOut() (rolling bool, reason string) {
+	job := w.Job
+
+	if JobFinished(job) || JobSuspended(job) {
+		return false, ""
+	}
+
+	return true, fmt.Sprintf("job is running: active=%d", job.Status.Active)
+}
+
+// JobComplete reports whether the Job has the Complete condition.
+func JobComplete(job *batchv1.Job) bool {
+	return jobCondition(job, batchv1.JobComplete) != nil
+}
+
+// JobFailed reports whether the Job has the Failed condition, along with the reason of the failure.
+func JobFailed(job *batchv1.Job) (failed bool, reason string) {
+	cond := jobCondition(job, batchv1.JobFailed)
+	if cond == nil {
+		return false, ""
+	}
+	if cond.Message == "" {
+		return true, fmt.Sprintf("job failed: %s", cond.Reason)
+	}
+	return true, fmt.Sprintf("job failed: %s: %s", cond.Reason, cond.Message)
+}
+
+// JobSuspended reports whether the Job is suspended.
+func JobSuspended(job *batchv1.Job) bool {
+	return job.Spec.Suspend != nil && *job.Spec.Suspend
+}
+
+// JobFinished reports whether the Job completed or failed.
+func JobFinished(job *batchv1.Job) bool {
+	failed, _ := JobFailed(job)
+	return failed || JobComplete(job)
+}
+
+// jobCondition returns the condition of the given type if it is true, or nil.
+func jobCondition(job *batchv1.Job, condType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		cond := &job.Status.Conditions[i]
+		if cond.Type == condType && cond.Status == corev1.ConditionTrue {
+			return cond
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloads
+
+import (
+	"testing"
+
+	"github.com/thurgauerkb/cascader/internal/kinds"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestJobWorkload_Methods(t *testing.T) {
+	t.Parallel()
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "migrate",
+			Namespace: "default",
+		},
+	}
+	w := JobWorkload{Job: job}
+
+	assert.Equal(t, "migrate", w.GetName())
+	assert.Equal(t, "default", w.GetNamespace())
+	assert.Equal(t, job, w.Resource())
+	assert.Equal(t, kinds.JobKind, w.Kind())
+	assert.Equal(t, "Job/default/migrate", w.ID())
+	assert.Equal(t, &job.Spec.Template, w.PodTemplateSpec())
+}
+
+func TestJobWorkload_RollingOut(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		suspend    *bool
+		status     batchv1.JobStatus
+		expRolling bool
+		expReason  string
+	}{
+		{
+			name:       "Running Job",
+			status:     batchv1.JobStatus{Active: 2},
+			expRolling: true,
+			expReason:  "job is running: active=2",
+		},
+		{
+			name: "Completed Job",
+			status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+			},
+		},
+		{
+			name: "Failed Job",
+			status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}},
+			},
+		},
+		{
+			name:    "Suspended Job",
+			suspend: ptr.To(true),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			job := &batchv1.Job{Spec: batchv1.JobSpec{Suspend: tt.suspend}, Status: tt.status}
+			w := JobWorkload{Job: job}
+
+			rolling, reason := w.RollingOut()
+			assert.Equal(t, tt.expRolling, rolling)
+			assert.Equal(t, tt.expReason, reason)
+		})
+	}
+}
+
+func TestJobWorkload_IsStable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		status    batchv1.JobStatus
+		expStable bool
+		expReason string
+	}{
+		{
+			name: "Completed Job",
+			status: batchv1.JobStatus{
+				Succeeded: 1,
+				Conditions: []batchv1.JobCondition{
+					{Type: batchv1.JobSuccessCriteriaMet, Status: corev1.ConditionTrue},
+					{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+				},
+			},
+			expStable: true,
+			expReason: "job completed: succeeded=1",
+		},
+		{
+			name: "Failed Job",
+			status: batchv1.JobStatus{
+				Failed: 7,
+				Conditions: []batchv1.JobCondition{
+					{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded", Message: "Job has reached the specified backoff limit"},
+				},
+			},
+			expStable: false,
+			expReason: "job failed: BackoffLimitExceeded: Job has reached the specified backoff limit",
+		},
+		{
+			name: "Failed Job without message",
+			status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{
+					{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "DeadlineExceeded"},
+				},
+			},
+			expStable: false,
+			expReason: "job failed: DeadlineExceeded",
+		},
+		{
+			name: "Running Job",
+			status: batchv1.JobStatus{
+				Active: 1,
+				Failed: 2,
+				Conditions: []batchv1.JobCondition{
+					{Type: batchv1.JobComplete, Status: corev1.ConditionFalse},
+				},
+			},
+			expStable: false,
+			expReason: "job not complete: active=1, succeeded=0, failed=2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := JobWorkload{Job: &batchv1.Job{Status: tt.status}}
+			isStable, reason := w.Stable()
+
+			assert.Equal(t, tt.expStable, isStable)
+			assert.Equal(t, tt.expReason, reason)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloads
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PodSelector returns the label selector used by a workload to select its Pods.
+func PodSelector(obj client.Object) (labels.Selector, error) {
+	var selector *metav1.LabelSelector
+
+	switch res := obj.(type) {
+	case *appsv1.Deployment:
+		selector = res.Spec.Selector
+	case *appsv1.StatefulSet:
+		selector = res.Spec.Selector
+	case *appsv1.DaemonSet:
+		selector = res.Spec.Selector
+	case *batchv1.Job:
+		selector = res.Spec.Selector
+	default:
+		return nil, fmt.Errorf("unsupported workload type: %T", obj)
+	}
+
+	if selector == nil {
+		return nil, fmt.Errorf("workload %s/%s has no pod selector", obj.GetNamespace(), obj.GetName())
+	}
+
+	return metav1.LabelSelectorAsSelector(selector)
+}
+
+// ListPods returns the Pods selected by the given workload that are not being deleted.
+func ListPods(ctx context.Context, c client.Client, obj client.Object) ([]corev1.Pod, error) {
+	selector, err := PodSelector(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	podList := &corev1.PodList{}
+	if err := c.List(ctx, podList,
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingLabelsSelector{Selector: selector},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list pods for %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	pods := make([]corev1.Pod, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+
+	return pods, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloads
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPodSelector(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Deployment selector", func(t *testing.T) {
+		t.Parallel()
+
+		dep := &appsv1.Deployment{
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+		}
+
+		selector, err := PodSelector(dep)
+		require.NoError(t, err)
+		assert.Equal(t, "app=web", selector.String())
+	})
+
+	t.Run("Missing selector", func(t *testing.T) {
+		t.Parallel()
+
+		sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"}}
+
+		_, err := PodSelector(sts)
+		require.Error(t, err)
+		assert.EqualError(t, err, "workload default/db has no pod selector")
+	})
+
+	t.Run("Unsupported type", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := PodSelector(&corev1.Pod{})
+		require.Error(t, err)
+		assert.EqualError(t, err, "unsupported workload type: *v1.Pod")
+	})
+}
+
+func TestListPods(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	_ = appsv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "agent"}},
+		},
+	}
+	running := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "agent-a", Namespace: "default", Labels: map[string]string{"app": "agent"},
+	}}
+	terminating := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "agent-b", Namespace: "default", Labels: map[string]string{"app": "agent"},
+		DeletionTimestamp: &metav1.Time{Time: time.Now()}, Finalizers: []string{"test"},
+	}}
+	otherNamespace := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "agent-c", Namespace: "other", Labels: map[string]string{"app": "agent"},
+	}}
+	otherApp := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "web", Namespace: "default", Labels: map[string]string{"app": "web"},
+	}}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(ds, running, terminating, otherNamespace, otherApp).
+		Build()
+
+	pods, err := ListPods(t.Context(), fakeClient, ds)
+	require.NoError(t, err)
+	require.Len(t, pods, 1)
+	assert.Equal(t, "agent-a", pods[0].Name)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloads
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodRestarts records the container restarts of the Pods of a source, to detect Pods replaced or
+// restarted in place without a change of the pod template.
+type PodRestarts struct {
+	Template string           `json:"template"`           // Hash of the pod template the Pods were recorded for.
+	Pods     map[string]int32 `json:"pods"`               // Container restarts per Pod UID.
+	Cascades int              `json:"cascades,omitempty"` // Number of restarts detected from replaced Pods.
+}
+
+// ParsePodRestarts parses recorded pod restarts. An empty value returns nil.
+func ParsePodRestarts(value string) (*PodRestarts, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	restarts := &PodRestarts{}
+	if err := json.Unmarshal([]byte(value), restarts); err != nil {
+		return nil, err
+	}
+	return restarts, nil
+}
+
+// PodRestartCount returns the total number of restarts of the containers of a Pod, including sidecars.
+func PodRestartCount(pod *corev1.Pod) int32 {
+	var restarts int32
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, cs := range statuses {
+			restarts += cs.RestartCount
+		}
+	}
+	return restarts
+}
+
+// RecordPodRestarts returns the container restarts of the given Pods by their UID.
+func RecordPodRestarts(pods []corev1.Pod) map[string]int32 {
+	restarts := make(map[string]int32, len(pods))
+	for i := range pods {
+		restarts[string(pods[i].UID)] = PodRestartCount(&pods[i])
+	}
+	return restarts
+}
+
+// ReplacedPods counts the Pods which were replaced or restarted a container since the baseline was recorded.
+// A Pod which disappeared only counts if another Pod took its place, so that scaling is not counted.
+func ReplacedPods(baseline, current map[string]int32) int {
+	var restarted, gone, added int
+	for uid, before := range baseline {
+		now, ok := current[uid]
+		switch {
+		case !ok:
+			gone++
+		case now > before:
+			restarted++
+		}
+	}
+	for uid := range current {
+		if _, ok := baseline[uid]; !ok {
+			added++
+		}
+	}
+	return restarted + min(gone, added)
+}
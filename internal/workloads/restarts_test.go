@@ -0,0 +1,99 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloads
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestParsePodRestarts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		restarts, err := ParsePodRestarts("")
+		require.NoError(t, err)
+		assert.Nil(t, restarts)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+
+		restarts, err := ParsePodRestarts(`{"template":"abc","pods":{"uid-1":2},"cascades":1}`)
+		require.NoError(t, err)
+		assert.Equal(t, &PodRestarts{Template: "abc", Pods: map[string]int32{"uid-1": 2}, Cascades: 1}, restarts)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParsePodRestarts("abc")
+		require.Error(t, err)
+	})
+}
+
+func TestRecordPodRestarts(t *testing.T) {
+	t.Parallel()
+
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{UID: types.UID("uid-1")},
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{Name: "sidecar", RestartCount: 1}},
+				ContainerStatuses:     []corev1.ContainerStatus{{Name: "app", RestartCount: 2}},
+			},
+		},
+		{ObjectMeta: metav1.ObjectMeta{UID: types.UID("uid-2")}},
+	}
+
+	assert.Equal(t, map[string]int32{"uid-1": 3, "uid-2": 0}, RecordPodRestarts(pods))
+}
+
+func TestReplacedPods(t *testing.T) {
+	t.Parallel()
+
+	baseline := map[string]int32{"a": 0, "b": 1, "c": 0}
+
+	tests := []struct {
+		name     string
+		current  map[string]int32
+		expected int
+	}{
+		{name: "Unchanged", current: map[string]int32{"a": 0, "b": 1, "c": 0}, expected: 0},
+		{name: "Container restarted", current: map[string]int32{"a": 0, "b": 2, "c": 0}, expected: 1},
+		{name: "Pod replaced", current: map[string]int32{"a": 0, "b": 1, "d": 0}, expected: 1},
+		{name: "All Pods replaced", current: map[string]int32{"d": 0, "e": 0, "f": 0}, expected: 3},
+		{name: "Replaced and restarted", current: map[string]int32{"a": 1, "b": 1, "d": 0}, expected: 2},
+		{name: "Scaled down", current: map[string]int32{"a": 0}, expected: 0},
+		{name: "Scaled up", current: map[string]int32{"a": 0, "b": 1, "c": 0, "d": 0}, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, ReplacedPods(baseline, tt.current))
+		})
+	}
+}
@@ -18,18 +18,46 @@ package workloads
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/thurgauerkb/cascader/internal/kinds"
 	"github.com/thurgauerkb/cascader/internal/utils"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// OnDeleteMode defines when a StatefulSet with the OnDelete update strategy is considered stable.
+type OnDeleteMode string
+
+const (
+	// OnDeleteImmediate considers the StatefulSet stable as soon as all replicas are ready,
+	// regardless of whether the Pods were replaced yet.
+	OnDeleteImmediate OnDeleteMode = "immediate"
+
+	// OnDeleteWait considers the StatefulSet stable once all Pods run the new revision.
+	OnDeleteWait OnDeleteMode = "wait"
+
+	// OnDeleteSkip does not cascade restarts of the StatefulSet at all.
+	OnDeleteSkip OnDeleteMode = "skip"
+)
+
+// ParseOnDeleteMode parses an OnDelete mode. The value is case-insensitive.
+func ParseOnDeleteMode(value string) (OnDeleteMode, error) {
+	switch mode := OnDeleteMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case OnDeleteImmediate, OnDeleteWait, OnDeleteSkip:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unsupported on-delete mode: %q", value)
+	}
+}
+
 // StatefulSetWorkload implements the workload interface for StatefulSets.
 type StatefulSetWorkload struct {
 	StatefulSet *appsv1.StatefulSet
+	OnDelete    OnDeleteMode // OnDelete defines the stability of StatefulSets with the OnDelete update strategy; defaults to wait.
 }
 
 func (w *StatefulSetWorkload) GetName() string         { return w.StatefulSet.GetName() }
@@ -44,7 +72,14 @@ func (w *StatefulSetWorkload) PodTemplateSpec() *corev1.PodTemplateSpec {
 	return &w.StatefulSet.Spec.Template
 }
 
-// Stable checks if the StatefulSet is stable based on its replica status.
+// OnDeleteStrategy returns true if the StatefulSet uses the OnDelete update strategy.
+func (w *StatefulSetWorkload) OnDeleteStrategy() bool {
+	return w.StatefulSet.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType
+}
+
+// Stable checks if the StatefulSet is stable based on its replica status, revisions and update strategy.
+// Partitioned rolling updates are stable once all Pods at or above the partition are updated, and
+// StatefulSets with the OnDelete update strategy are stable depending on the OnDelete mode.
 func (w *StatefulSetWorkload) Stable() (isStable bool, reason string) {
 	sts := w.StatefulSet
 	updated := sts.Status.UpdatedReplicas
@@ -59,8 +94,26 @@ func (w *StatefulSetWorkload) Stable() (isStable bool, reason string) {
 		return true, "scaled to zero replicas" // nolint:goconst
 	}
 
-	if updated != desired {
-		return false, fmt.Sprintf("not all replicas are updated: updated=%d, ready=%d, desired=%d", updated, ready, desired)
+	if w.OnDeleteStrategy() {
+		// Pods are only replaced once they are deleted, so the update progress depends on the mode.
+		if w.OnDelete != OnDeleteImmediate && updated != desired {
+			return false, fmt.Sprintf("waiting for pods to be replaced: updated=%d, ready=%d, desired=%d", updated, ready, desired)
+		}
+	} else {
+		partition := w.partition()
+		expected := max(desired-partition, 0)
+
+		if updated < expected {
+			if partition > 0 {
+				return false, fmt.Sprintf("not all replicas are updated: updated=%d, ready=%d, desired=%d, partition=%d", updated, ready, desired, partition)
+			}
+			return false, fmt.Sprintf("not all replicas are updated: updated=%d, ready=%d, desired=%d", updated, ready, desired)
+		}
+
+		// The current revision only catches up with the update revision once an unpartitioned rollout completed.
+		if partition == 0 && sts.Status.UpdateRevision != "" && sts.Status.CurrentRevision != sts.Status.UpdateRevision {
+			return false, fmt.Sprintf("revision rollout in progress: currentRevision=%s, updateRevision=%s", sts.Status.CurrentRevision, sts.Status.UpdateRevision)
+		}
 	}
 
 	if ready != desired {
@@ -70,3 +123,41 @@ func (w *StatefulSetWorkload) Stable() (isStable bool, reason string) {
 	// StatefulSets don't have an AvailableReplicas field, so we rely on ReadyReplicas.
 	return true, fmt.Sprintf("workload is stable: ready=%d, desired=%d", ready, desired)
 }
+
+// RollingOut checks if a rolling update of the StatefulSet is still replacing Pods.
+// Unlike Stable, it ignores the readiness of the Pods. OnDelete StatefulSets never roll out on their own.
+func (w *StatefulSetWorkload) RollingOut() (rolling bool, reason string) {
+	sts := w.StatefulSet
+
+	if sts.Status.ObservedGeneration < sts.Generation {
+		return true, fmt.Sprintf("rollout in progress: observedGeneration=%d, generation=%d", sts.Status.ObservedGeneration, sts.Generation)
+	}
+
+	if w.OnDeleteStrategy() {
+		return false, ""
+	}
+
+	// Partitioned StatefulSets keep their current revision, so only the replicas above the partition are compared.
+	if partition := w.partition(); partition > 0 {
+		expected := max(ptr.Deref(sts.Spec.Replicas, 1)-partition, 0)
+		if sts.Status.UpdatedReplicas < expected {
+			return true, fmt.Sprintf("not all replicas are updated: updated=%d, expected=%d, partition=%d", sts.Status.UpdatedReplicas, expected, partition)
+		}
+		return false, ""
+	}
+
+	if sts.Status.UpdateRevision != "" && sts.Status.CurrentRevision != sts.Status.UpdateRevision {
+		return true, fmt.Sprintf("revision rollout in progress: currentRevision=%s, updateRevision=%s", sts.Status.CurrentRevision, sts.Status.UpdateRevision)
+	}
+
+	return false, ""
+}
+
+// partition returns the partition of a rolling update, or zero if the StatefulSet is not partitioned.
+func (w *StatefulSetWorkload) partition() int32 {
+	ru := w.StatefulSet.Spec.UpdateStrategy.RollingUpdate
+	if ru == nil || ru.Partition == nil {
+		return 0
+	}
+	return *ru.Partition
+}
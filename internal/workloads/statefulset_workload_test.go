@@ -17,12 +17,14 @@ limitations under the License.
 package workloads
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/thurgauerkb/cascader/internal/kinds"
 	"github.com/thurgauerkb/cascader/test/testutils"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -213,3 +215,237 @@ func TestStatefulSetWorkload_IsStable(t *testing.T) {
 		assert.Equal(t, "scaled to zero replicas", msg)
 	})
 }
+
+func TestStatefulSetWorkload_StableStrategies(t *testing.T) {
+	t.Parallel()
+
+	rollingUpdate := func(partition int32) appsv1.StatefulSetUpdateStrategy {
+		return appsv1.StatefulSetUpdateStrategy{
+			Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: testutils.Int32Ptr(partition)},
+		}
+	}
+	onDelete := appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
+
+	tests := []struct {
+		name     string
+		strategy appsv1.StatefulSetUpdateStrategy
+		mode     OnDeleteMode
+		status   appsv1.StatefulSetStatus
+		stable   bool
+		reason   string
+	}{
+		{
+			name:     "Partitioned rollout completed",
+			strategy: rollingUpdate(2),
+			status:   appsv1.StatefulSetStatus{UpdatedReplicas: 2, ReadyReplicas: 4, CurrentRevision: "rev-1", UpdateRevision: "rev-2"},
+			stable:   true,
+			reason:   "workload is stable: ready=4, desired=4",
+		},
+		{
+			name:     "Partitioned rollout in progress",
+			strategy: rollingUpdate(2),
+			status:   appsv1.StatefulSetStatus{UpdatedReplicas: 1, ReadyReplicas: 4, CurrentRevision: "rev-1", UpdateRevision: "rev-2"},
+			stable:   false,
+			reason:   "not all replicas are updated: updated=1, ready=4, desired=4, partition=2",
+		},
+		{
+			name:     "Partition above replicas",
+			strategy: rollingUpdate(10),
+			status:   appsv1.StatefulSetStatus{UpdatedReplicas: 0, ReadyReplicas: 4, CurrentRevision: "rev-1", UpdateRevision: "rev-2"},
+			stable:   true,
+			reason:   "workload is stable: ready=4, desired=4",
+		},
+		{
+			name:     "Partitioned rollout with unready replica",
+			strategy: rollingUpdate(2),
+			status:   appsv1.StatefulSetStatus{UpdatedReplicas: 2, ReadyReplicas: 3, CurrentRevision: "rev-1", UpdateRevision: "rev-2"},
+			stable:   false,
+			reason:   "not enough ready replicas: ready=3, desired=4",
+		},
+		{
+			name:     "Revision not yet completed",
+			strategy: rollingUpdate(0),
+			status:   appsv1.StatefulSetStatus{UpdatedReplicas: 4, ReadyReplicas: 4, CurrentRevision: "rev-1", UpdateRevision: "rev-2"},
+			stable:   false,
+			reason:   "revision rollout in progress: currentRevision=rev-1, updateRevision=rev-2",
+		},
+		{
+			name:     "Revision completed",
+			strategy: rollingUpdate(0),
+			status:   appsv1.StatefulSetStatus{UpdatedReplicas: 4, ReadyReplicas: 4, CurrentRevision: "rev-2", UpdateRevision: "rev-2"},
+			stable:   true,
+			reason:   "workload is stable: ready=4, desired=4",
+		},
+		{
+			name:     "OnDelete immediate before Pods are replaced",
+			strategy: onDelete,
+			mode:     OnDeleteImmediate,
+			status:   appsv1.StatefulSetStatus{UpdatedReplicas: 0, ReadyReplicas: 4, CurrentRevision: "rev-1", UpdateRevision: "rev-2"},
+			stable:   true,
+			reason:   "workload is stable: ready=4, desired=4",
+		},
+		{
+			name:     "OnDelete defaults to wait",
+			strategy: onDelete,
+			status:   appsv1.StatefulSetStatus{UpdatedReplicas: 0, ReadyReplicas: 4, CurrentRevision: "rev-1", UpdateRevision: "rev-2"},
+			stable:   false,
+			reason:   "waiting for pods to be replaced: updated=0, ready=4, desired=4",
+		},
+		{
+			name:     "OnDelete immediate with unready replica",
+			strategy: onDelete,
+			mode:     OnDeleteImmediate,
+			status:   appsv1.StatefulSetStatus{UpdatedReplicas: 0, ReadyReplicas: 3},
+			stable:   false,
+			reason:   "not enough ready replicas: ready=3, desired=4",
+		},
+		{
+			name:     "OnDelete wait before Pods are replaced",
+			strategy: onDelete,
+			mode:     OnDeleteWait,
+			status:   appsv1.StatefulSetStatus{UpdatedReplicas: 1, ReadyReplicas: 4, CurrentRevision: "rev-1", UpdateRevision: "rev-2"},
+			stable:   false,
+			reason:   "waiting for pods to be replaced: updated=1, ready=4, desired=4",
+		},
+		{
+			name:     "OnDelete wait after Pods are replaced",
+			strategy: onDelete,
+			mode:     OnDeleteWait,
+			status:   appsv1.StatefulSetStatus{UpdatedReplicas: 4, ReadyReplicas: 4, CurrentRevision: "rev-1", UpdateRevision: "rev-2"},
+			stable:   true,
+			reason:   "workload is stable: ready=4, desired=4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			workload := StatefulSetWorkload{
+				StatefulSet: &appsv1.StatefulSet{
+					Spec: appsv1.StatefulSetSpec{
+						Replicas:       testutils.Int32Ptr(4),
+						UpdateStrategy: tt.strategy,
+					},
+					Status: tt.status,
+				},
+				OnDelete: tt.mode,
+			}
+			isStable, msg := workload.Stable()
+
+			assert.Equal(t, tt.stable, isStable)
+			assert.Equal(t, tt.reason, msg)
+		})
+	}
+}
+
+func TestStatefulSetWorkload_RollingOut(t *testing.T) {
+	t.Parallel()
+
+	rollingUpdate := func(partition int32) appsv1.StatefulSetUpdateStrategy {
+		return appsv1.StatefulSetUpdateStrategy{
+			Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: testutils.Int32Ptr(partition)},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		generation int64
+		strategy   appsv1.StatefulSetUpdateStrategy
+		status     appsv1.StatefulSetStatus
+		rolling    bool
+		reason     string
+	}{
+		{
+			name:     "Settled with unready replica",
+			strategy: rollingUpdate(0),
+			status:   appsv1.StatefulSetStatus{UpdatedReplicas: 4, ReadyReplicas: 1, CurrentRevision: "rev-2", UpdateRevision: "rev-2"},
+		},
+		{
+			name:       "Generation not observed",
+			generation: 2,
+			strategy:   rollingUpdate(0),
+			status:     appsv1.StatefulSetStatus{ObservedGeneration: 1},
+			rolling:    true,
+			reason:     "rollout in progress: observedGeneration=1, generation=2",
+		},
+		{
+			name:     "Revision rollout in progress",
+			strategy: rollingUpdate(0),
+			status:   appsv1.StatefulSetStatus{UpdatedReplicas: 2, CurrentRevision: "rev-1", UpdateRevision: "rev-2"},
+			rolling:  true,
+			reason:   "revision rollout in progress: currentRevision=rev-1, updateRevision=rev-2",
+		},
+		{
+			name:     "Partitioned rollout in progress",
+			strategy: rollingUpdate(2),
+			status:   appsv1.StatefulSetStatus{UpdatedReplicas: 1, CurrentRevision: "rev-1", UpdateRevision: "rev-2"},
+			rolling:  true,
+			reason:   "not all replicas are updated: updated=1, expected=2, partition=2",
+		},
+		{
+			name:     "Partitioned rollout completed",
+			strategy: rollingUpdate(2),
+			status:   appsv1.StatefulSetStatus{UpdatedReplicas: 2, CurrentRevision: "rev-1", UpdateRevision: "rev-2"},
+		},
+		{
+			name:     "OnDelete with Pods not replaced",
+			strategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType},
+			status:   appsv1.StatefulSetStatus{UpdatedReplicas: 0, CurrentRevision: "rev-1", UpdateRevision: "rev-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			workload := StatefulSetWorkload{
+				StatefulSet: &appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{Generation: tt.generation},
+					Spec: appsv1.StatefulSetSpec{
+						Replicas:       testutils.Int32Ptr(4),
+						UpdateStrategy: tt.strategy,
+					},
+					Status: tt.status,
+				},
+			}
+			rolling, reason := workload.RollingOut()
+
+			assert.Equal(t, tt.rolling, rolling)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestParseOnDeleteMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   string
+		want    OnDeleteMode
+		wantErr bool
+	}{
+		{value: "immediate", want: OnDeleteImmediate},
+		{value: " Wait ", want: OnDeleteWait},
+		{value: "SKIP", want: OnDeleteSkip},
+		{value: "never", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			mode, err := ParseOnDeleteMode(tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.EqualError(t, err, fmt.Sprintf("unsupported on-delete mode: %q", tt.value))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, mode)
+		})
+	}
+}
@@ -17,19 +17,40 @@ limitations under the License.
 package workloads
 
 import (
+	"fmt"
+
 	"github.com/thurgauerkb/cascader/internal/kinds"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // Workload defines the interface for Kubernetes workloads, providing methods for stability checks and metadata access.
 type Workload interface {
-	GetName() string                          // GetName returns the name of the workload.
-	GetNamespace() string                     // GetNamespace returns the namespace of the workload.
-	Resource() client.Object                  // Resource returns the underlying Kubernetes object.
-	Kind() kinds.Kind                         // Kind returns the kind of the workload.
-	ID() string                               // ID returns a unique identifier for the workload in the format Kind/namespace/name.
-	Stable() (isStable bool, reason string)   // Stable checks if the workload is in a stable state.
-	PodTemplateSpec() *corev1.PodTemplateSpec // PodTemplateSpec returns the PodTemplateSpec of the workload.
+	GetName() string                           // GetName returns the name of the workload.
+	GetNamespace() string                      // GetNamespace returns the namespace of the workload.
+	Resource() client.Object                   // Resource returns the underlying Kubernetes object.
+	Kind() kinds.Kind                          // Kind returns the kind of the workload.
+	ID() string                                // ID returns a unique identifier for the workload in the format Kind/namespace/name.
+	Stable() (isStable bool, reason string)    // Stable checks if the workload is in a stable state.
+	RollingOut() (rolling bool, reason string) // RollingOut checks if a rollout of the workload is still replacing Pods.
+	PodTemplateSpec() *corev1.PodTemplateSpec  // PodTemplateSpec returns the PodTemplateSpec of the workload.
+}
+
+// FromObject wraps a supported Kubernetes object into its Workload implementation.
+func FromObject(obj client.Object) (Workload, error) {
+	switch res := obj.(type) {
+	case *appsv1.Deployment:
+		return &DeploymentWorkload{Deployment: res}, nil
+	case *appsv1.StatefulSet:
+		return &StatefulSetWorkload{StatefulSet: res}, nil
+	case *appsv1.DaemonSet:
+		return &DaemonSetWorkload{DaemonSet: res}, nil
+	case *batchv1.Job:
+		return &JobWorkload{Job: res}, nil
+	default:
+		return nil, fmt.Errorf("unsupported workload type: %T", obj)
+	}
 }
@@ -0,0 +1,71 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloads
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestFromObject(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Deployment", func(t *testing.T) {
+		t.Parallel()
+
+		w, err := FromObject(&appsv1.Deployment{})
+		require.NoError(t, err)
+		assert.IsType(t, &DeploymentWorkload{}, w)
+	})
+
+	t.Run("StatefulSet", func(t *testing.T) {
+		t.Parallel()
+
+		w, err := FromObject(&appsv1.StatefulSet{})
+		require.NoError(t, err)
+		assert.IsType(t, &StatefulSetWorkload{}, w)
+	})
+
+	t.Run("DaemonSet", func(t *testing.T) {
+		t.Parallel()
+
+		w, err := FromObject(&appsv1.DaemonSet{})
+		require.NoError(t, err)
+		assert.IsType(t, &DaemonSetWorkload{}, w)
+	})
+
+	t.Run("Job", func(t *testing.T) {
+		t.Parallel()
+
+		w, err := FromObject(&batchv1.Job{})
+		require.NoError(t, err)
+		assert.IsType(t, &JobWorkload{}, w)
+	})
+
+	t.Run("Unsupported type", func(t *testing.T) {
+		t.Parallel()
+
+		w, err := FromObject(&corev1.Pod{})
+		require.Error(t, err)
+		assert.Nil(t, w)
+	})
+}
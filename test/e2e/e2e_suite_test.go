@@ -41,6 +41,7 @@ var (
 const (
 	successfullTriggerTargetMsg   string = "Successfully triggered reload"
 	restartDetectedMsg            string = "Restart detected, handling targets"
+	resumingCascadeMsg            string = "Resuming in-flight cascade"
 	deploymentAnnotation          string = "cascader.tkb.ch/deployment"
 	statefulSetAnnotation         string = "cascader.tkb.ch/statefulset"
 	daemonSetAnnotation           string = "cascader.tkb.ch/daemonset"
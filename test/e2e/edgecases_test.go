@@ -54,7 +54,7 @@ var _ = Describe("Edge cases", Serial, Ordered, func() {
 
 		By(fmt.Sprintf("validating cascader logs invalid annotation for %s", obj1ID))
 		testutils.ContainsLogs(
-			fmt.Sprintf("dependency cycle check failed: failed to fetch resource Deployment/%s", annotation),
+			fmt.Sprintf("targets not found: Deployment/%s", annotation),
 			1*time.Minute,
 			2*time.Second,
 		)
@@ -0,0 +1,102 @@
+/*
+Copyright 2026 Thurgauer Kantonalbank
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/thurgauerkb/cascader/test/testutils"
+
+	. "github.com/onsi/ginkgo/v2" // nolint:staticcheck
+)
+
+var _ = Describe("Cascade recovery", Serial, Ordered, func() {
+	var ns string
+
+	flags := []string{
+		"--leader-elect=false",
+		"--requeue-after-default=1s",
+		"--health-probe-bind-address=:4141",
+		"--metrics-enabled=false",
+	}
+
+	BeforeEach(func(ctx SpecContext) {
+		ns = testutils.NSManager.CreateNamespace(ctx)
+	})
+
+	AfterEach(func(ctx SpecContext) {
+		testutils.StopOperator()
+		testutils.NSManager.Cleanup(ctx)
+	})
+
+	It("Resumes a cascade interrupted by an operator restart", func(ctx SpecContext) {
+		testutils.StartOperatorWithFlags(flags)
+
+		obj1Name := testutils.GenerateUniqueName("dep1")
+		obj2Name := testutils.GenerateUniqueName("dep2")
+
+		// The startup probe keeps the source unstable long enough to kill the operator mid-cascade.
+		obj1 := testutils.CreateDeployment(
+			ctx,
+			ns,
+			obj1Name,
+			testutils.WithAnnotation(deploymentAnnotation, obj2Name),
+			testutils.WithStartupProbe(20),
+		)
+		obj1ID := testutils.GenerateID(obj1)
+
+		obj2 := testutils.CreateDeployment(
+			ctx,
+			ns,
+			obj2Name,
+		)
+		obj2ID := testutils.GenerateID(obj2)
+
+		testutils.RestartResource(ctx, obj1)
+
+		By(fmt.Sprintf("Detect restart of %s", obj1ID))
+		testutils.ContainsLogs(
+			fmt.Sprintf("%q,\"workloadID\":%q", restartDetectedMsg, obj1ID),
+			1*time.Minute,
+			1*time.Second,
+		)
+		testutils.ContainsNotLogs(
+			fmt.Sprintf("%q,\"workloadID\":%q,\"targetID\":%q", successfullTriggerTargetMsg, obj1ID, obj2ID),
+			2*time.Second,
+			1*time.Second,
+		)
+
+		By("Killing the operator mid-cascade")
+		testutils.StopOperator()
+		testutils.StartOperatorWithFlags(flags)
+
+		By(fmt.Sprintf("Resume cascade of %s", obj1ID))
+		testutils.ContainsLogs(
+			fmt.Sprintf("%q,\"workloadID\":%q", resumingCascadeMsg, obj1ID),
+			1*time.Minute,
+			1*time.Second,
+		)
+
+		By(fmt.Sprintf("Fetches restart of %s", obj2ID))
+		testutils.ContainsLogs(
+			fmt.Sprintf("%q,\"workloadID\":%q,\"targetID\":%q", successfullTriggerTargetMsg, obj1ID, obj2ID),
+			2*time.Minute,
+			2*time.Second,
+		)
+	})
+})